@@ -0,0 +1,97 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package buildinfo
+
+import (
+	sysBuildInfo "debug/buildinfo"
+	"io/fs"
+	"regexp"
+	"runtime/debug"
+
+	"github.com/pkg/errors"
+)
+
+// develVersion is the module version reported by binaries built
+// from a main module that is not a tagged release.
+const develVersion = "(devel)"
+
+// versionRegex matches the numeric part of a module version, e.g. "v1.2.3".
+var versionRegex = regexp.MustCompile(`^v(\d+)\.(\d+)\.(\d+)`)
+
+// pseudoVersionRegex matches the suffix of a pseudo-version,
+// e.g. "-20250101120000-abcdef123456".
+var pseudoVersionRegex = regexp.MustCompile(`\d{14}-[0-9a-f]{12}(\+dirty)?$`)
+
+// Info holds the build information embedded in a Go binary.
+type Info struct {
+	// ModulePath is the path of the main module, e.g. "github.com/user/app".
+	ModulePath string
+
+	// ModuleVersion is the version of the main module, e.g. "v1.2.3".
+	ModuleVersion string
+
+	// VCSRevision is the revision of the version control system
+	// the binary was built from, e.g. a git commit hash.
+	VCSRevision string
+
+	// VCSTime is the time of the VCSRevision, in RFC3339 format.
+	VCSTime string
+
+	// VCSModified indicates whether the binary was built from
+	// a working tree with uncommitted changes.
+	VCSModified bool
+}
+
+// Read reads the build information embedded in the Go binary at the given path.
+// It returns nil, without an error, if the file is not a Go binary.
+func Read(path string) (*Info, error) {
+	bi, err := sysBuildInfo.ReadFile(path)
+	if err != nil {
+		var pathErr *fs.PathError
+		if errors.As(err, &pathErr) {
+			return nil, errors.Wrapf(err, "error when reading build info from [%s]", path)
+		}
+		// not a Go binary, or a Go binary without build information.
+		return nil, nil
+	}
+	return fromBuildInfo(bi), nil
+}
+
+// fromBuildInfo converts the runtime build information into Info.
+func fromBuildInfo(bi *debug.BuildInfo) *Info {
+	info := &Info{
+		ModulePath:    bi.Main.Path,
+		ModuleVersion: bi.Main.Version,
+	}
+	for _, setting := range bi.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.VCSRevision = setting.Value
+		case "vcs.time":
+			info.VCSTime = setting.Value
+		case "vcs.modified":
+			info.VCSModified = setting.Value == "true"
+		}
+	}
+	return info
+}
+
+// Version returns the numeric part of the module version, e.g. "1.2.3" for
+// "v1.2.3-rc.1", which is suitable for CFBundleShortVersionString and CFBundleVersion.
+// It returns an empty string for development builds and pseudo-versions, since
+// they do not identify a release.
+func (i *Info) Version() string {
+	if i.ModuleVersion == "" || i.ModuleVersion == develVersion {
+		return ""
+	}
+	if pseudoVersionRegex.MatchString(i.ModuleVersion) {
+		return ""
+	}
+	matches := versionRegex.FindStringSubmatch(i.ModuleVersion)
+	if matches == nil {
+		return ""
+	}
+	return matches[1] + "." + matches[2] + "." + matches[3]
+}
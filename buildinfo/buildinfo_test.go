@@ -0,0 +1,79 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package buildinfo
+
+import (
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRead(t *testing.T) {
+	t.Run("go binary", func(t *testing.T) {
+		// the test binary itself is a Go binary with build information.
+		info, err := Read(os.Args[0])
+		require.NoError(t, err)
+		require.NotNil(t, info)
+	})
+
+	t.Run("not a go binary", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "script.sh")
+		require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\necho hello\n"), 0o755))
+		info, err := Read(path)
+		require.NoError(t, err)
+		require.Nil(t, info)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "missing")
+		info, err := Read(path)
+		require.Error(t, err)
+		require.Nil(t, info)
+	})
+}
+
+func Test_fromBuildInfo(t *testing.T) {
+	bi := &debug.BuildInfo{
+		Main: debug.Module{Path: "github.com/user/app", Version: "v1.2.3"},
+		Settings: []debug.BuildSetting{
+			{Key: "GOOS", Value: "darwin"},
+			{Key: "vcs.revision", Value: "0123456789abcdef0123456789abcdef01234567"},
+			{Key: "vcs.time", Value: "2025-06-01T10:00:00Z"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	}
+	require.Equal(t, &Info{
+		ModulePath:    "github.com/user/app",
+		ModuleVersion: "v1.2.3",
+		VCSRevision:   "0123456789abcdef0123456789abcdef01234567",
+		VCSTime:       "2025-06-01T10:00:00Z",
+		VCSModified:   true,
+	}, fromBuildInfo(bi))
+}
+
+func TestInfo_Version(t *testing.T) {
+	testCases := []struct {
+		name          string
+		moduleVersion string
+		want          string
+	}{
+		{name: "release", moduleVersion: "v1.2.3", want: "1.2.3"},
+		{name: "pre-release", moduleVersion: "v2.0.0-rc.1", want: "2.0.0"},
+		{name: "dirty release", moduleVersion: "v1.2.3+dirty", want: "1.2.3"},
+		{name: "pseudo-version", moduleVersion: "v0.0.0-20250601100000-0123456789ab", want: ""},
+		{name: "dirty pseudo-version", moduleVersion: "v1.2.4-0.20250601100000-0123456789ab+dirty", want: ""},
+		{name: "development build", moduleVersion: "(devel)", want: ""},
+		{name: "empty", moduleVersion: "", want: ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			info := &Info{ModuleVersion: tc.moduleVersion}
+			require.Equal(t, tc.want, info.Version())
+		})
+	}
+}
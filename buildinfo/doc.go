@@ -0,0 +1,3 @@
+// Package buildinfo provides functionality to read the
+// build information embedded in Go binaries.
+package buildinfo
@@ -0,0 +1,178 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package bundle
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tiagomelo/macos-dmg-creator/plist"
+)
+
+const (
+	// Extension is the extension of application bundles.
+	Extension = ".app"
+
+	// InfoPlistPath is the path to the Info.plist file inside an application bundle.
+	InfoPlistPath = "Contents/Info.plist"
+
+	// PkgInfoPath is the path to the PkgInfo file inside an application bundle.
+	PkgInfoPath = "Contents/PkgInfo"
+
+	// ExecutableDir is the directory of the executable inside an application bundle.
+	ExecutableDir = "Contents/MacOS"
+
+	// ApplicationPackageType is the CFBundlePackageType of applications.
+	ApplicationPackageType = "APPL"
+
+	// UnknownSignature is the CFBundleSignature of applications without a registered creator code.
+	UnknownSignature = "????"
+)
+
+// Info holds the information of a validated application bundle.
+type Info struct {
+	// Name is the name of the application: its CFBundleName,
+	// or the name of the bundle without its extension.
+	Name string
+
+	// Executable is the name of the executable in Contents/MacOS.
+	Executable string
+
+	// Identifier is the bundle identifier of the application.
+	Identifier string
+
+	// ShortVersion is the release version of the application, if declared.
+	ShortVersion string
+
+	// Version is the build version of the application, if declared.
+	Version string
+
+	// IconFile is the name of the icon file in Contents/Resources, if declared,
+	// with the .icns extension added when CFBundleIconFile has none.
+	IconFile string
+
+	// MinimumSystemVersion is the minimum macOS version required by the
+	// application, its LSMinimumSystemVersion, if declared.
+	MinimumSystemVersion string
+}
+
+// Validate checks that the directory at the given path is an application bundle
+// with a valid Info.plist file that declares an existing executable.
+func Validate(path string) (*Info, error) {
+	if filepath.Ext(path) != Extension {
+		return nil, errors.Errorf("[%s] is not an application bundle: its name does not end with %s", path, Extension)
+	}
+	stat, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.Errorf("[%s] does not exist", path)
+		}
+		return nil, errors.Wrapf(err, "error when checking if [%s] exists", path)
+	}
+	if !stat.IsDir() {
+		return nil, errors.Errorf("[%s] is not an application bundle: it is not a directory", path)
+	}
+
+	infoPlistPath := filepath.Join(path, InfoPlistPath)
+	dict, err := readInfoPlist(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &Info{}
+	for _, field := range []struct {
+		key      string
+		value    *string
+		required bool
+	}{
+		{key: "CFBundleExecutable", value: &info.Executable, required: true},
+		{key: "CFBundleIdentifier", value: &info.Identifier, required: true},
+		{key: "CFBundleName", value: &info.Name},
+		{key: "CFBundleShortVersionString", value: &info.ShortVersion},
+		{key: "CFBundleVersion", value: &info.Version},
+		{key: "CFBundleIconFile", value: &info.IconFile},
+		{key: "LSMinimumSystemVersion", value: &info.MinimumSystemVersion},
+	} {
+		if *field.value, err = stringValue(dict, field.key, infoPlistPath); err != nil {
+			return nil, err
+		}
+		if field.required && *field.value == "" {
+			return nil, errors.Errorf("[%s] does not declare %s", infoPlistPath, field.key)
+		}
+	}
+	packageType, err := stringValue(dict, "CFBundlePackageType", infoPlistPath)
+	if err != nil {
+		return nil, err
+	}
+	if packageType != "" && packageType != ApplicationPackageType {
+		return nil, errors.Errorf("[%s] declares CFBundlePackageType %s, expected %s", infoPlistPath, packageType, ApplicationPackageType)
+	}
+	if info.Name == "" {
+		info.Name = strings.TrimSuffix(filepath.Base(path), Extension)
+	}
+	if info.IconFile != "" && filepath.Ext(info.IconFile) == "" {
+		info.IconFile += iconExtension
+	}
+
+	if err := checkExecutable(filepath.Join(path, ExecutableDir, info.Executable)); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// readInfoPlist decodes the Info.plist file of the application bundle at the given path.
+func readInfoPlist(path string) (map[string]any, error) {
+	infoPlistPath := filepath.Join(path, InfoPlistPath)
+	if _, err := os.Stat(infoPlistPath); err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.Errorf("[%s] is not an application bundle: %s not found", path, InfoPlistPath)
+		}
+		return nil, errors.Wrapf(err, "error when checking if [%s] exists", infoPlistPath)
+	}
+	value, err := plist.DecodeFile(infoPlistPath)
+	if err != nil {
+		return nil, err
+	}
+	dict, ok := value.(map[string]any)
+	if !ok {
+		return nil, errors.Errorf("[%s] is not a dictionary", infoPlistPath)
+	}
+	return dict, nil
+}
+
+// stringValue returns the string stored under the given key,
+// or an empty string when the key is absent.
+func stringValue(dict map[string]any, key, infoPlistPath string) (string, error) {
+	value, ok := dict[key]
+	if !ok {
+		return "", nil
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", errors.Errorf("%s in [%s] is not a string", key, infoPlistPath)
+	}
+	return s, nil
+}
+
+// checkExecutable checks that the executable at the given path exists,
+// following symbolic links, and is a file that can be executed.
+func checkExecutable(path string) error {
+	stat, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return errors.Errorf("executable [%s] does not exist", path)
+		}
+		return errors.Wrapf(err, "error when checking if [%s] exists", path)
+	}
+	if !stat.Mode().IsRegular() {
+		return errors.Errorf("executable [%s] is not a file", path)
+	}
+	if stat.Mode().Perm()&0o111 == 0 {
+		return errors.Errorf("executable [%s] is not executable", path)
+	}
+	return nil
+}
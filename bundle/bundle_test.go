@@ -0,0 +1,216 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package bundle
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name          string
+		bundleName    string
+		setup         func(t *testing.T, bundlePath string)
+		expected      *Info
+		expectedError func(bundlePath string) string
+	}{
+		{
+			name:       "happy path",
+			bundleName: "MyApp.app",
+			setup: func(t *testing.T, bundlePath string) {
+				writeInfoPlist(t, bundlePath, map[string]string{
+					"CFBundleExecutable":         "myapp",
+					"CFBundleIdentifier":         "com.example.myapp",
+					"CFBundleName":               "My App",
+					"CFBundlePackageType":        "APPL",
+					"CFBundleShortVersionString": "1.2.3",
+					"CFBundleVersion":            "42",
+					"CFBundleIconFile":           "icon",
+					"LSMinimumSystemVersion":     "10.13",
+				})
+				writeExecutable(t, bundlePath, "myapp", 0o755)
+			},
+			expected: &Info{
+				Name:                 "My App",
+				Executable:           "myapp",
+				Identifier:           "com.example.myapp",
+				ShortVersion:         "1.2.3",
+				Version:              "42",
+				IconFile:             "icon.icns",
+				MinimumSystemVersion: "10.13",
+			},
+		},
+		{
+			name:       "name defaults to the bundle name and executable is a symlink",
+			bundleName: "MyApp.app",
+			setup: func(t *testing.T, bundlePath string) {
+				writeInfoPlist(t, bundlePath, map[string]string{
+					"CFBundleExecutable": "myapp",
+					"CFBundleIdentifier": "com.example.myapp",
+				})
+				writeExecutable(t, bundlePath, "myapp-real", 0o755)
+				require.NoError(t, os.Symlink("myapp-real", filepath.Join(bundlePath, ExecutableDir, "myapp")))
+			},
+			expected: &Info{
+				Name:       "MyApp",
+				Executable: "myapp",
+				Identifier: "com.example.myapp",
+			},
+		},
+		{
+			name:       "not a .app",
+			bundleName: "MyApp",
+			setup:      func(t *testing.T, bundlePath string) {},
+			expectedError: func(bundlePath string) string {
+				return fmt.Sprintf("[%s] is not an application bundle: its name does not end with .app", bundlePath)
+			},
+		},
+		{
+			name:       "does not exist",
+			bundleName: "MyApp.app",
+			setup: func(t *testing.T, bundlePath string) {
+				require.NoError(t, os.Remove(bundlePath))
+			},
+			expectedError: func(bundlePath string) string {
+				return fmt.Sprintf("[%s] does not exist", bundlePath)
+			},
+		},
+		{
+			name:       "not a directory",
+			bundleName: "MyApp.app",
+			setup: func(t *testing.T, bundlePath string) {
+				require.NoError(t, os.Remove(bundlePath))
+				require.NoError(t, os.WriteFile(bundlePath, nil, 0o644))
+			},
+			expectedError: func(bundlePath string) string {
+				return fmt.Sprintf("[%s] is not an application bundle: it is not a directory", bundlePath)
+			},
+		},
+		{
+			name:       "missing Info.plist",
+			bundleName: "MyApp.app",
+			setup:      func(t *testing.T, bundlePath string) {},
+			expectedError: func(bundlePath string) string {
+				return fmt.Sprintf("[%s] is not an application bundle: Contents/Info.plist not found", bundlePath)
+			},
+		},
+		{
+			name:       "invalid Info.plist",
+			bundleName: "MyApp.app",
+			setup: func(t *testing.T, bundlePath string) {
+				require.NoError(t, os.MkdirAll(filepath.Join(bundlePath, "Contents"), 0o755))
+				require.NoError(t, os.WriteFile(filepath.Join(bundlePath, InfoPlistPath), []byte("{}"), 0o644))
+			},
+			expectedError: func(bundlePath string) string {
+				return fmt.Sprintf("error when decoding [%s/Contents/Info.plist]: not a property list", bundlePath)
+			},
+		},
+		{
+			name:       "Info.plist is not a dictionary",
+			bundleName: "MyApp.app",
+			setup: func(t *testing.T, bundlePath string) {
+				require.NoError(t, os.MkdirAll(filepath.Join(bundlePath, "Contents"), 0o755))
+				require.NoError(t, os.WriteFile(filepath.Join(bundlePath, InfoPlistPath), []byte("<plist><array/></plist>"), 0o644))
+			},
+			expectedError: func(bundlePath string) string {
+				return fmt.Sprintf("[%s/Contents/Info.plist] is not a dictionary", bundlePath)
+			},
+		},
+		{
+			name:       "missing CFBundleIdentifier",
+			bundleName: "MyApp.app",
+			setup: func(t *testing.T, bundlePath string) {
+				writeInfoPlist(t, bundlePath, map[string]string{
+					"CFBundleExecutable": "myapp",
+				})
+			},
+			expectedError: func(bundlePath string) string {
+				return fmt.Sprintf("[%s/Contents/Info.plist] does not declare CFBundleIdentifier", bundlePath)
+			},
+		},
+		{
+			name:       "not an application",
+			bundleName: "MyApp.app",
+			setup: func(t *testing.T, bundlePath string) {
+				writeInfoPlist(t, bundlePath, map[string]string{
+					"CFBundleExecutable":  "myapp",
+					"CFBundleIdentifier":  "com.example.myapp",
+					"CFBundlePackageType": "FMWK",
+				})
+			},
+			expectedError: func(bundlePath string) string {
+				return fmt.Sprintf("[%s/Contents/Info.plist] declares CFBundlePackageType FMWK, expected APPL", bundlePath)
+			},
+		},
+		{
+			name:       "missing executable",
+			bundleName: "MyApp.app",
+			setup: func(t *testing.T, bundlePath string) {
+				writeInfoPlist(t, bundlePath, map[string]string{
+					"CFBundleExecutable": "myapp",
+					"CFBundleIdentifier": "com.example.myapp",
+				})
+			},
+			expectedError: func(bundlePath string) string {
+				return fmt.Sprintf("executable [%s/Contents/MacOS/myapp] does not exist", bundlePath)
+			},
+		},
+		{
+			name:       "executable without permission",
+			bundleName: "MyApp.app",
+			setup: func(t *testing.T, bundlePath string) {
+				writeInfoPlist(t, bundlePath, map[string]string{
+					"CFBundleExecutable": "myapp",
+					"CFBundleIdentifier": "com.example.myapp",
+				})
+				writeExecutable(t, bundlePath, "myapp", 0o644)
+			},
+			expectedError: func(bundlePath string) string {
+				return fmt.Sprintf("executable [%s/Contents/MacOS/myapp] is not executable", bundlePath)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bundlePath := filepath.Join(t.TempDir(), tc.bundleName)
+			require.NoError(t, os.Mkdir(bundlePath, 0o755))
+			tc.setup(t, bundlePath)
+			info, err := Validate(bundlePath)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError(bundlePath), err.Error())
+				return
+			}
+			if tc.expectedError != nil {
+				t.Fatalf(`expected error "%v", got nil`, tc.expectedError(bundlePath))
+			}
+			require.Equal(t, tc.expected, info)
+		})
+	}
+}
+
+func writeInfoPlist(t *testing.T, bundlePath string, entries map[string]string) {
+	t.Helper()
+	data := "<plist><dict>"
+	for key, value := range entries {
+		data += fmt.Sprintf("<key>%s</key><string>%s</string>", key, value)
+	}
+	data += "</dict></plist>"
+	require.NoError(t, os.MkdirAll(filepath.Join(bundlePath, "Contents"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(bundlePath, InfoPlistPath), []byte(data), 0o644))
+}
+
+func writeExecutable(t *testing.T, bundlePath, name string, perm os.FileMode) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Join(bundlePath, ExecutableDir), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(bundlePath, ExecutableDir, name), []byte("#!/bin/sh\n"), perm))
+}
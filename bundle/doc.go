@@ -0,0 +1,3 @@
+// Package bundle provides functionality to validate macOS application
+// bundles and to check their layout against Apple's structure rules.
+package bundle
@@ -0,0 +1,184 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package bundle
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// contentsDir is the only entry allowed at the top level of an application bundle.
+	contentsDir = "Contents"
+
+	// resourcesDir is the directory of the resources inside an application bundle.
+	resourcesDir = "Contents/Resources"
+
+	// iconExtension is the extension CFBundleIconFile defaults to when it has none.
+	iconExtension = ".icns"
+
+	// dirMode is the mode of the directories and executables of an application bundle.
+	dirMode fs.FileMode = 0o755
+
+	// dataMode is the mode of the data files of an application bundle.
+	dataMode fs.FileMode = 0o644
+)
+
+// contentsEntries are the entries Apple allows directly inside the Contents directory.
+var contentsEntries = []string{
+	"Info.plist",
+	"PkgInfo",
+	"MacOS",
+	"Resources",
+	"Frameworks",
+	"SharedFrameworks",
+	"SharedSupport",
+	"PlugIns",
+	"Library",
+	"XPCServices",
+	"Helpers",
+	"_CodeSignature",
+	"CodeResources",
+	"embedded.provisionprofile",
+}
+
+// NormalizeModes sets the mode of the directories and executables of the
+// application bundle at the given path to 0755 and of the other files to 0644.
+// Symbolic links are left untouched.
+func NormalizeModes(path string) error {
+	return filepath.WalkDir(path, func(entryPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return errors.Wrapf(err, "error when walking [%s]", entryPath)
+		}
+		if entry.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return errors.Wrapf(err, "error when reading mode of [%s]", entryPath)
+		}
+		mode := dataMode
+		if entry.IsDir() || info.Mode().Perm()&0o111 != 0 {
+			mode = dirMode
+		}
+		if err := os.Chmod(entryPath, mode); err != nil {
+			return errors.Wrapf(err, "error when setting mode of [%s]", entryPath)
+		}
+		return nil
+	})
+}
+
+// CheckLayout checks the application bundle at the given path against Apple's
+// structure rules: everything is inside Contents, which holds only the entries
+// Apple defines, Info.plist declares the executable and the identifier, PkgInfo
+// agrees with Info.plist, the executable and the icon exist and the directories
+// and files have the canonical modes.
+func CheckLayout(path string) error {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return errors.Wrapf(err, "error when reading directory [%s]", path)
+	}
+	for _, entry := range entries {
+		if entry.Name() != contentsDir {
+			return errors.Errorf("unexpected [%s] at the top level of [%s]: everything must be inside %s", entry.Name(), path, contentsDir)
+		}
+	}
+	contentsDirPath := filepath.Join(path, contentsDir)
+	entries, err = os.ReadDir(contentsDirPath)
+	if err != nil {
+		return errors.Wrapf(err, "error when reading directory [%s]", contentsDirPath)
+	}
+	for _, entry := range entries {
+		if !slices.Contains(contentsEntries, entry.Name()) {
+			return errors.Errorf("unexpected [%s] in [%s]", entry.Name(), contentsDirPath)
+		}
+	}
+
+	infoPlistPath := filepath.Join(path, InfoPlistPath)
+	dict, err := readInfoPlist(path)
+	if err != nil {
+		return err
+	}
+	values := map[string]string{}
+	for _, key := range []string{"CFBundleExecutable", "CFBundleIdentifier", "CFBundlePackageType", "CFBundleSignature", "CFBundleIconFile"} {
+		if values[key], err = stringValue(dict, key, infoPlistPath); err != nil {
+			return err
+		}
+	}
+	for _, key := range []string{"CFBundleExecutable", "CFBundleIdentifier"} {
+		if values[key] == "" {
+			return errors.Errorf("[%s] does not declare %s", infoPlistPath, key)
+		}
+	}
+
+	if err := checkPkgInfo(path, values["CFBundlePackageType"], values["CFBundleSignature"]); err != nil {
+		return err
+	}
+	if err := checkExecutable(filepath.Join(path, ExecutableDir, values["CFBundleExecutable"])); err != nil {
+		return err
+	}
+	if iconFile := values["CFBundleIconFile"]; iconFile != "" {
+		if filepath.Ext(iconFile) == "" {
+			iconFile += iconExtension
+		}
+		iconPath := filepath.Join(path, resourcesDir, iconFile)
+		if _, err := os.Stat(iconPath); err != nil {
+			return errors.Errorf("icon [%s] declared in [%s] does not exist", iconPath, infoPlistPath)
+		}
+	}
+	return checkModes(path)
+}
+
+// checkPkgInfo checks that the PkgInfo file of the application bundle holds
+// the package type followed by the signature declared in Info.plist.
+func checkPkgInfo(path, packageType, signature string) error {
+	pkgInfoPath := filepath.Join(path, PkgInfoPath)
+	data, err := os.ReadFile(pkgInfoPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return errors.Errorf("[%s] not found", pkgInfoPath)
+		}
+		return errors.Wrapf(err, "error when reading [%s]", pkgInfoPath)
+	}
+	if len(data) != 8 {
+		return errors.Errorf("[%s] has %d bytes, expected 8", pkgInfoPath, len(data))
+	}
+	if packageType != "" && string(data[:4]) != packageType {
+		return errors.Errorf("[%s] declares package type %s, but Info.plist declares %s", pkgInfoPath, data[:4], packageType)
+	}
+	if signature != "" && string(data[4:]) != signature {
+		return errors.Errorf("[%s] declares signature %s, but Info.plist declares %s", pkgInfoPath, data[4:], signature)
+	}
+	return nil
+}
+
+// checkModes checks that the directories and executables of the application
+// bundle have mode 0755 and the other files have mode 0644.
+func checkModes(path string) error {
+	return filepath.WalkDir(path, func(entryPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return errors.Wrapf(err, "error when walking [%s]", entryPath)
+		}
+		if entry.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return errors.Wrapf(err, "error when reading mode of [%s]", entryPath)
+		}
+		mode := info.Mode().Perm()
+		switch {
+		case entry.IsDir() && mode != dirMode:
+			return errors.Errorf("directory [%s] has mode %04o, expected %04o", entryPath, mode, dirMode)
+		case !entry.IsDir() && mode != dirMode && mode != dataMode:
+			return errors.Errorf("file [%s] has mode %04o, expected %04o or %04o", entryPath, mode, dirMode, dataMode)
+		}
+		return nil
+	})
+}
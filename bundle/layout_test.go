@@ -0,0 +1,191 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package bundle
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeModes(t *testing.T) {
+	bundlePath := filepath.Join(t.TempDir(), "MyApp.app")
+	writeInfoPlist(t, bundlePath, map[string]string{"CFBundleExecutable": "myapp"})
+	writeExecutable(t, bundlePath, "myapp", 0o700)
+	require.NoError(t, os.MkdirAll(filepath.Join(bundlePath, "Contents", "Frameworks"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(bundlePath, "Contents", "Frameworks", "libfoo.dylib"), nil, 0o444))
+	require.NoError(t, os.WriteFile(filepath.Join(bundlePath, "Contents", "PkgInfo"), nil, 0o666))
+	require.NoError(t, os.Symlink("libfoo.dylib", filepath.Join(bundlePath, "Contents", "Frameworks", "libfoo.1.dylib")))
+
+	require.NoError(t, NormalizeModes(bundlePath))
+
+	for path, want := range map[string]os.FileMode{
+		"":                                 0o755,
+		"Contents/Frameworks":              0o755,
+		"Contents/MacOS/myapp":             0o755,
+		"Contents/Frameworks/libfoo.dylib": 0o644,
+		"Contents/PkgInfo":                 0o644,
+		"Contents/Info.plist":              0o644,
+	} {
+		stat, err := os.Stat(filepath.Join(bundlePath, path))
+		require.NoError(t, err)
+		require.Equal(t, want, stat.Mode().Perm(), path)
+	}
+	stat, err := os.Lstat(filepath.Join(bundlePath, "Contents", "Frameworks", "libfoo.1.dylib"))
+	require.NoError(t, err)
+	require.Equal(t, os.ModeSymlink, stat.Mode().Type())
+}
+
+func TestCheckLayout(t *testing.T) {
+	testCases := []struct {
+		name          string
+		setup         func(t *testing.T, bundlePath string)
+		expectedError func(bundlePath string) string
+	}{
+		{
+			name: "happy path",
+			setup: func(t *testing.T, bundlePath string) {
+				writeCanonicalBundle(t, bundlePath)
+			},
+		},
+		{
+			name: "entry outside of Contents",
+			setup: func(t *testing.T, bundlePath string) {
+				writeCanonicalBundle(t, bundlePath)
+				require.NoError(t, os.WriteFile(filepath.Join(bundlePath, "README"), nil, 0o644))
+			},
+			expectedError: func(bundlePath string) string {
+				return fmt.Sprintf("unexpected [README] at the top level of [%s]: everything must be inside Contents", bundlePath)
+			},
+		},
+		{
+			name: "unexpected entry in Contents",
+			setup: func(t *testing.T, bundlePath string) {
+				writeCanonicalBundle(t, bundlePath)
+				require.NoError(t, os.Mkdir(filepath.Join(bundlePath, "Contents", "Assets"), 0o755))
+			},
+			expectedError: func(bundlePath string) string {
+				return fmt.Sprintf("unexpected [Assets] in [%s/Contents]", bundlePath)
+			},
+		},
+		{
+			name: "missing CFBundleIdentifier",
+			setup: func(t *testing.T, bundlePath string) {
+				writeCanonicalBundle(t, bundlePath)
+				writeInfoPlist(t, bundlePath, map[string]string{"CFBundleExecutable": "myapp"})
+			},
+			expectedError: func(bundlePath string) string {
+				return fmt.Sprintf("[%s/Contents/Info.plist] does not declare CFBundleIdentifier", bundlePath)
+			},
+		},
+		{
+			name: "missing PkgInfo",
+			setup: func(t *testing.T, bundlePath string) {
+				writeCanonicalBundle(t, bundlePath)
+				require.NoError(t, os.Remove(filepath.Join(bundlePath, PkgInfoPath)))
+			},
+			expectedError: func(bundlePath string) string {
+				return fmt.Sprintf("[%s/Contents/PkgInfo] not found", bundlePath)
+			},
+		},
+		{
+			name: "PkgInfo too short",
+			setup: func(t *testing.T, bundlePath string) {
+				writeCanonicalBundle(t, bundlePath)
+				require.NoError(t, os.WriteFile(filepath.Join(bundlePath, PkgInfoPath), []byte("APPL"), 0o644))
+			},
+			expectedError: func(bundlePath string) string {
+				return fmt.Sprintf("[%s/Contents/PkgInfo] has 4 bytes, expected 8", bundlePath)
+			},
+		},
+		{
+			name: "PkgInfo disagrees with Info.plist",
+			setup: func(t *testing.T, bundlePath string) {
+				writeCanonicalBundle(t, bundlePath)
+				require.NoError(t, os.WriteFile(filepath.Join(bundlePath, PkgInfoPath), []byte("APPLMYAP"), 0o644))
+			},
+			expectedError: func(bundlePath string) string {
+				return fmt.Sprintf("[%s/Contents/PkgInfo] declares signature MYAP, but Info.plist declares ????", bundlePath)
+			},
+		},
+		{
+			name: "missing icon",
+			setup: func(t *testing.T, bundlePath string) {
+				writeCanonicalBundle(t, bundlePath)
+				require.NoError(t, os.Remove(filepath.Join(bundlePath, "Contents", "Resources", "icon.icns")))
+			},
+			expectedError: func(bundlePath string) string {
+				return fmt.Sprintf("icon [%s/Contents/Resources/icon.icns] declared in [%s/Contents/Info.plist] does not exist", bundlePath, bundlePath)
+			},
+		},
+		{
+			name: "executable without permission",
+			setup: func(t *testing.T, bundlePath string) {
+				writeCanonicalBundle(t, bundlePath)
+				require.NoError(t, os.Chmod(filepath.Join(bundlePath, ExecutableDir, "myapp"), 0o644))
+			},
+			expectedError: func(bundlePath string) string {
+				return fmt.Sprintf("executable [%s/Contents/MacOS/myapp] is not executable", bundlePath)
+			},
+		},
+		{
+			name: "file writable by others",
+			setup: func(t *testing.T, bundlePath string) {
+				writeCanonicalBundle(t, bundlePath)
+				require.NoError(t, os.Chmod(filepath.Join(bundlePath, InfoPlistPath), 0o666))
+			},
+			expectedError: func(bundlePath string) string {
+				return fmt.Sprintf("file [%s/Contents/Info.plist] has mode 0666, expected 0755 or 0644", bundlePath)
+			},
+		},
+		{
+			name: "directory with wrong mode",
+			setup: func(t *testing.T, bundlePath string) {
+				writeCanonicalBundle(t, bundlePath)
+				require.NoError(t, os.Chmod(filepath.Join(bundlePath, "Contents", "Resources"), 0o700))
+			},
+			expectedError: func(bundlePath string) string {
+				return fmt.Sprintf("directory [%s/Contents/Resources] has mode 0700, expected 0755", bundlePath)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bundlePath := filepath.Join(t.TempDir(), "MyApp.app")
+			tc.setup(t, bundlePath)
+			err := CheckLayout(bundlePath)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError(bundlePath), err.Error())
+				return
+			}
+			if tc.expectedError != nil {
+				t.Fatalf(`expected error "%v", got nil`, tc.expectedError(bundlePath))
+			}
+		})
+	}
+}
+
+// writeCanonicalBundle writes an application bundle that follows Apple's structure rules.
+func writeCanonicalBundle(t *testing.T, bundlePath string) {
+	t.Helper()
+	writeInfoPlist(t, bundlePath, map[string]string{
+		"CFBundleExecutable":  "myapp",
+		"CFBundleIdentifier":  "com.example.myapp",
+		"CFBundleIconFile":    "icon",
+		"CFBundlePackageType": "APPL",
+		"CFBundleSignature":   "????",
+	})
+	writeExecutable(t, bundlePath, "myapp", 0o755)
+	require.NoError(t, os.WriteFile(filepath.Join(bundlePath, PkgInfoPath), []byte("APPL????"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(bundlePath, "Contents", "Resources"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(bundlePath, "Contents", "Resources", "icon.icns"), nil, 0o644))
+	require.NoError(t, NormalizeModes(bundlePath))
+}
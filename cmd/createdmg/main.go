@@ -5,38 +5,434 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/jessevdk/go-flags"
 	"github.com/tiagomelo/macos-dmg-creator/dmg"
+	"github.com/tiagomelo/macos-dmg-creator/dsstore"
+	"github.com/tiagomelo/macos-dmg-creator/lint"
+	"github.com/tiagomelo/macos-dmg-creator/verify"
 )
 
+// lintCommand is the command that checks existing bundles and DMGs instead of creating a DMG.
+const lintCommand = "lint"
+
+// verifyCommand is the command that verifies an existing DMG instead of creating one.
+const verifyCommand = "verify"
+
 // options defines the command line options for the program.
 type options struct {
-	AppName       string `long:"appName" description:"Application name" required:"true"`
-	AppBinaryPath string `long:"appBinaryPath" description:"Path to the application binary" required:"true"`
-	BundleID      string `long:"bundleIdentifier" description:"Bundle identifier for the application" required:"true"`
-	IconPath      string `long:"iconPath" description:"Path to the application icon" required:"true"`
-	OutputDir     string `long:"outputDir" description:"Directory to save the output DMG file" required:"true"`
+	AppName       string            `long:"appName" description:"Application name"`
+	AppBinaryPath []string          `long:"appBinaryPath" description:"Path to the application binary; repeat it to merge per-architecture binaries into a universal binary"`
+	SourcePath    string            `long:"sourcePath" description:"Path to a Go main package to build for darwin, instead of using --appBinaryPath"`
+	ScriptPath    string            `long:"scriptPath" description:"Path to a shell, Python or other script to use as the entry point, instead of --appBinaryPath; its support files go in with --resource"`
+	Terminal      bool              `long:"terminal" description:"Open --scriptPath in Terminal, for interactive command-line tools"`
+	Tool          bool              `long:"tool" description:"Package a command-line tool with install and uninstall scripts, instead of creating an application bundle"`
+	ManPages      []string          `long:"manPage" description:"Man page of --tool, whose extension is its section, e.g. mytool.1; can be repeated"`
+	BashCompl     string            `long:"bashCompletion" description:"Bash completion script of --tool"`
+	ZshCompl      string            `long:"zshCompletion" description:"Zsh completion script of --tool"`
+	FishCompl     string            `long:"fishCompletion" description:"Fish completion script of --tool"`
+	AppBundlePath string            `long:"appBundlePath" description:"Path to an existing .app bundle to package as is, instead of creating one"`
+	Arch          []string          `long:"arch" description:"Architecture to build --sourcePath for (arm64 or amd64); repeat it to build a universal binary"`
+	LDFlags       string            `long:"ldflags" description:"Flags passed to the Go linker when building --sourcePath"`
+	Tags          []string          `long:"tags" description:"Build tag used when building --sourcePath; can be repeated"`
+	CGO           bool              `long:"cgo" description:"Enable cgo when building --sourcePath"`
+	BundleID      string            `long:"bundleIdentifier" description:"Bundle identifier for the application"`
+	IconPath      string            `long:"iconPath" description:"Path to the application icon"`
+	OutputDir     string            `long:"outputDir" description:"Directory to save the output DMG file" required:"true"`
+	MinSysVersion string            `long:"minimumSystemVersion" description:"Minimum macOS version required by the application (defaults to the one declared by the binary)"`
+	ShortVersion  string            `long:"shortVersion" description:"Release version of the application, CFBundleShortVersionString (defaults to the Go module version of the binary)"`
+	BundleVersion string            `long:"bundleVersion" description:"Build version of the application, CFBundleVersion (defaults to the Go module version of the binary)"`
+	ExecName      string            `long:"executableName" description:"Name of the application binary inside Contents/MacOS, CFBundleExecutable (defaults to the application name)"`
+	PackageType   string            `long:"packageType" description:"Four-character package type of the application, CFBundlePackageType (defaults to APPL)"`
+	Signature     string            `long:"bundleSignature" description:"Four-character creator code of the application, CFBundleSignature (defaults to ????)"`
+	UsageDesc     map[string]string `long:"usageDescription" key-value-delimiter:"=" value-name:"KEY=TEXT" description:"Privacy usage description written to Info.plist, e.g. NSCameraUsageDescription=TEXT; can be repeated"`
+	DevRegion     string            `long:"developmentRegion" description:"Language the application is developed in, CFBundleDevelopmentRegion (defaults to en)"`
+	Localize      []string          `long:"localize" value-name:"LANG:KEY=TEXT" description:"Localized CFBundleName, CFBundleDisplayName or privacy usage description, written to Contents/Resources/LANG.lproj/InfoPlist.strings; can be repeated"`
+	Resources     []string          `long:"resource" value-name:"SOURCE[:TARGET]" description:"File, directory or glob pattern copied to Contents/Resources, optionally to TARGET inside it; can be repeated"`
+	Helpers       []string          `long:"helper" value-name:"SOURCE[:TARGET]" description:"Helper executable copied to Contents/MacOS, optionally to TARGET inside it; can be repeated"`
+	Frameworks    []string          `long:"framework" value-name:"SOURCE[:TARGET]" description:".framework or .dylib copied to Contents/Frameworks, optionally to TARGET inside it; can be repeated"`
+	PlugIns       []string          `long:"plugIn" value-name:"SOURCE[:TARGET]" description:"Plug-in copied to Contents/PlugIns, optionally to TARGET inside it; can be repeated"`
+	LaunchAgents  []string          `long:"launchAgent" value-name:"LABEL:PROGRAM[:runAtLoad,keepAlive]" description:"Launch agent written to Contents/Library/LaunchAgents/LABEL.plist, running PROGRAM, a path inside the bundle such as Contents/MacOS/myagent; can be repeated"`
+	LoginItems    []string          `long:"loginItem" value-name:"SOURCE[:TARGET]" description:"Helper .app bundle copied to Contents/Library/LoginItems, optionally to TARGET inside it; can be repeated"`
+	Window        string            `long:"window" value-name:"X,Y,WIDTH,HEIGHT" description:"Position and size of the Finder window the DMG opens in (defaults to a 640x400 window)"`
+	IconSize      int               `long:"iconSize" description:"Size of the icons in the Finder window, from 16 to 512 (defaults to 128)"`
+	TextSize      int               `long:"textSize" description:"Size of the icon labels in the Finder window, from 10 to 16 (defaults to 12)"`
+	IconPositions map[string]string `long:"iconPosition" key-value-delimiter:"=" value-name:"NAME=X,Y" description:"Position of the center of an icon in the Finder window, e.g. Applications=480,200; can be repeated"`
+	FinderLayout  bool              `long:"finderLayout" description:"Have Finder lay out the window with AppleScript (osascript) instead of writing .DS_Store directly; needs a logged-in session"`
+	Background    string            `long:"backgroundImage" description:"PNG, JPEG, GIF or TIFF image shown as the background of the Finder window, which is sized after it"`
+	Background2x  string            `long:"backgroundImage2x" description:"2x version of the background image, twice as large, shown on retina screens"`
+	VolumeIcon    string            `long:"volumeIcon" description:"Icon shown for the mounted DMG: an .icns file, or an image converted like --iconPath"`
+	AppVolIcon    bool              `long:"appIconAsVolumeIcon" description:"Use the icon of the application as the icon of the mounted DMG"`
+	Format        string            `long:"format" choice:"UDZO" choice:"UDBZ" choice:"ULFO" choice:"ULMO" choice:"UDRO" choice:"UDRW" description:"Format of the DMG: UDZO (zlib, default), UDBZ (bzip2), ULFO (LZFSE), ULMO (LZMA), UDRO (read-only) or UDRW (read-write)"`
+	ZlibLevel     int               `long:"zlibLevel" description:"Compression level of UDZO DMGs, from 1 (fastest) to 9 (smallest)"`
+	Filesystem    string            `long:"filesystem" choice:"APFS" choice:"Case-sensitive APFS" choice:"HFS+" choice:"Journaled HFS+" choice:"Case-sensitive HFS+" choice:"Case-sensitive Journaled HFS+" description:"Filesystem of the DMG (defaults to APFS, or to HFS+ when the application supports macOS 10.12 or earlier)"`
+	Layout        string            `long:"layout" choice:"GPTSPUD" choice:"SPUD" choice:"MBRSPUD" choice:"NONE" description:"Partition layout of the DMG: GPTSPUD (GUID, default), SPUD (Apple), MBRSPUD (master boot record) or NONE; APFS needs GPTSPUD or NONE"`
+	Licenses      []string          `long:"license" value-name:"LANG:PATH" description:"Software license agreement, plain text or RTF, users accept before the DMG mounts, e.g. en:LICENSE.txt; repeat it for other languages, the first being the default"`
+	Verify        bool              `long:"verify" description:"Verify the DMG once created: check its checksums, then attach it read-only to check the application, the Applications symlink and the Info.plist values"`
+	Staging       bool              `long:"staging" description:"Assemble the contents of the DMG in a staging folder and create the DMG from it in one step, without mounting it; faster and needs no mount privileges, but incompatible with --finderLayout, --backgroundImage, --volumeIcon and --appIconAsVolumeIcon"`
+	Encryption    string            `long:"encryption" choice:"AES-128" choice:"AES-256" description:"Encrypt the DMG, which then asks for a passphrase before it mounts; the passphrase is read with --passphraseStdin, --passphraseEnv or --passphraseFile"`
+	PassStdin     bool              `long:"passphraseStdin" description:"Read the passphrase of --encryption from the standard input"`
+	PassEnv       string            `long:"passphraseEnv" value-name:"NAME" description:"Read the passphrase of --encryption from the environment variable NAME"`
+	PassFile      string            `long:"passphraseFile" value-name:"PATH" description:"Read the passphrase of --encryption from the first line of the file at PATH"`
+	FyneApp       bool              `long:"fyneApp" description:"Fill the application name, bundle identifier, icon and versions not given on the command line from the FyneApp.toml next to the source or binary"`
+}
+
+// verifyOptions defines the command line options of the verify command.
+type verifyOptions struct {
+	App       string            `long:"app" value-name:"NAME.app" description:"Application bundle expected at the top level of the DMG, next to the Applications symlink"`
+	InfoPlist map[string]string `long:"infoPlist" key-value-delimiter:"=" value-name:"KEY=VALUE" description:"Value expected in the Info.plist of --app, e.g. CFBundleVersion=42; can be repeated"`
+	Files     []string          `long:"file" value-name:"NAME" description:"Other file or directory expected at the top level of the DMG, e.g. Install.command; can be repeated"`
 }
 
 func run(opts *options) error {
-	createdDMGPath, err := dmg.Create(&dmg.CreateParams{
-		AppName:          opts.AppName,
-		AppBinaryPath:    opts.AppBinaryPath,
-		BundleIdentifier: opts.BundleID,
-		IconPath:         opts.IconPath,
-		OutputDir:        opts.OutputDir,
-	})
+	localizations, err := localizations(opts.Localize)
+	if err != nil {
+		return err
+	}
+	launchAgents, err := launchAgents(opts.LaunchAgents)
+	if err != nil {
+		return err
+	}
+	window, err := windowLayout(opts)
+	if err != nil {
+		return err
+	}
+	licenses, err := licenses(opts.Licenses)
 	if err != nil {
 		return err
 	}
-	fmt.Println("\nDMG created successfully at:", createdDMGPath)
+	passphrase, err := passphrase(opts)
+	if err != nil {
+		return err
+	}
+	params := &dmg.CreateParams{
+		AppName:                opts.AppName,
+		BundleIdentifier:       opts.BundleID,
+		IconPath:               opts.IconPath,
+		OutputDir:              opts.OutputDir,
+		MinimumSystemVersion:   opts.MinSysVersion,
+		ShortVersion:           opts.ShortVersion,
+		BundleVersion:          opts.BundleVersion,
+		ExecutableName:         opts.ExecName,
+		PackageType:            opts.PackageType,
+		BundleSignature:        opts.Signature,
+		UsageDescriptions:      opts.UsageDesc,
+		DevelopmentRegion:      opts.DevRegion,
+		Localizations:          localizations,
+		SourcePath:             opts.SourcePath,
+		ScriptPath:             opts.ScriptPath,
+		RunInTerminal:          opts.Terminal,
+		CommandLineTool:        opts.Tool,
+		ManPages:               opts.ManPages,
+		BashCompletion:         opts.BashCompl,
+		ZshCompletion:          opts.ZshCompl,
+		FishCompletion:         opts.FishCompl,
+		AppBundlePath:          opts.AppBundlePath,
+		Architectures:          opts.Arch,
+		LDFlags:                opts.LDFlags,
+		BuildTags:              opts.Tags,
+		CGOEnabled:             opts.CGO,
+		Resources:              bundleEntries(opts.Resources),
+		Helpers:                bundleEntries(opts.Helpers),
+		Frameworks:             bundleEntries(opts.Frameworks),
+		PlugIns:                bundleEntries(opts.PlugIns),
+		LaunchAgents:           launchAgents,
+		LoginItems:             bundleEntries(opts.LoginItems),
+		Window:                 window,
+		BackgroundImagePath:    opts.Background,
+		BackgroundImage2xPath:  opts.Background2x,
+		VolumeIconPath:         opts.VolumeIcon,
+		UseAppIconAsVolumeIcon: opts.AppVolIcon,
+		Licenses:               licenses,
+		Format:                 opts.Format,
+		ZlibLevel:              opts.ZlibLevel,
+		Filesystem:             opts.Filesystem,
+		Layout:                 opts.Layout,
+		Verify:                 opts.Verify,
+		Staging:                opts.Staging,
+		Encryption:             opts.Encryption,
+		Passphrase:             passphrase,
+		UseFyneAppMetadata:     opts.FyneApp,
+	}
+	if len(opts.AppBinaryPath) == 1 {
+		params.AppBinaryPath = opts.AppBinaryPath[0]
+	} else {
+		params.AppBinaryPaths = opts.AppBinaryPath
+	}
+	result, err := dmg.Create(params)
+	if err != nil {
+		return err
+	}
+	fmt.Println("\nDMG created successfully at:", result.Path)
+	fmt.Printf("%s, %.1f MB", result.Format, float64(result.Size)/(1<<20))
+	if result.Compressed() {
+		fmt.Printf(", compression ratio %.2f:1", result.CompressionRatio())
+	}
+	fmt.Printf(", converted in %s\n", result.ConversionDuration.Round(time.Millisecond))
 	return nil
 }
 
+// localizations parses the localized values given as LANG:KEY=TEXT,
+// grouping them by language in the order the languages are first given.
+func localizations(values []string) ([]dmg.Localization, error) {
+	var localizations []dmg.Localization
+	index := map[string]int{}
+	for _, value := range values {
+		language, entry, ok := strings.Cut(value, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid --localize value [%s]: expected LANG:KEY=TEXT", value)
+		}
+		key, text, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --localize value [%s]: expected LANG:KEY=TEXT", value)
+		}
+		i, ok := index[language]
+		if !ok {
+			i = len(localizations)
+			index[language] = i
+			localizations = append(localizations, dmg.Localization{Language: language, Strings: map[string]string{}})
+		}
+		localizations[i].Strings[key] = text
+	}
+	return localizations, nil
+}
+
+// launchAgents parses the launch agents given as LABEL:PROGRAM[:OPTION,...],
+// where the options are runAtLoad and keepAlive.
+func launchAgents(values []string) ([]dmg.LaunchAgent, error) {
+	var launchAgents []dmg.LaunchAgent
+	for _, value := range values {
+		parts := strings.Split(value, ":")
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, fmt.Errorf("invalid --launchAgent value [%s]: expected LABEL:PROGRAM[:runAtLoad,keepAlive]", value)
+		}
+		launchAgent := dmg.LaunchAgent{Label: parts[0], Program: parts[1]}
+		if len(parts) == 3 {
+			for _, option := range strings.Split(parts[2], ",") {
+				switch option {
+				case "runAtLoad":
+					launchAgent.RunAtLoad = true
+				case "keepAlive":
+					launchAgent.KeepAlive = true
+				default:
+					return nil, fmt.Errorf("invalid --launchAgent option [%s]: expected runAtLoad or keepAlive", option)
+				}
+			}
+		}
+		launchAgents = append(launchAgents, launchAgent)
+	}
+	return launchAgents, nil
+}
+
+// windowLayout returns the layout of the Finder window given by the window
+// options, the window given as X,Y,WIDTH,HEIGHT and the icons as NAME=X,Y,
+// or nil when none is given.
+func windowLayout(opts *options) (*dmg.WindowLayout, error) {
+	if opts.Window == "" && opts.IconSize == 0 && opts.TextSize == 0 && len(opts.IconPositions) == 0 && !opts.FinderLayout {
+		return nil, nil
+	}
+	layout := &dmg.WindowLayout{IconSize: opts.IconSize, TextSize: opts.TextSize, UseFinder: opts.FinderLayout}
+	if opts.Window != "" {
+		values, err := integers(opts.Window, 4)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --window value [%s]: expected X,Y,WIDTH,HEIGHT", opts.Window)
+		}
+		layout.X, layout.Y, layout.Width, layout.Height = values[0], values[1], values[2], values[3]
+	}
+	for name, position := range opts.IconPositions {
+		values, err := integers(position, 2)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --iconPosition value [%s=%s]: expected NAME=X,Y", name, position)
+		}
+		if layout.IconPositions == nil {
+			layout.IconPositions = map[string]dsstore.Point{}
+		}
+		layout.IconPositions[name] = dsstore.Point{X: values[0], Y: values[1]}
+	}
+	return layout, nil
+}
+
+// integers parses the given number of comma-separated integers.
+func integers(value string, n int) ([]int, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != n {
+		return nil, fmt.Errorf("expected %d values, got %d", n, len(parts))
+	}
+	values := make([]int, n)
+	for i, part := range parts {
+		v, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// runLint checks the bundles and DMGs at the given paths, printing what it finds.
+// It reports whether no errors were found; warnings do not make it fail.
+func runLint(paths []string) (bool, error) {
+	if len(paths) == 0 {
+		return false, fmt.Errorf("usage: createdmg %s <path.app|path.dmg>...", lintCommand)
+	}
+	ok := true
+	for _, path := range paths {
+		findings, err := lint.Path(path)
+		if err != nil {
+			return false, err
+		}
+		for _, finding := range findings {
+			symbol := "⚠"
+			if finding.Severity == lint.Error {
+				symbol = "✘"
+				ok = false
+			}
+			fmt.Println(symbol, finding)
+		}
+		if len(findings) == 0 {
+			fmt.Printf("✔ no problems found in [%s]\n", path)
+		}
+	}
+	return ok, nil
+}
+
+// runVerify verifies the DMG given in the arguments against the expected contents
+// given as options, printing each mismatch it finds. It reports whether the DMG
+// passed verification.
+func runVerify(args []string) (bool, error) {
+	var opts verifyOptions
+	parser := flags.NewParser(&opts, flags.HelpFlag|flags.PassDoubleDash)
+	parser.Usage = verifyCommand + " [OPTIONS] <path.dmg>"
+	paths, err := parser.ParseArgs(args)
+	if err != nil {
+		return false, err
+	}
+	if len(paths) != 1 {
+		return false, fmt.Errorf("usage: createdmg %s [OPTIONS] <path.dmg>", verifyCommand)
+	}
+	if len(opts.InfoPlist) > 0 && opts.App == "" {
+		return false, errors.New("--infoPlist needs --app")
+	}
+	err = verify.DMG(paths[0], &verify.Expected{AppBundleName: opts.App, InfoPlist: opts.InfoPlist, Files: opts.Files})
+	var verr *verify.VerificationError
+	if errors.As(err, &verr) {
+		for _, mismatch := range verr.Mismatches {
+			fmt.Println("✘", mismatch)
+		}
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	fmt.Printf("✔ [%s] verified\n", paths[0])
+	return true, nil
+}
+
+// licenses parses the license agreements given as LANG:PATH.
+func licenses(values []string) ([]dmg.License, error) {
+	var licenses []dmg.License
+	for _, value := range values {
+		language, path, ok := strings.Cut(value, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid --license value [%s]: expected LANG:PATH", value)
+		}
+		licenses = append(licenses, dmg.License{Language: language, Path: path})
+	}
+	return licenses, nil
+}
+
+// passphrase reads the passphrase of --encryption from the one source it is given in:
+// the standard input, an environment variable or a file. Only its first line is used,
+// so that a trailing newline is not part of it. The passphrase itself is never part
+// of the returned errors.
+func passphrase(opts *options) (string, error) {
+	sources := 0
+	for _, given := range []bool{opts.PassStdin, opts.PassEnv != "", opts.PassFile != ""} {
+		if given {
+			sources++
+		}
+	}
+	if opts.Encryption == "" {
+		if sources > 0 {
+			return "", errors.New("--passphraseStdin, --passphraseEnv and --passphraseFile only apply with --encryption")
+		}
+		return "", nil
+	}
+	if sources != 1 {
+		return "", errors.New("--encryption needs exactly one of --passphraseStdin, --passphraseEnv or --passphraseFile")
+	}
+	var value string
+	switch {
+	case opts.PassStdin:
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("error when reading the passphrase from the standard input: %w", err)
+		}
+		value = string(data)
+	case opts.PassEnv != "":
+		var ok bool
+		if value, ok = os.LookupEnv(opts.PassEnv); !ok {
+			return "", fmt.Errorf("error when reading the passphrase: environment variable [%s] is not set", opts.PassEnv)
+		}
+	default:
+		data, err := os.ReadFile(opts.PassFile)
+		if err != nil {
+			return "", fmt.Errorf("error when reading the passphrase from [%s]: %w", opts.PassFile, err)
+		}
+		value = string(data)
+	}
+	value, _, _ = strings.Cut(value, "\n")
+	value = strings.TrimSuffix(value, "\r")
+	if value == "" {
+		return "", errors.New("the passphrase of --encryption is empty")
+	}
+	return value, nil
+}
+
+// bundleEntries parses the bundle entries given as SOURCE[:TARGET].
+func bundleEntries(values []string) []dmg.BundleEntry {
+	var entries []dmg.BundleEntry
+	for _, value := range values {
+		source, target, _ := strings.Cut(value, ":")
+		entries = append(entries, dmg.BundleEntry{Source: source, Target: target})
+	}
+	return entries
+}
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == lintCommand {
+		ok, err := runLint(os.Args[2:])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if !ok {
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == verifyCommand {
+		ok, err := runVerify(os.Args[2:])
+		if err != nil {
+			fmt.Println(err)
+			if flags.WroteHelp(err) {
+				os.Exit(0)
+			}
+			os.Exit(1)
+		}
+		if !ok {
+			os.Exit(1)
+		}
+		return
+	}
+
 	var opts options
 	parser := flags.NewParser(&opts, flags.Default)
 	if _, err := parser.Parse(); err != nil {
@@ -0,0 +1,37 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package codesign
+
+import (
+	"github.com/pkg/errors"
+	"github.com/tiagomelo/macos-dmg-creator/syscall"
+)
+
+// osCommandExecutorProvider is a variable that holds the function
+// that executes a command with arguments.
+var osCommandExecutorProvider osCommandExecutor = &defaultOsCommandExecutor{}
+
+// osCommandExecutor defines an interface for executing OS commands.
+type osCommandExecutor interface {
+	ExecCommand(name string, arg ...string) (string, error)
+}
+
+// defaultOsCommandExecutor is the default implementation of osCommandExecutor.
+type defaultOsCommandExecutor struct{}
+
+// ExecCommand executes a command with arguments.
+func (d *defaultOsCommandExecutor) ExecCommand(name string, arg ...string) (string, error) {
+	return syscall.ExecCommand(name, arg...)
+}
+
+// SignAdHoc signs the binary at the given path with an ad hoc signature,
+// replacing its existing signature. Binaries for Apple Silicon must be
+// signed to run, so this is needed after their load commands change.
+func SignAdHoc(path string) error {
+	if _, err := osCommandExecutorProvider.ExecCommand("codesign", "--force", "--sign", "-", path); err != nil {
+		return errors.Wrapf(err, "error when signing [%s]", path)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package codesign
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAdHoc(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		mockOsCommandExecutor func() *mockOsCommandExecutor
+		expectedError         error
+	}{
+		{
+			name: "happy path",
+			mockOsCommandExecutor: func() *mockOsCommandExecutor {
+				return &mockOsCommandExecutor{}
+			},
+		},
+		{
+			name: "error",
+			mockOsCommandExecutor: func() *mockOsCommandExecutor {
+				return &mockOsCommandExecutor{
+					err: errors.New("some error"),
+				}
+			},
+			expectedError: errors.New("error when signing [MyApp.app/Contents/MacOS/myapp]: some error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockOsCommandExecutor := tc.mockOsCommandExecutor()
+			osCommandExecutorProvider = mockOsCommandExecutor
+			err := SignAdHoc("MyApp.app/Contents/MacOS/myapp")
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				if tc.expectedError.Error() != err.Error() {
+					t.Fatalf(`expected error "%v", got "%v"`, tc.expectedError, err)
+				}
+				return
+			}
+			if tc.expectedError != nil {
+				t.Fatalf(`expected error "%v", got nil`, tc.expectedError)
+			}
+			require.Equal(t, []string{"codesign", "--force", "--sign", "-", "MyApp.app/Contents/MacOS/myapp"}, mockOsCommandExecutor.cmd)
+		})
+	}
+}
+
+type mockOsCommandExecutor struct {
+	err error
+	cmd []string
+}
+
+func (m *mockOsCommandExecutor) ExecCommand(name string, arg ...string) (string, error) {
+	m.cmd = append([]string{name}, arg...)
+	return "", m.err
+}
@@ -0,0 +1,2 @@
+// Package codesign provides a Go interface to the macOS codesign command-line tool.
+package codesign
@@ -0,0 +1,105 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// volumeIconFile is the file at the root of a volume holding its custom icon.
+	volumeIconFile = ".VolumeIcon.icns"
+
+	// volumeIconDir is the directory of the temporary working directory
+	// where the volume icon is converted to .icns.
+	volumeIconDir = "volumeicon"
+)
+
+// volumeAppearance is how the mounted DMG looks in Finder:
+// the layout of its window, its background image and its volume icon.
+type volumeAppearance struct {
+	// layout is the layout of the window, nil for the default one.
+	layout *WindowLayout
+
+	// background is the staged background image of the window, if any.
+	background *backgroundImage
+
+	// volumeIconPath is the path of the .icns file used as the volume icon, if any.
+	volumeIconPath string
+
+	// filesystem is the filesystem of the volume, recorded in the alias of the background image.
+	filesystem string
+}
+
+// prepareAppearance stages the background image and the volume icon
+// of the DMG in the temporary working directory.
+func prepareAppearance(params *CreateParams, tmpWorkDir string) (*volumeAppearance, error) {
+	background, err := prepareBackgroundImage(params, tmpWorkDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when preparing background image")
+	}
+	volumeIconPath, err := prepareVolumeIcon(params.VolumeIconPath, tmpWorkDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when preparing volume icon")
+	}
+	return &volumeAppearance{
+		layout:         params.Window,
+		background:     background,
+		volumeIconPath: volumeIconPath,
+	}, nil
+}
+
+// prepareVolumeIcon returns the path of the .icns file used as the volume icon.
+// Images other than .icns files are converted like the application icon.
+// It returns an empty path if no icon is given.
+func prepareVolumeIcon(iconPath, tmpWorkDir string) (string, error) {
+	if iconPath == "" || strings.EqualFold(filepath.Ext(iconPath), ".icns") {
+		return iconPath, nil
+	}
+	volumeIconDirPath := filepath.Join(tmpWorkDir, volumeIconDir)
+	iconSetDirPath := filepath.Join(volumeIconDirPath, iconSetDir)
+	if err := sipsUtilityProvider.GenerateIcons(iconPath, iconSetDirPath, iconSizes...); err != nil {
+		return "", errors.Wrap(err, "error when generating icons")
+	}
+	if err := iconUtilProvider.GenerateIconSet(iconSetDirPath, volumeIconDirPath); err != nil {
+		return "", errors.Wrap(err, "error when generating icon set")
+	}
+	return filepath.Join(volumeIconDirPath, iconFile), nil
+}
+
+// applyAppearance copies the background image and the volume icon to the mounted
+// DMG template and lays out its window, which shows the given items.
+func applyAppearance(appearance *volumeAppearance, items []string, mountedDmgTemplatePath string) error {
+	if appearance.background != nil {
+		if err := copyBackgroundImage(appearance.background, mountedDmgTemplatePath); err != nil {
+			return err
+		}
+	}
+	if appearance.volumeIconPath != "" {
+		if err := setVolumeIcon(appearance.volumeIconPath, mountedDmgTemplatePath); err != nil {
+			return err
+		}
+	}
+	if err := layoutWindow(appearance.layout, appearance.background, appearance.filesystem, items, mountedDmgTemplatePath); err != nil {
+		return errors.Wrap(err, "error when laying out DMG window")
+	}
+	return nil
+}
+
+// setVolumeIcon copies the icon to the root of the mounted DMG template
+// and sets the custom icon Finder flag of the volume, so Finder shows it.
+func setVolumeIcon(iconPath, mountedDmgTemplatePath string) error {
+	volumeIconPath := filepath.Join(mountedDmgTemplatePath, volumeIconFile)
+	if err := fsOpsProvider.CopyFile(iconPath, volumeIconPath); err != nil {
+		return errors.Wrapf(err, "error when copying volume icon to [%s]", volumeIconPath)
+	}
+	if err := xattrProvider.SetCustomIcon(mountedDmgTemplatePath); err != nil {
+		return errors.Wrapf(err, "error when setting the custom icon flag of [%s]", mountedDmgTemplatePath)
+	}
+	return nil
+}
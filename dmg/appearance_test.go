@@ -0,0 +1,149 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import (
+	"os"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_prepareVolumeIcon(t *testing.T) {
+	testCases := []struct {
+		name                    string
+		iconPath                string
+		mockSipsUtilityProvider *mockSipsUtilityProvider
+		mockIconUtilProvider    *mockIconUtilProvider
+		want                    string
+		wantErr                 error
+	}{
+		{
+			name:                    "no volume icon",
+			mockSipsUtilityProvider: &mockSipsUtilityProvider{},
+			mockIconUtilProvider:    &mockIconUtilProvider{},
+		},
+		{
+			name:                    ".icns file",
+			iconPath:                "icons/volume.ICNS",
+			mockSipsUtilityProvider: &mockSipsUtilityProvider{expectedGenerateIconsErr: errors.New("not called")},
+			mockIconUtilProvider:    &mockIconUtilProvider{},
+			want:                    "icons/volume.ICNS",
+		},
+		{
+			name:                    "image converted to .icns",
+			iconPath:                "icons/volume.png",
+			mockSipsUtilityProvider: &mockSipsUtilityProvider{},
+			mockIconUtilProvider:    &mockIconUtilProvider{},
+			want:                    "tmp/volumeicon/icon.icns",
+		},
+		{
+			name:                    "error generating icons",
+			iconPath:                "icons/volume.png",
+			mockSipsUtilityProvider: &mockSipsUtilityProvider{expectedGenerateIconsErr: errors.New("some error")},
+			mockIconUtilProvider:    &mockIconUtilProvider{},
+			wantErr:                 errors.New("error when generating icons: some error"),
+		},
+		{
+			name:                    "error generating icon set",
+			iconPath:                "icons/volume.png",
+			mockSipsUtilityProvider: &mockSipsUtilityProvider{},
+			mockIconUtilProvider:    &mockIconUtilProvider{expectedGenerateIconSetErr: errors.New("some error")},
+			wantErr:                 errors.New("error when generating icon set: some error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sipsUtilityProvider = tc.mockSipsUtilityProvider
+			iconUtilProvider = tc.mockIconUtilProvider
+
+			got, err := prepareVolumeIcon(tc.iconPath, "tmp")
+			if tc.wantErr != nil {
+				require.EqualError(t, err, tc.wantErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func Test_applyAppearance(t *testing.T) {
+	testCases := []struct {
+		name                string
+		appearance          *volumeAppearance
+		mockFsOpsProvider   *mockFsOpsProvider
+		mockXattrProvider   *mockXattrProvider
+		wantCopiedPaths     []string
+		wantCustomIconPaths []string
+		wantErr             error
+	}{
+		{
+			name:              "default appearance",
+			appearance:        &volumeAppearance{},
+			mockFsOpsProvider: &mockFsOpsProvider{},
+			mockXattrProvider: &mockXattrProvider{},
+		},
+		{
+			name: "background image and volume icon",
+			appearance: &volumeAppearance{
+				background:     &backgroundImage{dirPath: "tmp/.background", fileName: "background.png", width: 660, height: 400},
+				volumeIconPath: "build/MyApp.app/Contents/Resources/icon.icns",
+			},
+			mockFsOpsProvider: &mockFsOpsProvider{},
+			mockXattrProvider: &mockXattrProvider{},
+			wantCopiedPaths: []string{
+				"tmp/.background/ -> /Volumes/MyApp",
+				"build/MyApp.app/Contents/Resources/icon.icns -> /Volumes/MyApp/.VolumeIcon.icns",
+			},
+			wantCustomIconPaths: []string{"/Volumes/MyApp"},
+		},
+		{
+			name:              "error copying background image",
+			appearance:        &volumeAppearance{background: &backgroundImage{dirPath: "tmp/.background", fileName: "background.png"}},
+			mockFsOpsProvider: &mockFsOpsProvider{expectedCopyDirErr: os.ErrPermission},
+			mockXattrProvider: &mockXattrProvider{},
+			wantErr:           errors.New("error when copying background image to mounted DMG template at [/Volumes/MyApp]: permission denied"),
+		},
+		{
+			name:              "error copying volume icon",
+			appearance:        &volumeAppearance{volumeIconPath: "volume.icns"},
+			mockFsOpsProvider: &mockFsOpsProvider{expectedCopyFileErr: os.ErrPermission},
+			mockXattrProvider: &mockXattrProvider{},
+			wantErr:           errors.New("error when copying volume icon to [/Volumes/MyApp/.VolumeIcon.icns]: permission denied"),
+		},
+		{
+			name:              "error setting the custom icon flag",
+			appearance:        &volumeAppearance{volumeIconPath: "volume.icns"},
+			mockFsOpsProvider: &mockFsOpsProvider{},
+			mockXattrProvider: &mockXattrProvider{expectedSetCustomIconErr: os.ErrPermission},
+			wantErr:           errors.New("error when setting the custom icon flag of [/Volumes/MyApp]: permission denied"),
+		},
+		{
+			name:              "error laying out window",
+			appearance:        &volumeAppearance{},
+			mockFsOpsProvider: &mockFsOpsProvider{expectedWriteFileErr: os.ErrPermission},
+			mockXattrProvider: &mockXattrProvider{},
+			wantErr:           errors.New("error when laying out DMG window: error when writing .DS_Store file to [/Volumes/MyApp/.DS_Store]: permission denied"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fsOpsProvider = tc.mockFsOpsProvider
+			xattrProvider = tc.mockXattrProvider
+
+			err := applyAppearance(tc.appearance, []string{"MyApp.app", "Applications"}, "/Volumes/MyApp")
+			if tc.wantErr != nil {
+				require.EqualError(t, err, tc.wantErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantCopiedPaths, tc.mockFsOpsProvider.copiedPaths)
+			require.Equal(t, tc.wantCustomIconPaths, tc.mockXattrProvider.customIconPaths)
+			require.Contains(t, tc.mockFsOpsProvider.writtenFiles, "/Volumes/MyApp/.DS_Store")
+		})
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import (
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/tiagomelo/macos-dmg-creator/dsstore"
+)
+
+const (
+	// backgroundDir is the hidden directory of the DMG holding the background image.
+	backgroundDir = ".background"
+
+	// backgroundFileName is the name of the background image, without extension.
+	backgroundFileName = "background"
+)
+
+// backgroundImage is the background image of the Finder window,
+// staged in the temporary working directory.
+type backgroundImage struct {
+	// dirPath is the path of the staged .background directory.
+	dirPath string
+
+	// fileName is the name of the image inside the .background directory.
+	fileName string
+
+	// width and height are the size of the image, in points.
+	width, height int
+}
+
+// prepareBackgroundImage stages the background image in the temporary working directory.
+// When a 2x image is given, both images are combined into a multi-resolution TIFF file,
+// so the background is sharp on retina screens. It returns nil if no image is given.
+func prepareBackgroundImage(params *CreateParams, tmpWorkDir string) (*backgroundImage, error) {
+	if params.BackgroundImagePath == "" {
+		return nil, nil
+	}
+	width, height, err := imageProvider.Size(params.BackgroundImagePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when reading the size of the background image")
+	}
+	background := &backgroundImage{
+		dirPath: filepath.Join(tmpWorkDir, backgroundDir),
+		width:   width,
+		height:  height,
+	}
+	if err := fsOpsProvider.MkdirAll(background.dirPath, bundleDirMode); err != nil {
+		return nil, errors.Wrapf(err, "error when creating directory [%s]", background.dirPath)
+	}
+	if params.BackgroundImage2xPath != "" {
+		background.fileName = backgroundFileName + ".tiff"
+		imagePath := filepath.Join(background.dirPath, background.fileName)
+		if err := imageProvider.CombineTIFF(imagePath, params.BackgroundImagePath, params.BackgroundImage2xPath); err != nil {
+			return nil, errors.Wrap(err, "error when combining the background images")
+		}
+		return background, nil
+	}
+	background.fileName = backgroundFileName + filepath.Ext(params.BackgroundImagePath)
+	imagePath := filepath.Join(background.dirPath, background.fileName)
+	if err := fsOpsProvider.CopyFile(params.BackgroundImagePath, imagePath); err != nil {
+		return nil, errors.Wrapf(err, "error when copying background image to [%s]", imagePath)
+	}
+	return background, nil
+}
+
+// copyBackgroundImage copies the staged .background directory to the mounted DMG template.
+func copyBackgroundImage(background *backgroundImage, mountedDmgTemplatePath string) error {
+	if err := fsOpsProvider.CopyDir(background.dirPath, mountedDmgTemplatePath); err != nil {
+		return errors.Wrapf(err, "error when copying background image to mounted DMG template at [%s]", mountedDmgTemplatePath)
+	}
+	return nil
+}
+
+// backgroundAlias returns the alias Finder uses to find the background
+// image once copied to the mounted DMG template, of the given filesystem.
+func backgroundAlias(background *backgroundImage, filesystem, mountedDmgTemplatePath string) (*dsstore.Alias, error) {
+	dirPath := filepath.Join(mountedDmgTemplatePath, backgroundDir)
+	parentID, err := fsOpsProvider.FileID(dirPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when reading the identifier of [%s]", dirPath)
+	}
+	imagePath := filepath.Join(dirPath, background.fileName)
+	fileID, err := fsOpsProvider.FileID(imagePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when reading the identifier of [%s]", imagePath)
+	}
+	return &dsstore.Alias{
+		VolumeName: filepath.Base(mountedDmgTemplatePath),
+		Filesystem: filesystem,
+		Path:       backgroundDir + "/" + background.fileName,
+		ParentID:   uint32(parentID),
+		FileID:     uint32(fileID),
+	}, nil
+}
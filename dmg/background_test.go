@@ -0,0 +1,184 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import (
+	"os"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/macos-dmg-creator/dsstore"
+	"github.com/tiagomelo/macos-dmg-creator/plist"
+)
+
+func Test_prepareBackgroundImage(t *testing.T) {
+	testCases := []struct {
+		name               string
+		params             *CreateParams
+		mockFsOpsProvider  *mockFsOpsProvider
+		mockImageProvider  *mockImageProvider
+		want               *backgroundImage
+		wantCopiedPaths    []string
+		wantCombinedImages []string
+		wantErr            error
+	}{
+		{
+			name:              "no background image",
+			params:            &CreateParams{},
+			mockFsOpsProvider: &mockFsOpsProvider{},
+			mockImageProvider: &mockImageProvider{},
+		},
+		{
+			name:              "background image",
+			params:            &CreateParams{BackgroundImagePath: "images/background.png"},
+			mockFsOpsProvider: &mockFsOpsProvider{},
+			mockImageProvider: &mockImageProvider{expectedWidth: 660, expectedHeight: 400},
+			want: &backgroundImage{
+				dirPath:  "tmp/.background",
+				fileName: "background.png",
+				width:    660,
+				height:   400,
+			},
+			wantCopiedPaths: []string{"images/background.png -> tmp/.background/background.png"},
+		},
+		{
+			name: "1x and 2x background images",
+			params: &CreateParams{
+				BackgroundImagePath:   "images/background.png",
+				BackgroundImage2xPath: "images/background@2x.png",
+			},
+			mockFsOpsProvider: &mockFsOpsProvider{},
+			mockImageProvider: &mockImageProvider{expectedWidth: 660, expectedHeight: 400},
+			want: &backgroundImage{
+				dirPath:  "tmp/.background",
+				fileName: "background.tiff",
+				width:    660,
+				height:   400,
+			},
+			wantCombinedImages: []string{"images/background.png + images/background@2x.png -> tmp/.background/background.tiff"},
+		},
+		{
+			name:              "error reading the size of the background image",
+			params:            &CreateParams{BackgroundImagePath: "images/background.png"},
+			mockFsOpsProvider: &mockFsOpsProvider{},
+			mockImageProvider: &mockImageProvider{expectedSizeErr: errors.New("image: unknown format")},
+			wantErr:           errors.New("error when reading the size of the background image: image: unknown format"),
+		},
+		{
+			name:              "error creating the .background directory",
+			params:            &CreateParams{BackgroundImagePath: "images/background.png"},
+			mockFsOpsProvider: &mockFsOpsProvider{expectedMkdirAllErr: os.ErrPermission},
+			mockImageProvider: &mockImageProvider{},
+			wantErr:           errors.New("error when creating directory [tmp/.background]: permission denied"),
+		},
+		{
+			name: "error combining the background images",
+			params: &CreateParams{
+				BackgroundImagePath:   "images/background.png",
+				BackgroundImage2xPath: "images/background@2x.png",
+			},
+			mockFsOpsProvider: &mockFsOpsProvider{},
+			mockImageProvider: &mockImageProvider{expectedCombineTIFFErr: errors.New("some error")},
+			wantErr:           errors.New("error when combining the background images: some error"),
+		},
+		{
+			name:              "error copying the background image",
+			params:            &CreateParams{BackgroundImagePath: "images/background.png"},
+			mockFsOpsProvider: &mockFsOpsProvider{expectedCopyFileErr: os.ErrPermission},
+			mockImageProvider: &mockImageProvider{},
+			wantErr:           errors.New("error when copying background image to [tmp/.background/background.png]: permission denied"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fsOpsProvider = tc.mockFsOpsProvider
+			imageProvider = tc.mockImageProvider
+
+			got, err := prepareBackgroundImage(tc.params, "tmp")
+			if tc.wantErr != nil {
+				require.EqualError(t, err, tc.wantErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+			require.Equal(t, tc.wantCopiedPaths, tc.mockFsOpsProvider.copiedPaths)
+			require.Equal(t, tc.wantCombinedImages, tc.mockImageProvider.combinedImages)
+		})
+	}
+}
+
+func Test_layoutWindowWithBackground(t *testing.T) {
+	background := &backgroundImage{dirPath: "tmp/.background", fileName: "background.png", width: 660, height: 420}
+	testCases := []struct {
+		name              string
+		layout            *WindowLayout
+		filesystem        string
+		mockFsOpsProvider *mockFsOpsProvider
+		wantBounds        string
+		wantErr           error
+	}{
+		{
+			name:       "window sized after the background image",
+			filesystem: "APFS",
+			mockFsOpsProvider: &mockFsOpsProvider{
+				fileIDs: map[string]uint64{
+					"/Volumes/MyApp/.background":                17,
+					"/Volumes/MyApp/.background/background.png": 18,
+				},
+			},
+			wantBounds: "{{0, 0}, {660, 420}}",
+		},
+		{
+			name:              "window sized by the layout",
+			layout:            &WindowLayout{X: 10, Y: 20, Width: 800},
+			filesystem:        "Journaled HFS+",
+			mockFsOpsProvider: &mockFsOpsProvider{},
+			wantBounds:        "{{10, 20}, {800, 420}}",
+		},
+		{
+			name:              "error reading the identifier of the background image",
+			mockFsOpsProvider: &mockFsOpsProvider{expectedFileIDErr: os.ErrNotExist},
+			wantErr:           errors.New("error when reading the identifier of [/Volumes/MyApp/.background]: file does not exist"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fsOpsProvider = tc.mockFsOpsProvider
+
+			err := layoutWindow(tc.layout, background, tc.filesystem, []string{"MyApp.app", "Applications"}, "/Volumes/MyApp")
+			if tc.wantErr != nil {
+				require.EqualError(t, err, tc.wantErr.Error())
+				return
+			}
+			require.NoError(t, err)
+
+			records, err := dsstore.Decode(tc.mockFsOpsProvider.writtenFiles["/Volumes/MyApp/.DS_Store"])
+			require.NoError(t, err)
+			values := map[string]any{}
+			for _, record := range records {
+				if record.Name == "." {
+					values[record.Code] = record.Value
+				}
+			}
+			bwsp, err := plist.Decode(values["bwsp"].([]byte))
+			require.NoError(t, err)
+			require.Equal(t, tc.wantBounds, bwsp.(map[string]any)["WindowBounds"])
+
+			wantAlias, err := (&dsstore.Alias{
+				VolumeName: "MyApp",
+				Filesystem: tc.filesystem,
+				Path:       ".background/background.png",
+				ParentID:   uint32(tc.mockFsOpsProvider.fileIDs["/Volumes/MyApp/.background"]),
+				FileID:     uint32(tc.mockFsOpsProvider.fileIDs["/Volumes/MyApp/.background/background.png"]),
+			}).Encode()
+			require.NoError(t, err)
+			icvp, err := plist.Decode(values["icvp"].([]byte))
+			require.NoError(t, err)
+			require.Equal(t, int64(2), icvp.(map[string]any)["backgroundType"])
+			require.Equal(t, wantAlias, icvp.(map[string]any)["backgroundImageAlias"])
+		})
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import "github.com/tiagomelo/macos-dmg-creator/buildinfo"
+
+// buildInfoProvider is a variable that holds the function
+// that reads the build information embedded in Go binaries.
+var buildInfoProvider buildInfoOps = defaultBuildInfo{}
+
+// buildInfoOps defines an interface for reading the build information of Go binaries.
+type buildInfoOps interface {
+	// Read reads the build information embedded in the Go binary at the given path.
+	Read(path string) (*buildinfo.Info, error)
+}
+
+// defaultBuildInfo is the default implementation of buildInfoOps.
+type defaultBuildInfo struct{}
+
+func (d defaultBuildInfo) Read(path string) (*buildinfo.Info, error) {
+	return buildinfo.Read(path)
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import "github.com/tiagomelo/macos-dmg-creator/bundle"
+
+// bundleProvider is a variable that holds the function
+// that validates application bundles.
+var bundleProvider bundleOps = defaultBundle{}
+
+// bundleOps defines an interface for validating application bundles.
+type bundleOps interface {
+	// Validate checks that the directory at the given path is a valid application bundle.
+	Validate(path string) (*bundle.Info, error)
+
+	// NormalizeModes sets the canonical modes of the directories and files of the application bundle.
+	NormalizeModes(path string) error
+
+	// CheckLayout checks the application bundle against Apple's structure rules.
+	CheckLayout(path string) error
+}
+
+// defaultBundle is the default implementation of bundleOps.
+type defaultBundle struct{}
+
+func (d defaultBundle) Validate(path string) (*bundle.Info, error) {
+	return bundle.Validate(path)
+}
+
+func (d defaultBundle) NormalizeModes(path string) error {
+	return bundle.NormalizeModes(path)
+}
+
+func (d defaultBundle) CheckLayout(path string) error {
+	return bundle.CheckLayout(path)
+}
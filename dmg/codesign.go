@@ -0,0 +1,24 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import "github.com/tiagomelo/macos-dmg-creator/codesign"
+
+// codesignProvider is a variable that holds the function
+// that signs binaries.
+var codesignProvider codesignOps = defaultCodesign{}
+
+// codesignOps defines an interface for signing binaries.
+type codesignOps interface {
+	// SignAdHoc signs the binary at the given path with an ad hoc signature.
+	SignAdHoc(path string) error
+}
+
+// defaultCodesign is the default implementation of codesignOps.
+type defaultCodesign struct{}
+
+func (d defaultCodesign) SignAdHoc(path string) error {
+	return codesign.SignAdHoc(path)
+}
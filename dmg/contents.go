@@ -0,0 +1,133 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tiagomelo/macos-dmg-creator/bundle"
+)
+
+const (
+	frameworksDir = "Contents/Frameworks"
+	plugInsDir    = "Contents/PlugIns"
+)
+
+// frameworkExtensions are the extensions of the content
+// that can be copied to the Frameworks directory.
+var frameworkExtensions = []string{".framework", ".dylib"}
+
+// loginItemExtensions are the extensions of the content
+// that can be copied to the LoginItems directory.
+var loginItemExtensions = []string{bundle.Extension}
+
+// BundleEntry declares extra content copied into the application bundle.
+type BundleEntry struct {
+	// Source is the path to the file or directory to copy. It may be a glob
+	// pattern, e.g. "assets/*.png", in which case every match is copied.
+	Source string `validate:"required"`
+
+	// Target is the path, relative to the bundle directory the content goes to,
+	// where the content is copied. When Source is a glob pattern or Target ends
+	// with a slash, Target is a directory where the matches keep their names.
+	// When empty, the content is copied keeping its name.
+	Target string
+}
+
+// bundleContent is the extra content copied into a directory of the application bundle.
+type bundleContent struct {
+	// dir is the directory of the application bundle the content is copied to.
+	dir string
+
+	// entries are the declared entries.
+	entries []BundleEntry
+
+	// extensions are the allowed extensions of the copied files and directories.
+	// Any extension is allowed when empty.
+	extensions []string
+}
+
+// bundleContents returns the extra content declared in the parameters,
+// including the script used as the entry point of the application.
+func bundleContents(params *CreateParams) []bundleContent {
+	resources := params.Resources
+	if params.ScriptPath != "" {
+		resources = append([]BundleEntry{{Source: escapeGlobPattern(params.ScriptPath)}}, resources...)
+	}
+	return []bundleContent{
+		{dir: resourcesDir, entries: resources},
+		{dir: macOsDir, entries: params.Helpers},
+		{dir: frameworksDir, entries: params.Frameworks, extensions: frameworkExtensions},
+		{dir: plugInsDir, entries: params.PlugIns},
+		{dir: loginItemsDir, entries: params.LoginItems, extensions: loginItemExtensions},
+	}
+}
+
+// copyBundleContents copies the extra content into the application bundle.
+func copyBundleContents(contents []bundleContent, appBundleDirPath string) error {
+	for _, content := range contents {
+		for _, entry := range content.entries {
+			if err := copyBundleEntry(entry, content, appBundleDirPath); err != nil {
+				return errors.Wrapf(err, "error when copying [%s] to %s", entry.Source, content.dir)
+			}
+		}
+	}
+	return nil
+}
+
+// copyBundleEntry copies the files and directories matching
+// the entry into the given directory of the application bundle.
+func copyBundleEntry(entry BundleEntry, content bundleContent, appBundleDirPath string) error {
+	if entry.Target != "" && !filepath.IsLocal(entry.Target) {
+		return errors.Errorf("target [%s] is not a relative path inside %s", entry.Target, content.dir)
+	}
+	matches, err := fsOpsProvider.Glob(entry.Source)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		return errors.Errorf("no files match [%s]", entry.Source)
+	}
+	contentDirPath := filepath.Join(appBundleDirPath, content.dir)
+	targetIsDir := entry.Target == "" || isGlobPattern(entry.Source) || strings.HasSuffix(entry.Target, "/")
+	for _, match := range matches {
+		if len(content.extensions) > 0 && !slices.Contains(content.extensions, filepath.Ext(match)) {
+			return errors.Errorf("[%s] is not a %s", match, strings.Join(content.extensions, " or "))
+		}
+		dst := filepath.Join(contentDirPath, entry.Target)
+		if targetIsDir {
+			dst = filepath.Join(dst, filepath.Base(match))
+		}
+		if err := fsOpsProvider.MkdirAll(filepath.Dir(dst), bundleDirMode); err != nil {
+			return errors.Wrapf(err, "error when creating directory [%s]", filepath.Dir(dst))
+		}
+		isDir, err := fsOpsProvider.DirExists(match)
+		if err != nil {
+			return err
+		}
+		if isDir {
+			err = fsOpsProvider.CopyDir(match, dst)
+		} else {
+			err = fsOpsProvider.CopyFile(match, dst)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isGlobPattern reports whether the path contains glob meta characters.
+func isGlobPattern(path string) bool {
+	return strings.ContainsAny(path, `*?[\`)
+}
+
+// escapeGlobPattern escapes the glob meta characters of the path, so it only matches itself.
+func escapeGlobPattern(path string) string {
+	return strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`).Replace(path)
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import (
+	"os"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_copyBundleContents(t *testing.T) {
+	testCases := []struct {
+		name              string
+		params            *CreateParams
+		mockFsOpsProvider func() *mockFsOpsProvider
+		wantCopiedPaths   []string
+		wantErr           error
+	}{
+		{
+			name: "happy path",
+			params: &CreateParams{
+				Resources: []BundleEntry{
+					{Source: "assets/config.json"},
+					{Source: "assets/*.png", Target: "images"},
+					{Source: "assets/data", Target: "db"},
+				},
+				Helpers: []BundleEntry{
+					{Source: "bin/helper", Target: "myapp-helper"},
+				},
+				Frameworks: []BundleEntry{
+					{Source: "lib/*.dylib"},
+					{Source: "lib/Foo.framework", Target: "Versioned/"},
+				},
+				PlugIns: []BundleEntry{
+					{Source: "plugins/Share.appex"},
+				},
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					expectedGlobMatches: map[string][]string{
+						"assets/config.json":  {"assets/config.json"},
+						"assets/*.png":        {"assets/a.png", "assets/b.png"},
+						"assets/data":         {"assets/data"},
+						"bin/helper":          {"bin/helper"},
+						"lib/*.dylib":         {"lib/libfoo.dylib"},
+						"lib/Foo.framework":   {"lib/Foo.framework"},
+						"plugins/Share.appex": {"plugins/Share.appex"},
+					},
+					existingDirs: map[string]bool{
+						"assets/data":         true,
+						"lib/Foo.framework":   true,
+						"plugins/Share.appex": true,
+					},
+				}
+			},
+			wantCopiedPaths: []string{
+				"assets/config.json -> MyApp.app/Contents/Resources/config.json",
+				"assets/a.png -> MyApp.app/Contents/Resources/images/a.png",
+				"assets/b.png -> MyApp.app/Contents/Resources/images/b.png",
+				"assets/data/ -> MyApp.app/Contents/Resources/db",
+				"bin/helper -> MyApp.app/Contents/MacOS/myapp-helper",
+				"lib/libfoo.dylib -> MyApp.app/Contents/Frameworks/libfoo.dylib",
+				"lib/Foo.framework/ -> MyApp.app/Contents/Frameworks/Versioned/Foo.framework",
+				"plugins/Share.appex/ -> MyApp.app/Contents/PlugIns/Share.appex",
+			},
+		},
+		{
+			name: "target outside of the directory",
+			params: &CreateParams{
+				Resources: []BundleEntry{{Source: "assets/config.json", Target: "../config.json"}},
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			wantErr: errors.New("error when copying [assets/config.json] to Contents/Resources: target [../config.json] is not a relative path inside Contents/Resources"),
+		},
+		{
+			name: "no matches",
+			params: &CreateParams{
+				PlugIns: []BundleEntry{{Source: "plugins/*.appex"}},
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			wantErr: errors.New("error when copying [plugins/*.appex] to Contents/PlugIns: no files match [plugins/*.appex]"),
+		},
+		{
+			name: "not a framework",
+			params: &CreateParams{
+				Frameworks: []BundleEntry{{Source: "lib/*"}},
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					expectedGlobMatches: map[string][]string{
+						"lib/*": {"lib/libfoo.dylib", "lib/README.md"},
+					},
+				}
+			},
+			wantErr: errors.New("error when copying [lib/*] to Contents/Frameworks: [lib/README.md] is not a .framework or .dylib"),
+		},
+		{
+			name: "error when matching pattern",
+			params: &CreateParams{
+				Resources: []BundleEntry{{Source: "assets/[a"}},
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					expectedGlobErr: errors.New("syntax error in pattern"),
+				}
+			},
+			wantErr: errors.New("error when copying [assets/[a] to Contents/Resources: syntax error in pattern"),
+		},
+		{
+			name: "error when creating directory",
+			params: &CreateParams{
+				Resources: []BundleEntry{{Source: "assets/config.json"}},
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					expectedGlobMatches: map[string][]string{
+						"assets/config.json": {"assets/config.json"},
+					},
+					expectedMkdirAllErr: os.ErrPermission,
+				}
+			},
+			wantErr: errors.New("error when copying [assets/config.json] to Contents/Resources: error when creating directory [MyApp.app/Contents/Resources]: permission denied"),
+		},
+		{
+			name: "error when copying",
+			params: &CreateParams{
+				Helpers: []BundleEntry{{Source: "bin/helper"}},
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					expectedGlobMatches: map[string][]string{
+						"bin/helper": {"bin/helper"},
+					},
+					expectedCopyFileErr: os.ErrPermission,
+				}
+			},
+			wantErr: errors.New("error when copying [bin/helper] to Contents/MacOS: permission denied"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockFsOpsProvider := tc.mockFsOpsProvider()
+			fsOpsProvider = mockFsOpsProvider
+
+			err := copyBundleContents(bundleContents(tc.params), "MyApp.app")
+			if err != nil {
+				if tc.wantErr == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				if tc.wantErr.Error() != err.Error() {
+					t.Fatalf(`expected error "%v", got "%v"`, tc.wantErr, err)
+				}
+				return
+			}
+			if tc.wantErr != nil {
+				t.Fatalf(`expected error "%v", got nil`, tc.wantErr)
+			}
+			require.Equal(t, tc.wantCopiedPaths, mockFsOpsProvider.copiedPaths)
+		})
+	}
+}
+
+func Test_bundleContents(t *testing.T) {
+	got := bundleContents(&CreateParams{
+		ScriptPath: "scripts/run[1].sh",
+		Resources:  []BundleEntry{{Source: "scripts/lib/*.sh", Target: "lib"}},
+	})
+	require.Equal(t, []BundleEntry{
+		{Source: `scripts/run\[1].sh`},
+		{Source: "scripts/lib/*.sh", Target: "lib"},
+	}, got[0].entries)
+}
@@ -6,13 +6,23 @@ package dmg
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/briandowns/spinner"
 	"github.com/pkg/errors"
+	"github.com/tiagomelo/macos-dmg-creator/buildinfo"
+	"github.com/tiagomelo/macos-dmg-creator/bundle"
+	"github.com/tiagomelo/macos-dmg-creator/fyneapp"
+	"github.com/tiagomelo/macos-dmg-creator/gobuild"
+	"github.com/tiagomelo/macos-dmg-creator/hdiutil"
+	"github.com/tiagomelo/macos-dmg-creator/macho"
+	"github.com/tiagomelo/macos-dmg-creator/plist"
 	"github.com/tiagomelo/macos-dmg-creator/validate"
 )
 
@@ -21,43 +31,375 @@ const (
 	macOsDir     = "Contents/MacOS"
 	resourcesDir = "Contents/Resources"
 	iconSetDir   = "icon.iconset"
+	buildDir     = "build"
 )
 
+const (
+	// bundleDirMode is the mode of the directories and executables of the application bundle.
+	bundleDirMode os.FileMode = 0o755
+
+	// bundleDataMode is the mode of the data files of the application bundle.
+	bundleDataMode os.FileMode = 0o644
+)
+
+// iconSizes are the sizes, in pixels, of the images of the generated .icns files.
+var iconSizes = []int{16, 32, 64, 128, 256, 512, 1024}
+
+// warningOutput is the writer where warnings are printed to.
+var warningOutput io.Writer = os.Stdout
+
+// defaultArchitecture is the architecture the application
+// is built for when building it from source.
+const defaultArchitecture = "arm64"
+
 // CreateParams is the input parameters for the Create function.
 type CreateParams struct {
 	// AppName is the name of the application.
-	AppName string `validate:"required"`
+	AppName string `validate:"required_without=AppBundlePath,excluded_with=AppBundlePath"`
 
 	// AppBinaryPath is the path to the application binary.
-	AppBinaryPath string `validate:"required"`
+	AppBinaryPath string `validate:"required_without_all=AppBinaryPaths SourcePath AppBundlePath ScriptPath,excluded_with=AppBinaryPaths SourcePath AppBundlePath ScriptPath"`
+
+	// AppBinaryPaths are the paths to the application binaries built for different
+	// architectures, e.g. darwin/arm64 and darwin/amd64. They are merged into
+	// a universal binary. It is an alternative to AppBinaryPath.
+	AppBinaryPaths []string `validate:"omitempty,min=2,excluded_with=SourcePath AppBundlePath ScriptPath,dive,required"`
+
+	// SourcePath is the directory of a Go main package that is built for darwin
+	// and used as the application binary. It is an alternative to AppBinaryPath.
+	SourcePath string `validate:"excluded_with=AppBundlePath ScriptPath"`
+
+	// ScriptPath is the path to a shell, Python or other script used as the entry point
+	// of the application. It is an alternative to AppBinaryPath. The script is copied to
+	// Contents/Resources, along with its support files given in Resources, and a launcher
+	// that runs it becomes the bundle executable.
+	ScriptPath string `validate:"excluded_with=AppBundlePath CommandLineTool"`
+
+	// RunInTerminal indicates whether the launcher opens the script in Terminal,
+	// for interactive command-line tools, instead of running it in the background.
+	RunInTerminal bool `validate:"excluded_without=ScriptPath"`
+
+	// AppBundlePath is the path to an existing application bundle (.app), such as one
+	// built with Xcode, Wails or fyne package. It is validated and copied into the DMG
+	// as is, so the parameters used to create a new bundle cannot be given with it.
+	AppBundlePath string
+
+	// CommandLineTool indicates whether the DMG holds a command-line tool instead of an
+	// application bundle. The binary is shipped with its man pages, its shell completions
+	// and an Install.command script that copies them into /usr/local, along with an
+	// Uninstall.command script that removes them. No .app nor /Applications symlink is created.
+	CommandLineTool bool `validate:"excluded_with=AppBundlePath"`
+
+	// ManPages are the man pages of the command-line tool, whose extension is their
+	// section, e.g. "mytool.1", installed to /usr/local/share/man/man<section>.
+	ManPages []string `validate:"omitempty,excluded_without=CommandLineTool,dive,required"`
+
+	// BashCompletion is the bash completion script of the command-line tool,
+	// installed to /usr/local/etc/bash_completion.d.
+	BashCompletion string `validate:"excluded_without=CommandLineTool"`
+
+	// ZshCompletion is the zsh completion script of the command-line tool,
+	// installed to /usr/local/share/zsh/site-functions.
+	ZshCompletion string `validate:"excluded_without=CommandLineTool"`
+
+	// FishCompletion is the fish completion script of the command-line tool,
+	// installed to /usr/local/share/fish/vendor_completions.d.
+	FishCompletion string `validate:"excluded_without=CommandLineTool"`
+
+	// Architectures are the architectures the Go main package at SourcePath is built for.
+	// When more than one is given, the binaries are merged into a universal binary.
+	// Defaults to arm64.
+	Architectures []string `validate:"omitempty,excluded_without=SourcePath,dive,oneof=arm64 amd64"`
+
+	// LDFlags are the flags passed to the Go linker when building from SourcePath.
+	LDFlags string `validate:"excluded_without=SourcePath"`
+
+	// BuildTags are the build tags used when building from SourcePath.
+	BuildTags []string `validate:"omitempty,excluded_without=SourcePath"`
+
+	// CGOEnabled indicates whether cgo is enabled when building from SourcePath.
+	CGOEnabled bool
 
 	// BundleIdentifier is the bundle identifier of the application.
-	BundleIdentifier string `validate:"required"`
+	BundleIdentifier string `validate:"required_without_all=AppBundlePath CommandLineTool,excluded_with=AppBundlePath CommandLineTool"`
 
 	// IconPath is the path to the icon file. Usable icon files are of type .png, .jpg, .gif, or .tiff.
-	IconPath string `validate:"required"`
+	IconPath string `validate:"required_without_all=AppBundlePath CommandLineTool,excluded_with=AppBundlePath CommandLineTool"`
 
 	// OutputDir is the directory where the DMG file will be created.
 	OutputDir string `validate:"required"`
+
+	// MinimumSystemVersion is the minimum macOS version required by the application.
+	// When empty, the minimum version declared by the application binary is used.
+	MinimumSystemVersion string `validate:"excluded_with=AppBundlePath CommandLineTool"`
+
+	// ShortVersion is the release version of the application (CFBundleShortVersionString), e.g. "1.2.3".
+	// When empty, the module version embedded in a Go application binary is used.
+	ShortVersion string `validate:"excluded_with=AppBundlePath CommandLineTool"`
+
+	// BundleVersion is the build version of the application (CFBundleVersion), e.g. "123".
+	// When empty, the module version embedded in a Go application binary is used.
+	BundleVersion string `validate:"excluded_with=AppBundlePath CommandLineTool"`
+
+	// ExecutableName is the name of the application binary inside Contents/MacOS (CFBundleExecutable),
+	// or the name the command-line tool is installed as. Defaults to AppName without the
+	// characters that cannot be part of a file name.
+	ExecutableName string `validate:"omitempty,excludesall=/,ne=.,ne=..,excluded_with=AppBundlePath"`
+
+	// PackageType is the four-character package type of the application (CFBundlePackageType).
+	// Defaults to APPL.
+	PackageType string `validate:"omitempty,len=4,printascii,excluded_with=AppBundlePath CommandLineTool"`
+
+	// BundleSignature is the four-character creator code of the application (CFBundleSignature).
+	// Defaults to ????, used by applications without a registered creator code.
+	BundleSignature string `validate:"omitempty,len=4,printascii,excluded_with=AppBundlePath CommandLineTool"`
+
+	// UsageDescriptions maps the privacy usage description keys of Info.plist,
+	// e.g. NSCameraUsageDescription, to the text shown when asking for the permission.
+	UsageDescriptions map[string]string `validate:"excluded_with=AppBundlePath CommandLineTool"`
+
+	// DevelopmentRegion is the language the application is developed in (CFBundleDevelopmentRegion).
+	// Defaults to en.
+	DevelopmentRegion string `validate:"excluded_with=AppBundlePath CommandLineTool"`
+
+	// Localizations are the localized application names and privacy usage descriptions,
+	// written to Contents/Resources/<language>.lproj/InfoPlist.strings.
+	Localizations []Localization `validate:"omitempty,excluded_with=AppBundlePath CommandLineTool,dive"`
+
+	// Resources are the extra files and directories copied to Contents/Resources.
+	Resources []BundleEntry `validate:"omitempty,excluded_with=AppBundlePath CommandLineTool,dive"`
+
+	// Helpers are the helper executables copied to Contents/MacOS next to the application binary.
+	Helpers []BundleEntry `validate:"omitempty,excluded_with=AppBundlePath CommandLineTool,dive"`
+
+	// Frameworks are the .framework and .dylib files copied to Contents/Frameworks.
+	Frameworks []BundleEntry `validate:"omitempty,excluded_with=AppBundlePath CommandLineTool,dive"`
+
+	// PlugIns are the plug-ins copied to Contents/PlugIns.
+	PlugIns []BundleEntry `validate:"omitempty,excluded_with=AppBundlePath CommandLineTool,dive"`
+
+	// LaunchAgents are the launchd agents whose plists are written to Contents/Library/LaunchAgents.
+	LaunchAgents []LaunchAgent `validate:"omitempty,excluded_with=AppBundlePath CommandLineTool,dive"`
+
+	// LoginItems are the helper application bundles copied to Contents/Library/LoginItems.
+	LoginItems []BundleEntry `validate:"omitempty,excluded_with=AppBundlePath CommandLineTool,dive"`
+
+	// Window is the layout of the Finder window the DMG opens in.
+	// When nil, the items are laid out in a 640x400 window with 128-point icons.
+	Window *WindowLayout
+
+	// BackgroundImagePath is the path to the PNG, JPEG, GIF or TIFF image shown as
+	// the background of the Finder window. It is copied to a hidden .background folder
+	// of the DMG, and the window is sized after it unless Window sets the size.
+	BackgroundImagePath string
+
+	// BackgroundImage2xPath is the path to the 2x version of the background image, twice
+	// as large, shown on retina screens. Both images are combined into a TIFF file.
+	BackgroundImage2xPath string `validate:"excluded_without=BackgroundImagePath"`
+
+	// VolumeIconPath is the path to the icon shown for the mounted DMG on the desktop and
+	// in Finder: an .icns file, or an image converted like IconPath. It is written to
+	// .VolumeIcon.icns at the root of the volume.
+	VolumeIconPath string
+
+	// UseAppIconAsVolumeIcon indicates whether the icon of the application bundle
+	// is used as the volume icon, as an alternative to VolumeIconPath.
+	UseAppIconAsVolumeIcon bool `validate:"excluded_with=VolumeIconPath CommandLineTool"`
+
+	// Licenses are the software license agreement, in one or more languages, that users
+	// must accept before the DMG mounts. The first license is shown by default, the others
+	// are picked from the language menu of the license window.
+	Licenses []License `validate:"omitempty,dive"`
+
+	// Format is the format of the final DMG: UDZO (zlib-compressed, the default), UDBZ
+	// (bzip2-compressed), ULFO (LZFSE-compressed, macOS 10.11+), ULMO (LZMA-compressed,
+	// macOS 10.15+), UDRO (read-only, uncompressed) or UDRW (read-write).
+	Format string `validate:"omitempty,oneof=UDZO UDBZ ULFO ULMO UDRO UDRW"`
+
+	// ZlibLevel is the compression level of UDZO DMGs, from 1 (fastest) to 9 (smallest).
+	// Defaults to the level of hdiutil.
+	ZlibLevel int `validate:"omitempty,min=1,max=9"`
+
+	// Filesystem is the filesystem of the DMG: APFS, Case-sensitive APFS, HFS+, Journaled HFS+,
+	// Case-sensitive HFS+ or Case-sensitive Journaled HFS+. APFS DMGs only mount on macOS 10.13
+	// and later, so it defaults to APFS unless the application supports older versions,
+	// according to its LSMinimumSystemVersion, in which case it defaults to HFS+.
+	Filesystem string `validate:"omitempty,oneof=APFS 'Case-sensitive APFS' HFS+ 'Journaled HFS+' 'Case-sensitive HFS+' 'Case-sensitive Journaled HFS+'"`
+
+	// Layout is the partition layout of the DMG: GPTSPUD (GUID partition map, the default),
+	// SPUD (Apple partition map), MBRSPUD (master boot record) or NONE. APFS needs GPTSPUD or NONE.
+	Layout string `validate:"omitempty,oneof=GPTSPUD SPUD MBRSPUD NONE"`
+
+	// Staging indicates whether the DMG is made in one step from a staging folder,
+	// where its contents are assembled, with hdiutil create -srcfolder, instead of
+	// mounting a DMG template, copying the contents into it and converting it. It needs
+	// no mount privileges and is faster, but the window cannot be laid out by Finder,
+	// which needs a mounted volume, and it cannot have a background image or a volume icon.
+	Staging bool
+
+	// Verify indicates whether the DMG is verified once created: its checksums are checked
+	// with hdiutil verify, then it is attached read-only at a private mount point to check
+	// that it holds the application, the Applications symlink and the Info.plist values of
+	// the application, or the install scripts of a command-line tool. Mismatches are
+	// returned as a *verify.VerificationError. Encrypted DMGs cannot be verified.
+	Verify bool
+
+	// Encryption is the encryption of the final DMG: AES-128 or AES-256.
+	// Encrypted DMGs ask for Passphrase before they mount. They cannot carry
+	// a license agreement, since it is attached to the unencrypted image.
+	Encryption string `validate:"omitempty,oneof=AES-128 AES-256"`
+
+	// Passphrase is the passphrase of the encrypted DMG. It is handed to hdiutil
+	// through its standard input, and it is never printed nor included in errors.
+	Passphrase string `validate:"required_with=Encryption,excluded_without=Encryption"`
+
+	// UseFyneAppMetadata indicates whether the FyneApp.toml file found next to
+	// SourcePath or to the application binary is used to fill AppName, BundleIdentifier,
+	// IconPath, ShortVersion and BundleVersion. Values that are set take precedence.
+	UseFyneAppMetadata bool
 }
 
 // Create creates a DMG file with the specified parameters.
-func Create(params *CreateParams) (string, error) {
+func Create(params *CreateParams) (*Result, error) {
+	// fill the missing parameters from the FyneApp.toml file, if requested.
+	if params.UseFyneAppMetadata {
+		var err error
+		params, err = applyFyneAppMetadata(params)
+		if err != nil {
+			return nil, errors.Wrap(err, "error when applying Fyne app metadata")
+		}
+	}
+
 	// validate the input parameters.
 	if err := validate.Check(params); err != nil {
-		return "", errors.Wrap(err, "error when validating input parameters")
+		return nil, errors.Wrap(err, "error when validating input parameters")
+	}
+	options, err := newImageOptions(params)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when validating input parameters")
 	}
 
 	// temporary working directory for the application bundle.
 	tmpWorkDir := filepath.Join(params.OutputDir, "tmp")
 	if err := fsOpsProvider.MkdirAll(tmpWorkDir, os.ModePerm); err != nil {
-		return "", errors.Wrap(err, "error when creating temp working directory")
+		return nil, errors.Wrap(err, "error when creating temp working directory")
 	}
 	// ensure the temporary working directory is cleaned up after use.
 	defer func() {
 		fsOpsProvider.DeleteDir(tmpWorkDir)
 	}()
 
+	// stage the background image and the volume icon of the DMG, if any.
+	appearance, err := prepareAppearance(params, tmpWorkDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// encode the license agreements, if any, before spending time on the DMG.
+	licenses, err := licenseResources(params.Licenses)
+	if err != nil {
+		return nil, err
+	}
+
+	// command-line tools are shipped with install scripts instead of an application bundle.
+	if params.CommandLineTool {
+		toolDmg, err := createToolDmg(params, appearance, options, tmpWorkDir)
+		if err != nil {
+			return nil, errors.Wrap(err, "error when creating tool DMG")
+		}
+		if err := attachLicense(toolDmg.Path, licenses); err != nil {
+			return nil, err
+		}
+		if params.Verify {
+			if err := verifyDmg(toolDmg.Path, toolExpectations()); err != nil {
+				return nil, err
+			}
+		}
+		return toolDmg, nil
+	}
+
+	// use the existing application bundle or create a new one.
+	var appBundlePath, appIconFile, minimumSystemVersion string
+	if params.AppBundlePath != "" {
+		appBundlePath = filepath.Clean(params.AppBundlePath)
+		info, err := validateAppBundle(appBundlePath)
+		if err != nil {
+			return nil, errors.Wrap(err, "error when validating app bundle")
+		}
+		appIconFile = info.IconFile
+		minimumSystemVersion = info.MinimumSystemVersion
+	} else {
+		appBundlePath, minimumSystemVersion, err = buildAppBundle(params, tmpWorkDir)
+		if err != nil {
+			return nil, err
+		}
+		appIconFile = iconFile
+	}
+	options.resolveFilesystem(minimumSystemVersion)
+	appearance.filesystem = options.filesystem
+
+	// use the icon of the application as the volume icon, if requested.
+	if params.UseAppIconAsVolumeIcon {
+		if appIconFile == "" {
+			return nil, errors.Errorf("app bundle [%s] does not declare an icon to use as the volume icon", appBundlePath)
+		}
+		appearance.volumeIconPath = filepath.Join(appBundlePath, resourcesDir, appIconFile)
+	}
+
+	// create the DMG file from the application bundle.
+	appDmg, err := createAppDmg(appBundlePath, appearance, options, tmpWorkDir, params.OutputDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when creating app DMG")
+	}
+
+	// embed the license agreements into the DMG, if any.
+	if err := attachLicense(appDmg.Path, licenses); err != nil {
+		return nil, err
+	}
+
+	// check the DMG against the application bundle it was created from, if requested.
+	if params.Verify {
+		expected, err := appExpectations(appBundlePath)
+		if err != nil {
+			return nil, err
+		}
+		if err := verifyDmg(appDmg.Path, expected); err != nil {
+			return nil, err
+		}
+	}
+
+	return appDmg, nil
+}
+
+// buildAppBundle creates a new application bundle from the application binary, which is
+// built or merged first when needed, or from the script, and returns its path along with
+// the minimum macOS version it requires.
+func buildAppBundle(params *CreateParams, tmpWorkDir string) (string, string, error) {
+	if err := checkLocalizations(params.UsageDescriptions, params.Localizations); err != nil {
+		return "", "", errors.Wrap(err, "error when checking localizations")
+	}
+	if err := checkLaunchAgents(params.BundleIdentifier, params.LaunchAgents); err != nil {
+		return "", "", errors.Wrap(err, "error when checking launch agents")
+	}
+	executableName, err := resolveExecutableName(params)
+	if err != nil {
+		return "", "", err
+	}
+	infoPlist := newInfoPlistData(params, executableName)
+
+	// scripts are run by a generated launcher, which has no
+	// metadata to read nor dynamic libraries to bundle.
+	isScript := params.ScriptPath != ""
+	var appBinaryPath string
+	if isScript {
+		appBinaryPath, err = createScriptLauncher(params, tmpWorkDir)
+	} else {
+		appBinaryPath, err = prepareAppBinary(params, infoPlist, tmpWorkDir)
+	}
+	if err != nil {
+		return "", "", err
+	}
+
 	appBundleSpinner := spinner.New(spinner.CharSets[14], 300*time.Millisecond)
 	appBundleSpinner.Suffix = " creating application bundle..."
 	appBundleSpinner.FinalMSG = "✔ creating application bundle...\n"
@@ -66,27 +408,366 @@ func Create(params *CreateParams) (string, error) {
 	// create the application bundle directory structure and files.
 	createdAppBundleDirPath, err := createAppBundle(
 		params.AppName,
-		params.AppBinaryPath,
+		appBinaryPath,
 		params.IconPath,
-		params.BundleIdentifier,
+		infoPlist,
+		bundleContents(params),
+		params.Localizations,
 		tmpWorkDir,
 	)
 	appBundleSpinner.Stop()
 	if err != nil {
-		return "", errors.Wrap(err, "error when creating app bundle")
+		return "", "", errors.Wrap(err, "error when creating app bundle")
 	}
 
-	// create the DMG file from the application bundle.
-	createdAppDmgPath, err := createAppDmg(createdAppBundleDirPath, tmpWorkDir, params.OutputDir)
+	// write the launch agent plists and check the login items against the bundle identifier.
+	if err := createLaunchAgentFiles(params.BundleIdentifier, params.LaunchAgents, createdAppBundleDirPath); err != nil {
+		return "", "", errors.Wrap(err, "error when creating launch agents")
+	}
+	if err := checkLoginItems(params.BundleIdentifier, createdAppBundleDirPath); err != nil {
+		return "", "", errors.Wrap(err, "error when checking login items")
+	}
+
+	// bundle the non-system dynamic libraries the application binary depends on.
+	if !isScript {
+		if err := bundleAppDylibs(appBinaryPath, executableName, createdAppBundleDirPath); err != nil {
+			return "", "", err
+		}
+	}
+
+	// set the canonical file modes and check the finished bundle.
+	if err := finishAppBundle(createdAppBundleDirPath); err != nil {
+		return "", "", err
+	}
+	return createdAppBundleDirPath, infoPlist.MinimumSystemVersion, nil
+}
+
+// newInfoPlistData returns the Info.plist values given in the parameters, with the defaults filled.
+func newInfoPlistData(params *CreateParams, executableName string) *infoPlistData {
+	infoPlist := &infoPlistData{
+		Name:                 params.AppName,
+		Executable:           executableName,
+		BundleIdentifier:     params.BundleIdentifier,
+		MinimumSystemVersion: params.MinimumSystemVersion,
+		ShortVersion:         params.ShortVersion,
+		BundleVersion:        params.BundleVersion,
+		PackageType:          params.PackageType,
+		Signature:            params.BundleSignature,
+		DevelopmentRegion:    params.DevelopmentRegion,
+		UsageDescriptions:    params.UsageDescriptions,
+	}
+	if infoPlist.PackageType == "" {
+		infoPlist.PackageType = bundle.ApplicationPackageType
+	}
+	if infoPlist.Signature == "" {
+		infoPlist.Signature = bundle.UnknownSignature
+	}
+	if infoPlist.DevelopmentRegion == "" {
+		infoPlist.DevelopmentRegion = defaultDevelopmentRegion
+	}
+	infoPlist.Localizations = localizedLanguages(infoPlist.DevelopmentRegion, params.Localizations)
+	if hasLocalizedDisplayName(params.Localizations) {
+		// the localized names are only used when Info.plist declares a name to localize.
+		infoPlist.DisplayName = params.AppName
+		infoPlist.LocalizedDisplayName = true
+	}
+	return infoPlist
+}
+
+// prepareAppBinary builds or merges the application binary, if needed, inspects it
+// and fills the missing Info.plist values from it, returning the path to the binary.
+func prepareAppBinary(params *CreateParams, infoPlist *infoPlistData, tmpWorkDir string) (string, error) {
+	// build or merge the application binary, if needed.
+	appBinaryPath, err := resolveAppBinary(params, tmpWorkDir)
+	if err != nil {
+		return "", err
+	}
+
+	// inspect the application binary before bundling it.
+	appBinaryInfo, err := inspectAppBinary(appBinaryPath)
 	if err != nil {
-		return "", errors.Wrap(err, "error when creating app DMG")
+		return "", errors.Wrap(err, "error when inspecting app binary")
 	}
+	if infoPlist.MinimumSystemVersion == "" {
+		infoPlist.MinimumSystemVersion = appBinaryInfo.MinimumOS
+	}
+
+	// fill the missing metadata from the Go build information, if any.
+	appBinaryBuildInfo, err := buildInfoProvider.Read(appBinaryPath)
+	if err != nil {
+		return "", errors.Wrap(err, "error when reading app binary build info")
+	}
+	applyBuildInfo(infoPlist, appBinaryBuildInfo)
+	return appBinaryPath, nil
+}
+
+// bundleAppDylibs bundles the non-system dynamic libraries the application
+// binary depends on, warning about the ones that could not be bundled.
+func bundleAppDylibs(appBinaryPath, executableName, appBundleDirPath string) error {
+	dylibsSpinner := spinner.New(spinner.CharSets[14], 300*time.Millisecond)
+	dylibsSpinner.Suffix = " bundling dynamic libraries..."
+	dylibsSpinner.FinalMSG = "✔ bundling dynamic libraries...\n"
+	dylibsSpinner.Start()
 
-	return createdAppDmgPath, nil
+	dylibDeps, err := bundleDylibs(appBinaryPath, executableName, appBundleDirPath)
+	if err == nil {
+		dylibsSpinner.FinalMSG = fmt.Sprintf("✔ bundling dynamic libraries... [%d bundled]\n", len(dylibDeps.libraries))
+	}
+	dylibsSpinner.Stop()
+	if err != nil {
+		return errors.Wrap(err, "error when bundling dynamic libraries")
+	}
+	for _, problem := range dylibDeps.problems {
+		printWarning("%s", problem)
+	}
+	return nil
+}
+
+// finishAppBundle sets the canonical modes of the directories and files of the
+// created application bundle and checks it against Apple's structure rules.
+func finishAppBundle(appBundleDirPath string) error {
+	checkSpinner := spinner.New(spinner.CharSets[14], 300*time.Millisecond)
+	checkSpinner.Suffix = " checking application bundle layout..."
+	checkSpinner.FinalMSG = "✔ checking application bundle layout...\n"
+	checkSpinner.Start()
+	defer checkSpinner.Stop()
+
+	if err := bundleProvider.NormalizeModes(appBundleDirPath); err != nil {
+		return errors.Wrap(err, "error when setting app bundle file modes")
+	}
+	if err := bundleProvider.CheckLayout(appBundleDirPath); err != nil {
+		return errors.Wrap(err, "error when checking app bundle layout")
+	}
+	return nil
+}
+
+// validateAppBundle validates the existing application bundle at the given path.
+func validateAppBundle(appBundlePath string) (*bundle.Info, error) {
+	validateSpinner := spinner.New(spinner.CharSets[14], 300*time.Millisecond)
+	validateSpinner.Suffix = " validating application bundle..."
+	validateSpinner.FinalMSG = "✔ validating application bundle...\n"
+	validateSpinner.Start()
+
+	info, err := bundleProvider.Validate(appBundlePath)
+	if err == nil {
+		validateSpinner.FinalMSG = fmt.Sprintf("✔ validating application bundle... %s\n", describeAppBundle(info))
+	}
+	validateSpinner.Stop()
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// describeAppBundle returns a human-readable description of the application bundle.
+func describeAppBundle(info *bundle.Info) string {
+	version := info.ShortVersion
+	if version == "" {
+		version = "not declared"
+	}
+	if info.Version != "" && info.Version != info.ShortVersion {
+		version = fmt.Sprintf("%s (%s)", version, info.Version)
+	}
+	return fmt.Sprintf("[identifier: %s, executable: %s, version: %s]", info.Identifier, info.Executable, version)
+}
+
+// applyFyneAppMetadata returns a copy of the given parameters with the missing
+// values filled from the FyneApp.toml file found next to the source or the binary.
+func applyFyneAppMetadata(params *CreateParams) (*CreateParams, error) {
+	fyneAppTomlPath, err := findFyneAppToml(params)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := fyneAppProvider.Load(fyneAppTomlPath)
+	if err != nil {
+		return nil, err
+	}
+	filled := *params
+	if filled.AppName == "" {
+		filled.AppName = metadata.Details.Name
+	}
+	if filled.BundleIdentifier == "" {
+		filled.BundleIdentifier = metadata.Details.ID
+	}
+	if filled.IconPath == "" {
+		filled.IconPath = metadata.Details.Icon
+	}
+	if filled.ShortVersion == "" {
+		filled.ShortVersion = metadata.Details.Version
+	}
+	if filled.BundleVersion == "" && metadata.Details.Build > 0 {
+		filled.BundleVersion = strconv.Itoa(metadata.Details.Build)
+	}
+	return &filled, nil
+}
+
+// findFyneAppToml finds the FyneApp.toml file in the directory of
+// the source package or in the directory of the application binary.
+func findFyneAppToml(params *CreateParams) (string, error) {
+	var dir string
+	switch {
+	case params.SourcePath != "":
+		dir = params.SourcePath
+	case len(params.AppBinaryPaths) > 0:
+		dir = filepath.Dir(params.AppBinaryPaths[0])
+	case params.AppBinaryPath != "":
+		dir = filepath.Dir(params.AppBinaryPath)
+	default:
+		return "", errors.Errorf("a source path or an app binary path is required to find %s", fyneapp.FileName)
+	}
+	fyneAppTomlPath := filepath.Join(dir, fyneapp.FileName)
+	exists, err := fsOpsProvider.FileExists(fyneAppTomlPath)
+	if err != nil {
+		return "", errors.Wrapf(err, "error when checking if [%s] exists", fyneAppTomlPath)
+	}
+	if !exists {
+		return "", errors.Errorf("%s not found in [%s]", fyneapp.FileName, dir)
+	}
+	return fyneAppTomlPath, nil
+}
+
+// resolveAppBinary returns the path to the application binary to be bundled,
+// building it from source or merging the per-architecture binaries when requested.
+func resolveAppBinary(params *CreateParams, tmpWorkDir string) (string, error) {
+	switch {
+	case params.SourcePath != "":
+		appBinaryPath, err := buildAppBinary(params, tmpWorkDir)
+		if err != nil {
+			return "", errors.Wrap(err, "error when building app binary")
+		}
+		return appBinaryPath, nil
+	case len(params.AppBinaryPaths) > 0:
+		appBinaryPath, err := createUniversalBinary(params.AppName, params.AppBinaryPaths, tmpWorkDir)
+		if err != nil {
+			return "", errors.Wrap(err, "error when creating universal binary")
+		}
+		return appBinaryPath, nil
+	default:
+		return params.AppBinaryPath, nil
+	}
+}
+
+// buildAppBinary builds the Go main package at SourcePath for darwin, once per
+// architecture, merging the binaries into a universal binary if more than one is built.
+func buildAppBinary(params *CreateParams, outputDir string) (string, error) {
+	architectures := params.Architectures
+	if len(architectures) == 0 {
+		architectures = []string{defaultArchitecture}
+	}
+	var appBinaryPaths []string
+	for _, arch := range architectures {
+		appBinaryPath, err := buildAppBinaryForArch(params, arch, outputDir)
+		if err != nil {
+			return "", err
+		}
+		appBinaryPaths = append(appBinaryPaths, appBinaryPath)
+	}
+	if len(appBinaryPaths) == 1 {
+		return appBinaryPaths[0], nil
+	}
+	return createUniversalBinary(params.AppName, appBinaryPaths, outputDir)
+}
+
+// buildAppBinaryForArch builds the Go main package at SourcePath for darwin/arch.
+func buildAppBinaryForArch(params *CreateParams, arch, outputDir string) (string, error) {
+	buildSpinner := spinner.New(spinner.CharSets[14], 300*time.Millisecond)
+	buildSpinner.Suffix = fmt.Sprintf(" building application binary for darwin/%s...", arch)
+	buildSpinner.FinalMSG = fmt.Sprintf("✔ building application binary for darwin/%s...\n", arch)
+	buildSpinner.Start()
+
+	appBinaryDirPath := filepath.Join(outputDir, buildDir, arch)
+	if err := fsOpsProvider.MkdirAll(appBinaryDirPath, os.ModePerm); err != nil {
+		buildSpinner.Stop()
+		return "", errors.Wrapf(err, "error when creating directory [%s]", appBinaryDirPath)
+	}
+	appBinaryPath := filepath.Join(appBinaryDirPath, params.AppName)
+	err := goBuildProvider.Build(&gobuild.Params{
+		PackageDir: params.SourcePath,
+		OutputPath: appBinaryPath,
+		Arch:       arch,
+		LDFlags:    params.LDFlags,
+		Tags:       params.BuildTags,
+		CGOEnabled: params.CGOEnabled,
+	})
+	buildSpinner.Stop()
+	if err != nil {
+		return "", err
+	}
+	return appBinaryPath, nil
+}
+
+// createUniversalBinary merges the per-architecture application
+// binaries into a universal binary named after the application.
+func createUniversalBinary(appName string, appBinaryPaths []string, outputDir string) (string, error) {
+	universalBinarySpinner := spinner.New(spinner.CharSets[14], 300*time.Millisecond)
+	universalBinarySpinner.Suffix = " creating universal binary..."
+	universalBinarySpinner.FinalMSG = "✔ creating universal binary...\n"
+	universalBinarySpinner.Start()
+
+	universalBinaryPath := filepath.Join(outputDir, appName)
+	err := machoProvider.CreateUniversal(universalBinaryPath, appBinaryPaths...)
+	universalBinarySpinner.Stop()
+	if err != nil {
+		return "", err
+	}
+	return universalBinaryPath, nil
+}
+
+// inspectAppBinary inspects the application binary, making sure it is
+// a Mach-O executable, and reports its architectures and minimum macOS version.
+func inspectAppBinary(appBinaryPath string) (*macho.Info, error) {
+	inspectSpinner := spinner.New(spinner.CharSets[14], 300*time.Millisecond)
+	inspectSpinner.Suffix = " inspecting application binary..."
+	inspectSpinner.FinalMSG = "✔ inspecting application binary...\n"
+	inspectSpinner.Start()
+
+	info, err := machoProvider.Inspect(appBinaryPath)
+	if err == nil {
+		inspectSpinner.FinalMSG = fmt.Sprintf("✔ inspecting application binary... %s\n", describeAppBinary(info))
+	}
+	inspectSpinner.Stop()
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// describeAppBinary returns a human-readable description of the application binary.
+func describeAppBinary(info *macho.Info) string {
+	kind := "thin"
+	if info.Fat {
+		kind = "fat"
+	}
+	minimumOS := info.MinimumOS
+	if minimumOS == "" {
+		minimumOS = "not declared"
+	}
+	return fmt.Sprintf("[architectures: %s (%s), minimum macOS: %s]", strings.Join(info.Architectures, ", "), kind, minimumOS)
+}
+
+// applyBuildInfo fills the versions of the application that were not given
+// from the Go build information of the application binary, records the
+// VCS revision and warns about binaries built from a modified working tree.
+func applyBuildInfo(infoPlist *infoPlistData, info *buildinfo.Info) {
+	if info == nil {
+		return
+	}
+	if version := info.Version(); version != "" {
+		if infoPlist.ShortVersion == "" {
+			infoPlist.ShortVersion = version
+		}
+		if infoPlist.BundleVersion == "" {
+			infoPlist.BundleVersion = version
+		}
+	}
+	infoPlist.VCSRevision = info.VCSRevision
+	if info.VCSModified {
+		printWarning("the application binary was built from a working tree with uncommitted changes (revision %s)", info.VCSRevision)
+	}
 }
 
 // createAppBundle creates the application bundle.
-func createAppBundle(appName, appBinaryPath, iconPath, bundleIdentifier, outputDir string) (string, error) {
+func createAppBundle(appName, appBinaryPath, iconPath string, infoPlist *infoPlistData, contents []bundleContent, localizations []Localization, outputDir string) (string, error) {
 	appBundleDirName := fmt.Sprintf("%s.app", appName)
 	appBundleDirPath := filepath.Join(outputDir, appBundleDirName)
 
@@ -101,15 +782,30 @@ func createAppBundle(appName, appBinaryPath, iconPath, bundleIdentifier, outputD
 	}
 
 	// copy the application binary to the MacOS directory.
-	if err := copyAppBinary(appBinaryPath, appBundleDirPath); err != nil {
+	if err := copyAppBinary(appBinaryPath, infoPlist.Executable, appBundleDirPath); err != nil {
 		return "", errors.Wrap(err, "error when copying app binary")
 	}
 
-	// create the Info.plist file in the Resources directory.
-	if err := createInfoPlistFile(appBinaryPath, appBundleDirName, outputDir, bundleIdentifier); err != nil {
+	// copy the extra resources, helpers, frameworks and plug-ins.
+	if err := copyBundleContents(contents, appBundleDirPath); err != nil {
+		return "", errors.Wrap(err, "error when copying bundle contents")
+	}
+
+	// create the Info.plist file in the Contents directory.
+	if err := createInfoPlistFile(infoPlist, appBundleDirName, outputDir); err != nil {
 		return "", errors.Wrap(err, "error when creating Info.plist file")
 	}
 
+	// create the PkgInfo file in the Contents directory.
+	if err := createPkgInfoFile(infoPlist, appBundleDirName, outputDir); err != nil {
+		return "", errors.Wrap(err, "error when creating PkgInfo file")
+	}
+
+	// create the InfoPlist.strings file of every localization.
+	if err := createInfoPlistStringsFiles(localizations, appBundleDirPath); err != nil {
+		return "", errors.Wrap(err, "error when creating localizations")
+	}
+
 	return appBundleDirPath, nil
 }
 
@@ -121,7 +817,7 @@ func createAppBundleDirectories(appBundleDirName, outputDir string) error {
 		filepath.Join(outputDir, appBundleDirName, macOsDir),
 		filepath.Join(outputDir, appBundleDirName, resourcesDir),
 	} {
-		err := fsOpsProvider.MkdirAll(dirName, os.ModePerm)
+		err := fsOpsProvider.MkdirAll(dirName, bundleDirMode)
 		if err != nil {
 			return errors.Wrapf(err, "error when creating directory [%s]", dirName)
 		}
@@ -131,7 +827,6 @@ func createAppBundleDirectories(appBundleDirName, outputDir string) error {
 
 // createIconSet creates the icon set directory structure.
 func createIconSet(iconPath, appleBundleDirName, appBundleDirPath string) error {
-	iconSizes := []int{16, 32, 64, 128, 256, 512, 1024}
 	iconSetDirPath := filepath.Join(appBundleDirPath, iconSetDir)
 	if err := sipsUtilityProvider.GenerateIcons(iconPath, iconSetDirPath, iconSizes...); err != nil {
 		return errors.Wrap(err, "error when generating icons")
@@ -143,33 +838,87 @@ func createIconSet(iconPath, appleBundleDirName, appBundleDirPath string) error
 	return nil
 }
 
-// copyAppBinary copies the application binary to the MacOS directory within the app bundle.
-func copyAppBinary(appBinaryPath, appBundleDirPath string) error {
-	macOsDirPath := filepath.Join(appBundleDirPath, macOsDir)
-	if err := fsOpsProvider.CopyFile(appBinaryPath, macOsDirPath); err != nil {
-		return errors.Wrapf(err, "error when copying file [%s] to [%s]", appBinaryPath, macOsDirPath)
+// copyAppBinary copies the application binary to the MacOS directory
+// within the app bundle, naming it after the bundle executable.
+func copyAppBinary(appBinaryPath, executableName, appBundleDirPath string) error {
+	executablePath := filepath.Join(appBundleDirPath, macOsDir, executableName)
+	if err := fsOpsProvider.CopyFile(appBinaryPath, executablePath); err != nil {
+		return errors.Wrapf(err, "error when copying file [%s] to [%s]", appBinaryPath, executablePath)
 	}
 	return nil
 }
 
+// resolveExecutableName returns the executable name given in the
+// parameters or, when not given, the one derived from the application name.
+func resolveExecutableName(params *CreateParams) (string, error) {
+	if params.ExecutableName != "" {
+		return params.ExecutableName, nil
+	}
+	executableName := sanitizeExecutableName(params.AppName)
+	if executableName == "" {
+		return "", errors.Errorf("cannot derive an executable name from the app name [%s]; set the executable name", params.AppName)
+	}
+	return executableName, nil
+}
+
+// sanitizeExecutableName returns the application name without the characters
+// that cannot be part of a file name, such as slashes and control characters,
+// and without leading dots and surrounding spaces.
+func sanitizeExecutableName(appName string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r == '/' || r == ':':
+			return '-'
+		case unicode.IsControl(r):
+			return -1
+		}
+		return r
+	}, appName)
+	return strings.TrimLeft(strings.TrimSpace(name), ".")
+}
+
 // createInfoPlistFile creates the Info.plist file.
-func createInfoPlistFile(appBinaryPath, appleBundleDirName, appBundleDirPath, bundleIdentifier string) error {
-	infoPlist := strings.Replace(infoPlistTpl, "{{.AppName}}", filepath.Base(appBinaryPath), -1)
-	infoPlist = strings.Replace(infoPlist, "{{.BundleIdentifier}}", bundleIdentifier, -1)
+func createInfoPlistFile(data *infoPlistData, appleBundleDirName, appBundleDirPath string) error {
+	infoPlist, err := plist.Encode(newInfoPlist(data))
+	if err != nil {
+		return errors.Wrap(err, "error when encoding Info.plist file")
+	}
 	contentsDirPath := filepath.Join(appBundleDirPath, appleBundleDirName, contentsDir)
 	infoPlistPath := filepath.Join(contentsDirPath, "Info.plist")
-	err := fsOpsProvider.WriteFile(infoPlistPath, []byte(infoPlist), os.ModePerm)
-	if err != nil {
+	if err := fsOpsProvider.WriteFile(infoPlistPath, infoPlist, bundleDataMode); err != nil {
 		return errors.Wrapf(err, "error when writing Info.plist file to [%s]", infoPlistPath)
 	}
 	return nil
 }
 
+// createPkgInfoFile creates the PkgInfo file, which holds the package
+// type followed by the signature of the application.
+func createPkgInfoFile(data *infoPlistData, appleBundleDirName, appBundleDirPath string) error {
+	pkgInfoPath := filepath.Join(appBundleDirPath, appleBundleDirName, bundle.PkgInfoPath)
+	err := fsOpsProvider.WriteFile(pkgInfoPath, []byte(data.PackageType+data.Signature), bundleDataMode)
+	if err != nil {
+		return errors.Wrapf(err, "error when writing PkgInfo file to [%s]", pkgInfoPath)
+	}
+	return nil
+}
+
 // createAppDmg creates the DMG file for the application bundle.
-func createAppDmg(appBundlePath, tmpWorkDir, outputDir string) (string, error) {
+func createAppDmg(appBundlePath string, appearance *volumeAppearance, options *imageOptions, tmpWorkDir, outputDir string) (*Result, error) {
 	dmgName := strings.TrimSuffix(filepath.Base(appBundlePath), ".app")
+	return createDmg(dmgName, func(mountPoint string) error {
+		return setupDMGTemplate(mountPoint, appBundlePath, appearance)
+	}, options, tmpWorkDir, outputDir)
+}
+
+// createDmg creates a DMG file with the given name, whose contents are
+// copied by the setup function into the mounted DMG template, or into
+// the staging folder when staging is requested.
+func createDmg(dmgName string, setup func(mountPoint string) error, options *imageOptions, tmpWorkDir, outputDir string) (*Result, error) {
 	if err := checkIfFinalDMGAlreadyExists(dmgName, outputDir); err != nil {
-		return "", err
+		return nil, err
+	}
+	if options.staging {
+		return createStagedDmg(dmgName, setup, options, tmpWorkDir, outputDir)
 	}
 
 	dmgTemplateSpinner := spinner.New(spinner.CharSets[14], 300*time.Millisecond)
@@ -177,10 +926,10 @@ func createAppDmg(appBundlePath, tmpWorkDir, outputDir string) (string, error) {
 	dmgTemplateSpinner.FinalMSG = "✔ creating DMG template...\n"
 	dmgTemplateSpinner.Start()
 
-	dmgTemplatePath, err := createDMGTemplate(dmgName, tmpWorkDir)
+	dmgTemplatePath, err := createDMGTemplate(dmgName, options, tmpWorkDir)
 	dmgTemplateSpinner.Stop()
 	if err != nil {
-		return "", errors.Wrap(err, "error when creating DMG template")
+		return nil, errors.Wrap(err, "error when creating DMG template")
 	}
 
 	mountDMGTemplateSpinner := spinner.New(spinner.CharSets[14], 300*time.Millisecond)
@@ -191,7 +940,7 @@ func createAppDmg(appBundlePath, tmpWorkDir, outputDir string) (string, error) {
 	mountPoint, err := mountDMGTemplate(dmgName, dmgTemplatePath)
 	mountDMGTemplateSpinner.Stop()
 	if err != nil {
-		return "", errors.Wrap(err, "error when mounting DMG template")
+		return nil, errors.Wrap(err, "error when mounting DMG template")
 	}
 
 	setupDMGTemplateSpinner := spinner.New(spinner.CharSets[14], 300*time.Millisecond)
@@ -199,10 +948,14 @@ func createAppDmg(appBundlePath, tmpWorkDir, outputDir string) (string, error) {
 	setupDMGTemplateSpinner.FinalMSG = "✔ setting up DMG template...\n"
 	setupDMGTemplateSpinner.Start()
 
-	err = setupDMGTemplate(mountPoint, appBundlePath)
+	err = setup(mountPoint)
 	setupDMGTemplateSpinner.Stop()
 	if err != nil {
-		return "", errors.Wrap(err, "error when setting up DMG template")
+		return nil, errors.Wrap(err, "error when setting up DMG template")
+	}
+	contentsSize, err := fsOpsProvider.DirSize(mountPoint)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when reading the size of the DMG contents")
 	}
 
 	unmountDMGTemplateSpinner := spinner.New(spinner.CharSets[14], 300*time.Millisecond)
@@ -213,17 +966,18 @@ func createAppDmg(appBundlePath, tmpWorkDir, outputDir string) (string, error) {
 	err = unmountDMGTemplate(mountPoint)
 	unmountDMGTemplateSpinner.Stop()
 	if err != nil {
-		return "", errors.Wrap(err, "error when unmounting DMG template")
+		return nil, errors.Wrap(err, "error when unmounting DMG template")
 	}
 
-	return convertDmg(appBundlePath, dmgTemplatePath, outputDir)
+	return convertDmg(dmgName, dmgTemplatePath, contentsSize, options, outputDir)
 }
 
-// createDMGTemplate creates a DMG template for the application bundle.
-func createDMGTemplate(dmgTemplateVolName, outputDir string) (string, error) {
+// createDMGTemplate creates a DMG template for the application bundle,
+// with the filesystem and the partition layout of the options.
+func createDMGTemplate(dmgTemplateVolName string, options *imageOptions, outputDir string) (string, error) {
 	dmgTemplateFileName := fmt.Sprintf("%s-template.dmg", dmgTemplateVolName)
 	dmgTemplatePath := filepath.Join(outputDir, dmgTemplateFileName)
-	if err := hdiutilProvider.CreateDMG("100m", "APFS", dmgTemplateVolName, "GPTSPUD", dmgTemplatePath); err != nil {
+	if err := hdiutilProvider.CreateDMG("100m", options.filesystem, dmgTemplateVolName, options.layout, dmgTemplatePath); err != nil {
 		return "", err
 	}
 	return dmgTemplatePath, nil
@@ -238,15 +992,17 @@ func mountDMGTemplate(dmgTemplateVolName, dmgTemplatePath string) (string, error
 	return mountedDmgTemplatePath, nil
 }
 
-// setupDMGTemplate sets up the mounted DMG template with the application bundle.
-func setupDMGTemplate(mountedDmgTemplatePath, createdAppBundleDirPath string) error {
+// setupDMGTemplate sets up the mounted DMG template with the application
+// bundle, next to the Applications symlink, and applies its appearance.
+func setupDMGTemplate(mountedDmgTemplatePath, createdAppBundleDirPath string, appearance *volumeAppearance) error {
 	if err := createMacOsApplicationFolderSymlink(mountedDmgTemplatePath); err != nil {
 		return errors.Wrap(err, "error when creating symlink for Applications folder")
 	}
 	if err := copyAppBundle(createdAppBundleDirPath, mountedDmgTemplatePath); err != nil {
 		return errors.Wrapf(err, "error when copying app bundle to mounted DMG template at [%s]", mountedDmgTemplatePath)
 	}
-	return nil
+	items := []string{filepath.Base(createdAppBundleDirPath), applicationsSymlinkName}
+	return applyAppearance(appearance, items, mountedDmgTemplatePath)
 }
 
 // createMacOsApplicationFolderSymlink creates a symlink
@@ -289,19 +1045,43 @@ func checkIfFinalDMGAlreadyExists(dmgName, outputDir string) error {
 	return nil
 }
 
-// convertDmg converts the DMG template to the final DMG file.
-func convertDmg(createdAppBundleDirPath, createdDmgTemplatePath, outputDir string) (string, error) {
+// convertDmg converts the DMG template to the final DMG file, in the given format, and reports
+// its size compared to the contentsSize bytes of its contents and how long the conversion took.
+func convertDmg(dmgName, createdDmgTemplatePath string, contentsSize int64, options *imageOptions, outputDir string) (*Result, error) {
 	convertDMGSpinner := spinner.New(spinner.CharSets[14], 300*time.Millisecond)
-	convertDMGSpinner.Suffix = " converting DMG template to final DMG..."
-	convertDMGSpinner.FinalMSG = "✔ converting DMG template to final DMG...\n"
+	convertDMGSpinner.Suffix = fmt.Sprintf(" converting DMG template to final %s DMG...", options.format)
+	convertDMGSpinner.FinalMSG = fmt.Sprintf("✔ converting DMG template to final %s DMG...\n", options.format)
 	convertDMGSpinner.Start()
 
-	appDMGPath := filepath.Join(outputDir, fmt.Sprintf("%s.dmg", filepath.Base(strings.TrimSuffix(createdAppBundleDirPath, ".app"))))
+	appDMGPath := filepath.Join(outputDir, fmt.Sprintf("%s.dmg", dmgName))
 
-	err := hdiutilProvider.ConvertDMG(createdDmgTemplatePath, appDMGPath)
+	start := time.Now()
+	err := hdiutilProvider.ConvertDMG(createdDmgTemplatePath, appDMGPath, &hdiutil.ConvertOptions{
+		Format:     options.format,
+		ZlibLevel:  options.zlibLevel,
+		Encryption: options.encryption,
+		Passphrase: options.passphrase,
+	})
+	duration := time.Since(start)
 	convertDMGSpinner.Stop()
 	if err != nil {
-		return "", errors.Wrap(err, "error when converting DMG template to final DMG")
+		return nil, errors.Wrap(err, "error when converting DMG template to final DMG")
 	}
-	return appDMGPath, nil
+
+	size, err := fsOpsProvider.FileSize(appDMGPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when reading the size of the final DMG")
+	}
+	return &Result{
+		Path:               appDMGPath,
+		Format:             options.format,
+		Size:               size,
+		ContentsSize:       contentsSize,
+		ConversionDuration: duration,
+	}, nil
+}
+
+// printWarning prints a warning message to the output.
+func printWarning(format string, args ...any) {
+	fmt.Fprintf(warningOutput, "⚠ warning: %s\n", fmt.Sprintf(format, args...))
 }
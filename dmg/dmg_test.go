@@ -5,10 +5,25 @@
 package dmg
 
 import (
+	"bytes"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/macos-dmg-creator/buildinfo"
+	"github.com/tiagomelo/macos-dmg-creator/bundle"
+	"github.com/tiagomelo/macos-dmg-creator/dsstore"
+	"github.com/tiagomelo/macos-dmg-creator/fyneapp"
+	"github.com/tiagomelo/macos-dmg-creator/gobuild"
+	"github.com/tiagomelo/macos-dmg-creator/hdiutil"
+	"github.com/tiagomelo/macos-dmg-creator/macho"
+	"github.com/tiagomelo/macos-dmg-creator/osascript"
+	"github.com/tiagomelo/macos-dmg-creator/script"
+	"github.com/tiagomelo/macos-dmg-creator/udif"
+	"github.com/tiagomelo/macos-dmg-creator/verify"
 )
 
 func TestCreate(t *testing.T) {
@@ -19,7 +34,17 @@ func TestCreate(t *testing.T) {
 		mockSipsUtilityProvider func() *mockSipsUtilityProvider
 		mockIconUtilProvider    func() *mockIconUtilProvider
 		mockHdiutilProvider     func() *mockHdiutilProvider
+		mockMachoProvider       func() *mockMachoProvider
+		mockGoBuildProvider     func() *mockGoBuildProvider
+		mockBuildInfoProvider   func() *mockBuildInfoProvider
+		mockFyneAppProvider     func() *mockFyneAppProvider
+		mockBundleProvider      func() *mockBundleProvider
+		mockImageProvider       func() *mockImageProvider
+		mockUdifProvider        func() *mockUdifProvider
+		mockVerifyProvider      func() *mockVerifyProvider
 		want                    string
+		wantValidatedPath       string
+		wantCheckedPath         string
 		wantErr                 error
 	}{
 		{
@@ -43,16 +68,21 @@ func TestCreate(t *testing.T) {
 			mockHdiutilProvider: func() *mockHdiutilProvider {
 				return &mockHdiutilProvider{}
 			},
-			want: "outputDir/testAppName.dmg",
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			want:            "outputDir/testAppName.dmg",
+			wantCheckedPath: "outputDir/tmp/testAppName.app",
 		},
 		{
-			name: "error when validating input parameters",
+			name: "happy path with staging",
 			params: &CreateParams{
-				AppName:          "",
+				AppName:          "testAppName",
 				AppBinaryPath:    "testAppBinaryPath",
 				BundleIdentifier: "testBundleIdentifier",
 				IconPath:         "testIconPath",
 				OutputDir:        "outputDir",
+				Staging:          true,
 			},
 			mockFsOpsProvider: func() *mockFsOpsProvider {
 				return &mockFsOpsProvider{}
@@ -64,22 +94,66 @@ func TestCreate(t *testing.T) {
 				return &mockIconUtilProvider{}
 			},
 			mockHdiutilProvider: func() *mockHdiutilProvider {
-				return &mockHdiutilProvider{}
+				// the DMG is never mounted when staging.
+				return &mockHdiutilProvider{
+					expectedCreateDMGErr: os.ErrPermission,
+					expectedMountDMGErr:  os.ErrPermission,
+				}
 			},
-			wantErr: errors.New("error when validating input parameters: AppName: AppName is a required field"),
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			want:            "outputDir/testAppName.dmg",
+			wantCheckedPath: "outputDir/tmp/testAppName.app",
 		},
 		{
-			name: "error when creating temp working directory",
+			name: "verification mismatch",
 			params: &CreateParams{
 				AppName:          "testAppName",
 				AppBinaryPath:    "testAppBinaryPath",
 				BundleIdentifier: "testBundleIdentifier",
 				IconPath:         "testIconPath",
 				OutputDir:        "outputDir",
+				Verify:           true,
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			mockBundleProvider: func() *mockBundleProvider {
+				return &mockBundleProvider{expectedInfo: &bundle.Info{Executable: "testAppName", Identifier: "testBundleIdentifier"}}
+			},
+			mockVerifyProvider: func() *mockVerifyProvider {
+				return &mockVerifyProvider{expectedErr: &verify.VerificationError{Path: "outputDir/testAppName.dmg", Mismatches: []verify.Mismatch{
+					{Path: "outputDir/testAppName.dmg:Applications", Message: "the Applications symlink is missing"},
+				}}}
+			},
+			wantErr: errors.New("error when verifying DMG: DMG [outputDir/testAppName.dmg] failed verification: " +
+				"[outputDir/testAppName.dmg:Applications] the Applications symlink is missing"),
+			wantValidatedPath: "outputDir/tmp/testAppName.app",
+			wantCheckedPath:   "outputDir/tmp/testAppName.app",
+		},
+		{
+			name: "happy path with Fyne app metadata",
+			params: &CreateParams{
+				SourcePath:         "testSourcePath",
+				OutputDir:          "outputDir",
+				UseFyneAppMetadata: true,
 			},
 			mockFsOpsProvider: func() *mockFsOpsProvider {
 				return &mockFsOpsProvider{
-					expectedMkdirAllErr: os.ErrPermission,
+					existingFiles: map[string]bool{"testSourcePath/FyneApp.toml": true},
 				}
 			},
 			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
@@ -91,12 +165,54 @@ func TestCreate(t *testing.T) {
 			mockHdiutilProvider: func() *mockHdiutilProvider {
 				return &mockHdiutilProvider{}
 			},
-			wantErr: errors.Wrap(os.ErrPermission, "error when creating temp working directory"),
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			mockFyneAppProvider: func() *mockFyneAppProvider {
+				return &mockFyneAppProvider{
+					expectedMetadata: &fyneapp.Metadata{
+						Details: fyneapp.Details{Icon: "Icon.png", Name: "fyneAppName", ID: "fyneAppID"},
+					},
+				}
+			},
+			want:            "outputDir/fyneAppName.dmg",
+			wantCheckedPath: "outputDir/tmp/fyneAppName.app",
 		},
 		{
-			name: "error when creating app bundle",
+			name: "error when applying Fyne app metadata",
 			params: &CreateParams{
-				AppName:          "testAppName",
+				SourcePath:         "testSourcePath",
+				OutputDir:          "outputDir",
+				UseFyneAppMetadata: true,
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					existingFiles: map[string]bool{"testSourcePath/FyneApp.toml": true},
+				}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			mockFyneAppProvider: func() *mockFyneAppProvider {
+				return &mockFyneAppProvider{
+					expectedLoadErr: os.ErrPermission,
+				}
+			},
+			wantErr: errors.Wrap(os.ErrPermission, "error when applying Fyne app metadata"),
+		},
+		{
+			name: "error when validating input parameters",
+			params: &CreateParams{
+				AppName:          "",
 				AppBinaryPath:    "testAppBinaryPath",
 				BundleIdentifier: "testBundleIdentifier",
 				IconPath:         "testIconPath",
@@ -106,9 +222,7 @@ func TestCreate(t *testing.T) {
 				return &mockFsOpsProvider{}
 			},
 			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
-				return &mockSipsUtilityProvider{
-					expectedGenerateIconsErr: os.ErrPermission,
-				}
+				return &mockSipsUtilityProvider{}
 			},
 			mockIconUtilProvider: func() *mockIconUtilProvider {
 				return &mockIconUtilProvider{}
@@ -116,13 +230,16 @@ func TestCreate(t *testing.T) {
 			mockHdiutilProvider: func() *mockHdiutilProvider {
 				return &mockHdiutilProvider{}
 			},
-			wantErr: errors.Wrap(os.ErrPermission, "error when creating app bundle: error when creating icon set: error when generating icons"),
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.New("error when validating input parameters: AppName: AppName is a required field"),
 		},
 		{
-			name: "error when creating app DMG",
+			name: "happy path with per-architecture binaries",
 			params: &CreateParams{
 				AppName:          "testAppName",
-				AppBinaryPath:    "testAppBinaryPath",
+				AppBinaryPaths:   []string{"testAppBinaryPath-arm64", "testAppBinaryPath-amd64"},
 				BundleIdentifier: "testBundleIdentifier",
 				IconPath:         "testIconPath",
 				OutputDir:        "outputDir",
@@ -137,52 +254,24 @@ func TestCreate(t *testing.T) {
 				return &mockIconUtilProvider{}
 			},
 			mockHdiutilProvider: func() *mockHdiutilProvider {
-				return &mockHdiutilProvider{
-					expectedMountDMGErr: os.ErrPermission,
-				}
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
 			},
-			wantErr: errors.Wrap(os.ErrPermission, "error when creating app DMG: error when mounting DMG template"),
+			want:            "outputDir/testAppName.dmg",
+			wantCheckedPath: "outputDir/tmp/testAppName.app",
 		},
-	}
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			fsOpsProvider = tc.mockFsOpsProvider()
-			sipsUtilityProvider = tc.mockSipsUtilityProvider()
-			iconUtilProvider = tc.mockIconUtilProvider()
-			hdiutilProvider = tc.mockHdiutilProvider()
-
-			got, err := Create(tc.params)
-			if err != nil {
-				if tc.wantErr == nil {
-					t.Fatalf(`expected no error, got "%v"`, err)
-				}
-				if tc.wantErr.Error() != err.Error() {
-					t.Fatalf(`expected error "%v", got "%v"`, tc.wantErr, err)
-				}
-			} else {
-				if tc.wantErr != nil {
-					t.Fatalf(`expected error "%v", got nil`, tc.wantErr)
-				}
-			}
-			if got != tc.want {
-				t.Fatalf(`expected DMG file path "%s", got "%s"`, tc.want, got)
-			}
-
-		})
-	}
-}
-
-func Test_createAppBundle(t *testing.T) {
-	testCases := []struct {
-		name                    string
-		mockFsOpsProvider       func() *mockFsOpsProvider
-		mockSipsUtilityProvider func() *mockSipsUtilityProvider
-		mockIconUtilProvider    func() *mockIconUtilProvider
-		want                    string
-		wantErr                 error
-	}{
 		{
-			name: "happy path",
+			name: "error when validating both binary path and per-architecture binaries",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				AppBinaryPath:    "testAppBinaryPath",
+				AppBinaryPaths:   []string{"testAppBinaryPath-arm64", "testAppBinaryPath-amd64"},
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+			},
 			mockFsOpsProvider: func() *mockFsOpsProvider {
 				return &mockFsOpsProvider{}
 			},
@@ -192,14 +281,25 @@ func Test_createAppBundle(t *testing.T) {
 			mockIconUtilProvider: func() *mockIconUtilProvider {
 				return &mockIconUtilProvider{}
 			},
-			want: "testOutputDir/testAppName.app",
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.New("error when validating input parameters: AppBinaryPath: AppBinaryPath is an excluded field"),
 		},
 		{
-			name: "error when creating directories",
+			name: "error when creating universal binary",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				AppBinaryPaths:   []string{"testAppBinaryPath-arm64", "testAppBinaryPath-arm64"},
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+			},
 			mockFsOpsProvider: func() *mockFsOpsProvider {
-				return &mockFsOpsProvider{
-					expectedMkdirAllErr: os.ErrPermission,
-				}
+				return &mockFsOpsProvider{}
 			},
 			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
 				return &mockSipsUtilityProvider{}
@@ -207,29 +307,60 @@ func Test_createAppBundle(t *testing.T) {
 			mockIconUtilProvider: func() *mockIconUtilProvider {
 				return &mockIconUtilProvider{}
 			},
-			wantErr: errors.Wrap(os.ErrPermission, "error when creating app bundle directories: error when creating directory [testOutputDir/testAppName.app]"),
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{
+					expectedCreateUniversalErr: errors.New("duplicate architecture arm64"),
+				}
+			},
+			wantErr: errors.New("error when creating universal binary: duplicate architecture arm64"),
 		},
 		{
-			name: "error when creating icon set",
+			name: "happy path with source path",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				SourcePath:       "testSourcePath",
+				Architectures:    []string{"arm64", "amd64"},
+				LDFlags:          "-s -w",
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+			},
 			mockFsOpsProvider: func() *mockFsOpsProvider {
 				return &mockFsOpsProvider{}
 			},
 			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
-				return &mockSipsUtilityProvider{
-					expectedGenerateIconsErr: os.ErrPermission,
-				}
+				return &mockSipsUtilityProvider{}
 			},
 			mockIconUtilProvider: func() *mockIconUtilProvider {
 				return &mockIconUtilProvider{}
 			},
-			wantErr: errors.Wrap(os.ErrPermission, "error when creating icon set: error when generating icons"),
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			mockGoBuildProvider: func() *mockGoBuildProvider {
+				return &mockGoBuildProvider{}
+			},
+			want:            "outputDir/testAppName.dmg",
+			wantCheckedPath: "outputDir/tmp/testAppName.app",
 		},
 		{
-			name: "error when copying app binary",
+			name: "error when validating build options without source path",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				AppBinaryPath:    "testAppBinaryPath",
+				Architectures:    []string{"arm64"},
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+			},
 			mockFsOpsProvider: func() *mockFsOpsProvider {
-				return &mockFsOpsProvider{
-					expectedCopyFileErr: os.ErrPermission,
-				}
+				return &mockFsOpsProvider{}
 			},
 			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
 				return &mockSipsUtilityProvider{}
@@ -237,14 +368,26 @@ func Test_createAppBundle(t *testing.T) {
 			mockIconUtilProvider: func() *mockIconUtilProvider {
 				return &mockIconUtilProvider{}
 			},
-			wantErr: errors.Wrap(os.ErrPermission, "error when copying app binary: error when copying file [testAppBinaryPath] to [testOutputDir/testAppName.app/Contents/MacOS]"),
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.New("error when validating input parameters: Architectures: Architectures is an excluded field"),
 		},
 		{
-			name: "error when creating Info.plist file",
+			name: "error when validating unsupported architecture",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				SourcePath:       "testSourcePath",
+				Architectures:    []string{"386"},
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+			},
 			mockFsOpsProvider: func() *mockFsOpsProvider {
-				return &mockFsOpsProvider{
-					expectedWriteFileErr: os.ErrPermission,
-				}
+				return &mockFsOpsProvider{}
 			},
 			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
 				return &mockSipsUtilityProvider{}
@@ -252,70 +395,1701 @@ func Test_createAppBundle(t *testing.T) {
 			mockIconUtilProvider: func() *mockIconUtilProvider {
 				return &mockIconUtilProvider{}
 			},
-			wantErr: errors.Wrap(os.ErrPermission, "error when creating Info.plist file: error when writing Info.plist file to [testOutputDir/testAppName.app/Contents/Info.plist]"),
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.New("error when validating input parameters: Architectures[0]: Architectures[0] must be one of [arm64 amd64]"),
 		},
-	}
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			fsOpsProvider = tc.mockFsOpsProvider()
-			sipsUtilityProvider = tc.mockSipsUtilityProvider()
-			iconUtilProvider = tc.mockIconUtilProvider()
-
-			got, err := createAppBundle(
-				"testAppName",
-				"testAppBinaryPath",
-				"testIconPath",
-				"testBundleIdentifier",
-				"testOutputDir",
-			)
-
-			if err != nil {
-				if tc.wantErr == nil {
-					t.Fatalf(`expected no error, got "%v"`, err)
-				}
-				if tc.wantErr.Error() != err.Error() {
-					t.Fatalf(`expected error "%v", got "%v"`, tc.wantErr, err)
-				}
-			} else {
-				if tc.wantErr != nil {
-					t.Fatalf(`expected error "%v", got nil`, tc.wantErr)
-				}
-			}
-
-			if got != tc.want {
-				t.Fatalf(`expected app bundle directory path "%s", got "%s"`, tc.want, got)
-			}
-		})
-	}
-}
-
-func Test_createAppBundleDirectories(t *testing.T) {
-	testCases := []struct {
-		name              string
-		mockFsOpsProvider func() *mockFsOpsProvider
-		wantErr           error
-	}{
 		{
-			name: "happy path",
+			name: "error when building app binary",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				SourcePath:       "testSourcePath",
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+			},
 			mockFsOpsProvider: func() *mockFsOpsProvider {
 				return &mockFsOpsProvider{}
 			},
-		},
-		{
-			name: "error when creating directories",
-			mockFsOpsProvider: func() *mockFsOpsProvider {
-				return &mockFsOpsProvider{
-					expectedMkdirAllErr: os.ErrPermission,
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			mockGoBuildProvider: func() *mockGoBuildProvider {
+				return &mockGoBuildProvider{
+					expectedBuildErr: errors.New("build failed"),
 				}
 			},
-			wantErr: errors.Wrap(os.ErrPermission, "error when creating directory [testOutputDir/testAppBundleDirName]"),
+			wantErr: errors.New("error when building app binary: build failed"),
 		},
-	}
-	for _, tc := range testCases {
+		{
+			name: "error when inspecting app binary",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				AppBinaryPath:    "testAppBinaryPath",
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{
+					expectedInspectErr: errors.New("[testAppBinaryPath] is not a Mach-O binary"),
+				}
+			},
+			wantErr: errors.New("error when inspecting app binary: [testAppBinaryPath] is not a Mach-O binary"),
+		},
+		{
+			name: "error when reading app binary build info",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				AppBinaryPath:    "testAppBinaryPath",
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			mockBuildInfoProvider: func() *mockBuildInfoProvider {
+				return &mockBuildInfoProvider{
+					expectedReadErr: os.ErrPermission,
+				}
+			},
+			wantErr: errors.Wrap(os.ErrPermission, "error when reading app binary build info"),
+		},
+		{
+			name: "error when validating resource without source",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				AppBinaryPath:    "testAppBinaryPath",
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+				Resources:        []BundleEntry{{Target: "images"}},
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.New("error when validating input parameters: Resources[0].Source: Source is a required field"),
+		},
+		{
+			name: "happy path with app bundle",
+			params: &CreateParams{
+				AppBundlePath: "build/MyApp.app/",
+				OutputDir:     "outputDir",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			mockBundleProvider: func() *mockBundleProvider {
+				return &mockBundleProvider{
+					expectedInfo: &bundle.Info{Name: "MyApp", Executable: "myapp", Identifier: "com.example.myapp"},
+				}
+			},
+			want:              "outputDir/MyApp.dmg",
+			wantValidatedPath: "build/MyApp.app",
+		},
+		{
+			name: "happy path with app icon as volume icon",
+			params: &CreateParams{
+				AppBundlePath:          "build/MyApp.app",
+				OutputDir:              "outputDir",
+				UseAppIconAsVolumeIcon: true,
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			mockBundleProvider: func() *mockBundleProvider {
+				return &mockBundleProvider{
+					expectedInfo: &bundle.Info{Name: "MyApp", Executable: "myapp", Identifier: "com.example.myapp", IconFile: "MyApp.icns"},
+				}
+			},
+			want:              "outputDir/MyApp.dmg",
+			wantValidatedPath: "build/MyApp.app",
+		},
+		{
+			name: "error when app bundle declares no icon to use as volume icon",
+			params: &CreateParams{
+				AppBundlePath:          "build/MyApp.app",
+				OutputDir:              "outputDir",
+				UseAppIconAsVolumeIcon: true,
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			mockBundleProvider: func() *mockBundleProvider {
+				return &mockBundleProvider{
+					expectedInfo: &bundle.Info{Name: "MyApp", Executable: "myapp", Identifier: "com.example.myapp"},
+				}
+			},
+			wantErr:           errors.New("app bundle [build/MyApp.app] does not declare an icon to use as the volume icon"),
+			wantValidatedPath: "build/MyApp.app",
+		},
+		{
+			name: "error when validating volume icon options",
+			params: &CreateParams{
+				AppBundlePath:          "build/MyApp.app",
+				OutputDir:              "outputDir",
+				VolumeIconPath:         "volume.icns",
+				UseAppIconAsVolumeIcon: true,
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.New("error when validating input parameters: UseAppIconAsVolumeIcon: UseAppIconAsVolumeIcon is an excluded field"),
+		},
+		{
+			name: "error when preparing volume icon",
+			params: &CreateParams{
+				AppBundlePath:  "build/MyApp.app",
+				OutputDir:      "outputDir",
+				VolumeIconPath: "volume.png",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{expectedGenerateIconsErr: errors.New("some error")}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.New("error when preparing volume icon: error when generating icons: some error"),
+		},
+		{
+			name: "error when validating app bundle with bundle options",
+			params: &CreateParams{
+				AppBundlePath:    "build/MyApp.app",
+				BundleIdentifier: "testBundleIdentifier",
+				OutputDir:        "outputDir",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.New("error when validating input parameters: BundleIdentifier: BundleIdentifier is an excluded field"),
+		},
+		{
+			name: "error when validating app bundle",
+			params: &CreateParams{
+				AppBundlePath: "build/MyApp.app",
+				OutputDir:     "outputDir",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			mockBundleProvider: func() *mockBundleProvider {
+				return &mockBundleProvider{
+					expectedValidateErr: errors.New("executable [build/MyApp.app/Contents/MacOS/myapp] does not exist"),
+				}
+			},
+			wantErr:           errors.New("error when validating app bundle: executable [build/MyApp.app/Contents/MacOS/myapp] does not exist"),
+			wantValidatedPath: "build/MyApp.app",
+		},
+		{
+			name: "error when creating temp working directory",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				AppBinaryPath:    "testAppBinaryPath",
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					expectedMkdirAllErr: os.ErrPermission,
+				}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.Wrap(os.ErrPermission, "error when creating temp working directory"),
+		},
+		{
+			name: "error when creating app bundle",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				AppBinaryPath:    "testAppBinaryPath",
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{
+					expectedGenerateIconsErr: os.ErrPermission,
+				}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.Wrap(os.ErrPermission, "error when creating app bundle: error when creating icon set: error when generating icons"),
+		},
+		{
+			name: "error when validating package type",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				AppBinaryPath:    "testAppBinaryPath",
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+				PackageType:      "APP",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.New("error when validating input parameters: PackageType: PackageType must be 4 characters in length"),
+		},
+		{
+			name: "error when checking localizations",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				AppBinaryPath:    "testAppBinaryPath",
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+				Localizations: []Localization{
+					{Language: "fr", Strings: map[string]string{"NSCameraUsageDescription": "Scanne des codes QR."}},
+				},
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.New("error when checking localizations: NSCameraUsageDescription is localized for [fr] but has no usage description to localize"),
+		},
+		{
+			name: "happy path with launch agent and login item",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				AppBinaryPath:    "testAppBinaryPath",
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+				Helpers:          []BundleEntry{{Source: "testAgentPath"}},
+				LaunchAgents:     []LaunchAgent{{Label: "testBundleIdentifier.agent", Program: "Contents/MacOS/testAgentPath", RunAtLoad: true}},
+				LoginItems:       []BundleEntry{{Source: "testHelper.app"}},
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					expectedGlobMatches: map[string][]string{
+						"testAgentPath":  {"testAgentPath"},
+						"testHelper.app": {"testHelper.app"},
+						"outputDir/tmp/testAppName.app/Contents/Library/LoginItems/*": {"outputDir/tmp/testAppName.app/Contents/Library/LoginItems/testHelper.app"},
+					},
+					existingFiles: map[string]bool{"outputDir/tmp/testAppName.app/Contents/MacOS/testAgentPath": true},
+				}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			mockBundleProvider: func() *mockBundleProvider {
+				return &mockBundleProvider{expectedInfo: &bundle.Info{Identifier: "testBundleIdentifier.helper"}}
+			},
+			want:              "outputDir/testAppName.dmg",
+			wantValidatedPath: "outputDir/tmp/testAppName.app/Contents/Library/LoginItems/testHelper.app",
+			wantCheckedPath:   "outputDir/tmp/testAppName.app",
+		},
+		{
+			name: "error when checking launch agents",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				AppBinaryPath:    "testAppBinaryPath",
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+				LaunchAgents:     []LaunchAgent{{Label: "otherBundleIdentifier.agent", Program: "Contents/MacOS/testAgentPath"}},
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.New("error when checking launch agents: launch agent label [otherBundleIdentifier.agent] must start with the bundle identifier [testBundleIdentifier] followed by a dot"),
+		},
+		{
+			name: "happy path with script",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				ScriptPath:       "scripts/run.py",
+				RunInTerminal:    true,
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					expectedGlobMatches: map[string][]string{"scripts/run.py": {"scripts/run.py"}},
+				}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{
+					expectedInspectErr: errors.New("a script is not a Mach-O binary"),
+				}
+			},
+			want:            "outputDir/testAppName.dmg",
+			wantCheckedPath: "outputDir/tmp/testAppName.app",
+		},
+		{
+			name: "happy path with command-line tool",
+			params: &CreateParams{
+				AppName:         "testAppName",
+				AppBinaryPath:   "testAppBinaryPath",
+				CommandLineTool: true,
+				ManPages:        []string{"docs/testAppName.1"},
+				ZshCompletion:   "completions/_testAppName",
+				OutputDir:       "outputDir",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			want: "outputDir/testAppName.dmg",
+		},
+		{
+			name: "error when validating command-line tool with bundle identifier",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				AppBinaryPath:    "testAppBinaryPath",
+				CommandLineTool:  true,
+				BundleIdentifier: "testBundleIdentifier",
+				OutputDir:        "outputDir",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.New("error when validating input parameters: BundleIdentifier: BundleIdentifier is an excluded field"),
+		},
+		{
+			name: "error when validating man pages without command-line tool",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				AppBinaryPath:    "testAppBinaryPath",
+				ManPages:         []string{"docs/testAppName.1"},
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.New("error when validating input parameters: ManPages: ManPages is an excluded field"),
+		},
+		{
+			name: "error when creating command-line tool with invalid man page",
+			params: &CreateParams{
+				AppName:         "testAppName",
+				AppBinaryPath:   "testAppBinaryPath",
+				CommandLineTool: true,
+				ManPages:        []string{"docs/testAppName.md"},
+				OutputDir:       "outputDir",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.New("error when creating tool DMG: [docs/testAppName.md] is not a man page: its extension must be its section, e.g. .1"),
+		},
+		{
+			name: "error when mounting command-line tool DMG template",
+			params: &CreateParams{
+				AppName:         "testAppName",
+				AppBinaryPath:   "testAppBinaryPath",
+				CommandLineTool: true,
+				OutputDir:       "outputDir",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{
+					expectedMountDMGErr: os.ErrPermission,
+				}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.Wrap(os.ErrPermission, "error when creating tool DMG: error when mounting DMG template"),
+		},
+		{
+			name: "error when validating script with app binary",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				AppBinaryPath:    "testAppBinaryPath",
+				ScriptPath:       "scripts/run.py",
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.New("error when validating input parameters: AppBinaryPath: AppBinaryPath is an excluded field"),
+		},
+		{
+			name: "error when validating terminal without script",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				AppBinaryPath:    "testAppBinaryPath",
+				RunInTerminal:    true,
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.New("error when validating input parameters: RunInTerminal: RunInTerminal is an excluded field"),
+		},
+		{
+			name: "error when validating window layout",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				AppBinaryPath:    "testAppBinaryPath",
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+				Window:           &WindowLayout{IconSize: 1024},
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.New("error when validating input parameters: Window.IconSize: IconSize must be 512 or less"),
+		},
+		{
+			name: "happy path with background image",
+			params: &CreateParams{
+				AppName:               "testAppName",
+				AppBinaryPath:         "testAppBinaryPath",
+				BundleIdentifier:      "testBundleIdentifier",
+				IconPath:              "testIconPath",
+				OutputDir:             "outputDir",
+				BackgroundImagePath:   "background.png",
+				BackgroundImage2xPath: "background@2x.png",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			mockImageProvider: func() *mockImageProvider {
+				return &mockImageProvider{expectedWidth: 660, expectedHeight: 400}
+			},
+			want:            "outputDir/testAppName.dmg",
+			wantCheckedPath: "outputDir/tmp/testAppName.app",
+		},
+		{
+			name: "error when validating 2x background image",
+			params: &CreateParams{
+				AppName:               "testAppName",
+				AppBinaryPath:         "testAppBinaryPath",
+				BundleIdentifier:      "testBundleIdentifier",
+				IconPath:              "testIconPath",
+				OutputDir:             "outputDir",
+				BackgroundImage2xPath: "background@2x.png",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.New("error when validating input parameters: BackgroundImage2xPath: BackgroundImage2xPath is an excluded field"),
+		},
+		{
+			name: "error when preparing background image",
+			params: &CreateParams{
+				AppName:             "testAppName",
+				AppBinaryPath:       "testAppBinaryPath",
+				BundleIdentifier:    "testBundleIdentifier",
+				IconPath:            "testIconPath",
+				OutputDir:           "outputDir",
+				BackgroundImagePath: "background.png",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			mockImageProvider: func() *mockImageProvider {
+				return &mockImageProvider{expectedSizeErr: errors.New("image: unknown format")}
+			},
+			wantErr: errors.New("error when preparing background image: error when reading the size of the background image: image: unknown format"),
+		},
+		{
+			name: "error when validating executable name",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				AppBinaryPath:    "testAppBinaryPath",
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+				ExecutableName:   "bin/testAppName",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.New("error when validating input parameters: ExecutableName: ExecutableName cannot contain any of the following characters '/'"),
+		},
+		{
+			name: "error when deriving executable name",
+			params: &CreateParams{
+				AppName:          "..",
+				AppBinaryPath:    "testAppBinaryPath",
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.New("cannot derive an executable name from the app name [..]; set the executable name"),
+		},
+		{
+			name: "error when setting app bundle file modes",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				AppBinaryPath:    "testAppBinaryPath",
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			mockBundleProvider: func() *mockBundleProvider {
+				return &mockBundleProvider{
+					expectedNormalizeModesErr: os.ErrPermission,
+				}
+			},
+			wantErr: errors.Wrap(os.ErrPermission, "error when setting app bundle file modes"),
+		},
+		{
+			name: "error when checking app bundle layout",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				AppBinaryPath:    "testAppBinaryPath",
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			mockBundleProvider: func() *mockBundleProvider {
+				return &mockBundleProvider{
+					expectedCheckLayoutErr: errors.New("unexpected [README] at the top level of [outputDir/tmp/testAppName.app]: everything must be inside Contents"),
+				}
+			},
+			wantErr:         errors.New("error when checking app bundle layout: unexpected [README] at the top level of [outputDir/tmp/testAppName.app]: everything must be inside Contents"),
+			wantCheckedPath: "outputDir/tmp/testAppName.app",
+		},
+		{
+			name: "happy path with license",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				AppBinaryPath:    "testAppBinaryPath",
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+				Licenses:         []License{{Language: "en", Path: "LICENSE"}},
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{fileContents: map[string][]byte{"LICENSE": []byte("MIT License")}}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			want:            "outputDir/testAppName.dmg",
+			wantCheckedPath: "outputDir/tmp/testAppName.app",
+		},
+		{
+			name: "zlib level of other format",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				AppBinaryPath:    "testAppBinaryPath",
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+				Format:           "ULMO",
+				ZlibLevel:        9,
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.New("error when validating input parameters: the zlib level only applies to the UDZO format, not to ULMO"),
+		},
+		{
+			name: "APFS with Apple partition map",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				AppBinaryPath:    "testAppBinaryPath",
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+				Filesystem:       "Case-sensitive APFS",
+				Layout:           "SPUD",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.New("error when validating input parameters: the Case-sensitive APFS filesystem needs the GPTSPUD layout or none, not SPUD"),
+		},
+		{
+			name: "encryption without passphrase",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				AppBinaryPath:    "testAppBinaryPath",
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+				Encryption:       "AES-256",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.New("error when validating input parameters: Passphrase: Passphrase is a required field"),
+		},
+		{
+			name: "passphrase without encryption",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				AppBinaryPath:    "testAppBinaryPath",
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+				Passphrase:       "secret",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.New("error when validating input parameters: Passphrase: Passphrase is an excluded field"),
+		},
+		{
+			name: "invalid format",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				AppBinaryPath:    "testAppBinaryPath",
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+				Format:           "UDIF",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.New("error when validating input parameters: Format: Format must be one of [UDZO UDBZ ULFO ULMO UDRO UDRW]"),
+		},
+		{
+			name: "invalid license",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				AppBinaryPath:    "testAppBinaryPath",
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+				Licenses:         []License{{Language: "en"}},
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.New("error when validating input parameters: Licenses[0].Path: Path is a required field"),
+		},
+		{
+			name: "error when encoding license",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				AppBinaryPath:    "testAppBinaryPath",
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+				Licenses:         []License{{Language: "el", Path: "LICENSE"}},
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.New("error when encoding license agreement: unsupported license language [el]"),
+		},
+		{
+			name: "error when attaching license",
+			params: &CreateParams{
+				CommandLineTool: true,
+				AppBinaryPath:   "testAppBinaryPath",
+				AppName:         "testAppName",
+				OutputDir:       "outputDir",
+				Licenses:        []License{{Language: "en", Path: "LICENSE"}},
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			mockUdifProvider: func() *mockUdifProvider {
+				return &mockUdifProvider{expectedSetResourcesErr: errors.New("not a UDIF disk image: koly trailer not found")}
+			},
+			wantErr: errors.New("error when attaching license agreement: not a UDIF disk image: koly trailer not found"),
+		},
+		{
+			name: "error when creating app DMG",
+			params: &CreateParams{
+				AppName:          "testAppName",
+				AppBinaryPath:    "testAppBinaryPath",
+				BundleIdentifier: "testBundleIdentifier",
+				IconPath:         "testIconPath",
+				OutputDir:        "outputDir",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{
+					expectedMountDMGErr: os.ErrPermission,
+				}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr:         errors.Wrap(os.ErrPermission, "error when creating app DMG: error when mounting DMG template"),
+			wantCheckedPath: "outputDir/tmp/testAppName.app",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fsOpsProvider = tc.mockFsOpsProvider()
+			sipsUtilityProvider = tc.mockSipsUtilityProvider()
+			iconUtilProvider = tc.mockIconUtilProvider()
+			hdiutilProvider = tc.mockHdiutilProvider()
+			machoProvider = tc.mockMachoProvider()
+			goBuildProvider = &mockGoBuildProvider{}
+			if tc.mockGoBuildProvider != nil {
+				goBuildProvider = tc.mockGoBuildProvider()
+			}
+			buildInfoProvider = &mockBuildInfoProvider{}
+			if tc.mockBuildInfoProvider != nil {
+				buildInfoProvider = tc.mockBuildInfoProvider()
+			}
+			fyneAppProvider = &mockFyneAppProvider{}
+			if tc.mockFyneAppProvider != nil {
+				fyneAppProvider = tc.mockFyneAppProvider()
+			}
+			mockBundleProvider := &mockBundleProvider{}
+			if tc.mockBundleProvider != nil {
+				mockBundleProvider = tc.mockBundleProvider()
+			}
+			bundleProvider = mockBundleProvider
+			codesignProvider = &mockCodesignProvider{}
+			scriptProvider = &mockScriptProvider{expectedInfo: &script.Info{Interpreter: "/bin/sh"}}
+			xattrProvider = &mockXattrProvider{}
+			imageProvider = &mockImageProvider{}
+			if tc.mockImageProvider != nil {
+				imageProvider = tc.mockImageProvider()
+			}
+			udifProvider = &mockUdifProvider{}
+			if tc.mockUdifProvider != nil {
+				udifProvider = tc.mockUdifProvider()
+			}
+			verifyProvider = &mockVerifyProvider{}
+			if tc.mockVerifyProvider != nil {
+				verifyProvider = tc.mockVerifyProvider()
+			}
+
+			got, err := Create(tc.params)
+			if err != nil {
+				if tc.wantErr == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				if tc.wantErr.Error() != err.Error() {
+					t.Fatalf(`expected error "%v", got "%v"`, tc.wantErr, err)
+				}
+			} else {
+				if tc.wantErr != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.wantErr)
+				}
+			}
+			var gotPath string
+			if got != nil {
+				gotPath = got.Path
+			}
+			if gotPath != tc.want {
+				t.Fatalf(`expected DMG file path "%s", got "%s"`, tc.want, gotPath)
+			}
+			if mockBundleProvider.validatedPath != tc.wantValidatedPath {
+				t.Fatalf(`expected validated app bundle path "%s", got "%s"`, tc.wantValidatedPath, mockBundleProvider.validatedPath)
+			}
+			if mockBundleProvider.checkedPath != tc.wantCheckedPath {
+				t.Fatalf(`expected checked app bundle path "%s", got "%s"`, tc.wantCheckedPath, mockBundleProvider.checkedPath)
+			}
+		})
+	}
+}
+
+func Test_createAppBundle(t *testing.T) {
+	testCases := []struct {
+		name                    string
+		mockFsOpsProvider       func() *mockFsOpsProvider
+		mockSipsUtilityProvider func() *mockSipsUtilityProvider
+		mockIconUtilProvider    func() *mockIconUtilProvider
+		want                    string
+		wantErr                 error
+	}{
+		{
+			name: "happy path",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			want: "testOutputDir/testAppName.app",
+		},
+		{
+			name: "error when creating directories",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					expectedMkdirAllErr: os.ErrPermission,
+				}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			wantErr: errors.Wrap(os.ErrPermission, "error when creating app bundle directories: error when creating directory [testOutputDir/testAppName.app]"),
+		},
+		{
+			name: "error when creating icon set",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{
+					expectedGenerateIconsErr: os.ErrPermission,
+				}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			wantErr: errors.Wrap(os.ErrPermission, "error when creating icon set: error when generating icons"),
+		},
+		{
+			name: "error when copying app binary",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					expectedCopyFileErr: os.ErrPermission,
+				}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			wantErr: errors.Wrap(os.ErrPermission, "error when copying app binary: error when copying file [testAppBinaryPath] to [testOutputDir/testAppName.app/Contents/MacOS/testExecutableName]"),
+		},
+		{
+			name: "error when creating Info.plist file",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					expectedWriteFileErr: os.ErrPermission,
+				}
+			},
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			wantErr: errors.Wrap(os.ErrPermission, "error when creating Info.plist file: error when writing Info.plist file to [testOutputDir/testAppName.app/Contents/Info.plist]"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fsOpsProvider = tc.mockFsOpsProvider()
+			sipsUtilityProvider = tc.mockSipsUtilityProvider()
+			iconUtilProvider = tc.mockIconUtilProvider()
+
+			got, err := createAppBundle(
+				"testAppName",
+				"testAppBinaryPath",
+				"testIconPath",
+				&infoPlistData{
+					Executable:           "testExecutableName",
+					BundleIdentifier:     "testBundleIdentifier",
+					MinimumSystemVersion: "11.0",
+				},
+				nil,
+				nil,
+				"testOutputDir",
+			)
+
+			if err != nil {
+				if tc.wantErr == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				if tc.wantErr.Error() != err.Error() {
+					t.Fatalf(`expected error "%v", got "%v"`, tc.wantErr, err)
+				}
+			} else {
+				if tc.wantErr != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.wantErr)
+				}
+			}
+
+			if got != tc.want {
+				t.Fatalf(`expected app bundle directory path "%s", got "%s"`, tc.want, got)
+			}
+		})
+	}
+}
+
+func Test_createAppBundleDirectories(t *testing.T) {
+	testCases := []struct {
+		name              string
+		mockFsOpsProvider func() *mockFsOpsProvider
+		wantErr           error
+	}{
+		{
+			name: "happy path",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+		},
+		{
+			name: "error when creating directories",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					expectedMkdirAllErr: os.ErrPermission,
+				}
+			},
+			wantErr: errors.Wrap(os.ErrPermission, "error when creating directory [testOutputDir/testAppBundleDirName]"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fsOpsProvider = tc.mockFsOpsProvider()
+
+			err := createAppBundleDirectories("testAppBundleDirName", "testOutputDir")
+
+			if err != nil {
+				if tc.wantErr == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				if tc.wantErr.Error() != err.Error() {
+					t.Fatalf(`expected error "%v", got "%v"`, tc.wantErr, err)
+				}
+			} else {
+				if tc.wantErr != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func Test_createIconSet(t *testing.T) {
+	testCases := []struct {
+		name                    string
+		mockSipsUtilityProvider func() *mockSipsUtilityProvider
+		mockIconUtilProvider    func() *mockIconUtilProvider
+		wantErr                 error
+	}{
+		{
+			name: "happy path",
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+		},
+		{
+			name: "error when generating icons",
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{
+					expectedGenerateIconsErr: os.ErrPermission,
+				}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{}
+			},
+			wantErr: errors.Wrap(os.ErrPermission, "error when generating icons"),
+		},
+		{
+			name: "error when generating icon set",
+			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
+				return &mockSipsUtilityProvider{}
+			},
+			mockIconUtilProvider: func() *mockIconUtilProvider {
+				return &mockIconUtilProvider{
+					expectedGenerateIconSetErr: os.ErrPermission,
+				}
+			},
+			wantErr: errors.Wrap(os.ErrPermission, "error when generating icon set"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sipsUtilityProvider = tc.mockSipsUtilityProvider()
+			iconUtilProvider = tc.mockIconUtilProvider()
+
+			err := createIconSet(
+				"testIconPath",
+				"testAppBundleDirName",
+				"testAppBundleDirPath",
+			)
+
+			if err != nil {
+				if tc.wantErr == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				if tc.wantErr.Error() != err.Error() {
+					t.Fatalf(`expected error "%v", got "%v"`, tc.wantErr, err)
+				}
+			} else {
+				if tc.wantErr != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func Test_copyAppBinary(t *testing.T) {
+	testCases := []struct {
+		name              string
+		mockFsOpsProvider func() *mockFsOpsProvider
+		wantErr           error
+	}{
+		{
+			name: "happy path",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+		},
+		{
+			name: "error when copying app binary",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					expectedCopyFileErr: os.ErrPermission,
+				}
+			},
+			wantErr: errors.Wrap(os.ErrPermission, "error when copying file [testAppBinaryPath] to [testOutputDir/Contents/MacOS/testExecutableName]"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockFsOpsProvider := tc.mockFsOpsProvider()
+			fsOpsProvider = mockFsOpsProvider
+
+			err := copyAppBinary("testAppBinaryPath", "testExecutableName", "testOutputDir")
+			if err != nil {
+				if tc.wantErr == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				if tc.wantErr.Error() != err.Error() {
+					t.Fatalf(`expected error "%v", got "%v"`, tc.wantErr, err)
+				}
+			} else {
+				if tc.wantErr != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.wantErr)
+				}
+				require.Equal(t, []string{"testAppBinaryPath -> testOutputDir/Contents/MacOS/testExecutableName"}, mockFsOpsProvider.copiedPaths)
+			}
+		})
+	}
+}
+
+func Test_sanitizeExecutableName(t *testing.T) {
+	testCases := []struct {
+		appName string
+		want    string
+	}{
+		{appName: "MyApp", want: "MyApp"},
+		{appName: "My App", want: "My App"},
+		{appName: "AC/DC: Live\t", want: "AC-DC- Live"},
+		{appName: " .hidden ", want: "hidden"},
+		{appName: "..", want: ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.appName, func(t *testing.T) {
+			got := sanitizeExecutableName(tc.appName)
+			if got != tc.want {
+				t.Fatalf(`expected executable name "%s", got "%s"`, tc.want, got)
+			}
+		})
+	}
+}
+
+func Test_createInfoPlistFile(t *testing.T) {
+	testCases := []struct {
+		name              string
+		infoPlist         *infoPlistData
+		mockFsOpsProvider func() *mockFsOpsProvider
+		wantContains      []string
+		wantNotContains   []string
+		wantErr           error
+	}{
+		{
+			name: "happy path",
+			infoPlist: &infoPlistData{
+				Name:                 "Test App",
+				Executable:           "testAppName",
+				BundleIdentifier:     "testBundleIdentifier",
+				MinimumSystemVersion: "11.0",
+				ShortVersion:         "1.2.3",
+				BundleVersion:        "42",
+				VCSRevision:          "abcdef",
+				PackageType:          "APPL",
+				Signature:            "????",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			wantContains: []string{
+				"<key>CFBundleExecutable</key>\n\t<string>testAppName</string>",
+				"<key>CFBundleIdentifier</key>\n\t<string>testBundleIdentifier</string>",
+				"<key>LSMinimumSystemVersion</key>\n\t<string>11.0</string>",
+				"<key>CFBundleShortVersionString</key>\n\t<string>1.2.3</string>",
+				"<key>CFBundleVersion</key>\n\t<string>42</string>",
+				"<key>GoVCSRevision</key>\n\t<string>abcdef</string>",
+				"<key>CFBundleName</key>\n\t<string>Test App</string>",
+				"<key>CFBundleInfoDictionaryVersion</key>\n\t<string>6.0</string>",
+				"<key>CFBundlePackageType</key>\n\t<string>APPL</string>",
+				"<key>CFBundleSignature</key>\n\t<string>????</string>",
+			},
+		},
+		{
+			name: "happy path with localizations",
+			infoPlist: &infoPlistData{
+				Name:                 "Test App",
+				Executable:           "testAppName",
+				BundleIdentifier:     "testBundleIdentifier",
+				DevelopmentRegion:    "en",
+				Localizations:        []string{"en", "pt-BR"},
+				DisplayName:          "Test App",
+				LocalizedDisplayName: true,
+				UsageDescriptions: map[string]string{
+					"NSMicrophoneUsageDescription": "Records voice notes.",
+					"NSCameraUsageDescription":     "Scans <QR> codes.",
+				},
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			wantContains: []string{
+				"<key>CFBundleDisplayName</key>\n\t<string>Test App</string>",
+				"<key>LSHasLocalizedDisplayName</key>\n\t<true/>",
+				"<key>CFBundleDevelopmentRegion</key>\n\t<string>en</string>",
+				"<key>CFBundleLocalizations</key>\n\t<array>\n\t\t<string>en</string>\n\t\t<string>pt-BR</string>\n\t</array>",
+				"<key>NSCameraUsageDescription</key>\n\t<string>Scans &lt;QR&gt; codes.</string>\n\t<key>NSMicrophoneUsageDescription</key>\n\t<string>Records voice notes.</string>",
+			},
+		},
+		{
+			name: "happy path without optional values",
+			infoPlist: &infoPlistData{
+				Executable:       "testAppName",
+				BundleIdentifier: "testBundleIdentifier",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			wantNotContains: []string{"CFBundleName", "CFBundleDisplayName", "CFBundleDevelopmentRegion", "CFBundleLocalizations", "LSMinimumSystemVersion", "CFBundleShortVersionString", "CFBundleVersion", "GoVCSRevision"},
+		},
+		{
+			name: "error when writing file",
+			infoPlist: &infoPlistData{
+				Executable:       "testAppName",
+				BundleIdentifier: "testBundleIdentifier",
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					expectedWriteFileErr: os.ErrPermission,
+				}
+			},
+			wantErr: errors.Wrap(os.ErrPermission, "error when writing Info.plist file to [testOutputDir/testAppName.app/Contents/Info.plist]"),
+		},
+	}
+	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			fsOpsProvider = tc.mockFsOpsProvider()
+			mockFsOpsProvider := tc.mockFsOpsProvider()
+			fsOpsProvider = mockFsOpsProvider
 
-			err := createAppBundleDirectories("testAppBundleDirName", "testOutputDir")
+			err := createInfoPlistFile(
+				tc.infoPlist,
+				"testAppName.app",
+				"testOutputDir",
+			)
 
 			if err != nil {
 				if tc.wantErr == nil {
@@ -328,62 +2102,393 @@ func Test_createAppBundleDirectories(t *testing.T) {
 				if tc.wantErr != nil {
 					t.Fatalf(`expected error "%v", got nil`, tc.wantErr)
 				}
+				infoPlist := string(mockFsOpsProvider.writtenFiles["testOutputDir/testAppName.app/Contents/Info.plist"])
+				for _, want := range tc.wantContains {
+					if !strings.Contains(infoPlist, want) {
+						t.Fatalf(`expected Info.plist to contain "%s", got "%s"`, want, infoPlist)
+					}
+				}
+				for _, notWant := range tc.wantNotContains {
+					if strings.Contains(infoPlist, notWant) {
+						t.Fatalf(`expected Info.plist not to contain "%s", got "%s"`, notWant, infoPlist)
+					}
+				}
 			}
 		})
 	}
 }
 
-func Test_createIconSet(t *testing.T) {
+func Test_createPkgInfoFile(t *testing.T) {
 	testCases := []struct {
-		name                    string
-		mockSipsUtilityProvider func() *mockSipsUtilityProvider
-		mockIconUtilProvider    func() *mockIconUtilProvider
-		wantErr                 error
+		name              string
+		mockFsOpsProvider func() *mockFsOpsProvider
+		want              string
+		wantErr           error
 	}{
 		{
 			name: "happy path",
-			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
-				return &mockSipsUtilityProvider{}
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
 			},
-			mockIconUtilProvider: func() *mockIconUtilProvider {
-				return &mockIconUtilProvider{}
+			want: "APPLTAPP",
+		},
+		{
+			name: "error when writing file",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					expectedWriteFileErr: os.ErrPermission,
+				}
 			},
+			wantErr: errors.Wrap(os.ErrPermission, "error when writing PkgInfo file to [testOutputDir/testAppName.app/Contents/PkgInfo]"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockFsOpsProvider := tc.mockFsOpsProvider()
+			fsOpsProvider = mockFsOpsProvider
+
+			err := createPkgInfoFile(
+				&infoPlistData{PackageType: "APPL", Signature: "TAPP"},
+				"testAppName.app",
+				"testOutputDir",
+			)
+
+			if err != nil {
+				if tc.wantErr == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				if tc.wantErr.Error() != err.Error() {
+					t.Fatalf(`expected error "%v", got "%v"`, tc.wantErr, err)
+				}
+			} else {
+				if tc.wantErr != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.wantErr)
+				}
+				got := string(mockFsOpsProvider.writtenFiles["testOutputDir/testAppName.app/Contents/PkgInfo"])
+				if got != tc.want {
+					t.Fatalf(`expected PkgInfo "%s", got "%s"`, tc.want, got)
+				}
+			}
+		})
+	}
+}
+
+func Test_applyBuildInfo(t *testing.T) {
+	testCases := []struct {
+		name        string
+		infoPlist   *infoPlistData
+		buildInfo   *buildinfo.Info
+		want        *infoPlistData
+		wantWarning string
+	}{
+		{
+			name:      "not a Go binary",
+			infoPlist: &infoPlistData{},
+			want:      &infoPlistData{},
 		},
 		{
-			name: "error when generating icons",
-			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
-				return &mockSipsUtilityProvider{
-					expectedGenerateIconsErr: os.ErrPermission,
+			name:      "release build",
+			infoPlist: &infoPlistData{},
+			buildInfo: &buildinfo.Info{ModuleVersion: "v1.2.3", VCSRevision: "abcdef"},
+			want:      &infoPlistData{ShortVersion: "1.2.3", BundleVersion: "1.2.3", VCSRevision: "abcdef"},
+		},
+		{
+			name:      "explicit versions are kept",
+			infoPlist: &infoPlistData{ShortVersion: "2.0", BundleVersion: "200"},
+			buildInfo: &buildinfo.Info{ModuleVersion: "v1.2.3", VCSRevision: "abcdef"},
+			want:      &infoPlistData{ShortVersion: "2.0", BundleVersion: "200", VCSRevision: "abcdef"},
+		},
+		{
+			name:        "dirty development build",
+			infoPlist:   &infoPlistData{},
+			buildInfo:   &buildinfo.Info{ModuleVersion: "(devel)", VCSRevision: "abcdef", VCSModified: true},
+			want:        &infoPlistData{VCSRevision: "abcdef"},
+			wantWarning: "⚠ warning: the application binary was built from a working tree with uncommitted changes (revision abcdef)\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var output bytes.Buffer
+			warningOutput = &output
+
+			applyBuildInfo(tc.infoPlist, tc.buildInfo)
+
+			require.Equal(t, tc.want, tc.infoPlist)
+			if output.String() != tc.wantWarning {
+				t.Fatalf(`expected warning "%s", got "%s"`, tc.wantWarning, output.String())
+			}
+		})
+	}
+}
+
+func Test_applyFyneAppMetadata(t *testing.T) {
+	metadata := &fyneapp.Metadata{
+		Details: fyneapp.Details{
+			Icon:    "testSourcePath/Icon.png",
+			Name:    "fyneAppName",
+			ID:      "fyneAppID",
+			Version: "1.2.3",
+			Build:   42,
+		},
+	}
+	testCases := []struct {
+		name                string
+		params              *CreateParams
+		mockFsOpsProvider   func() *mockFsOpsProvider
+		mockFyneAppProvider func() *mockFyneAppProvider
+		want                *CreateParams
+		wantLoadedPath      string
+		wantErr             error
+	}{
+		{
+			name: "happy path",
+			params: &CreateParams{
+				SourcePath:         "testSourcePath",
+				OutputDir:          "outputDir",
+				UseFyneAppMetadata: true,
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					expectedFileExists: true,
 				}
 			},
-			mockIconUtilProvider: func() *mockIconUtilProvider {
-				return &mockIconUtilProvider{}
+			mockFyneAppProvider: func() *mockFyneAppProvider {
+				return &mockFyneAppProvider{
+					expectedMetadata: metadata,
+				}
 			},
-			wantErr: errors.Wrap(os.ErrPermission, "error when generating icons"),
+			want: &CreateParams{
+				AppName:            "fyneAppName",
+				SourcePath:         "testSourcePath",
+				BundleIdentifier:   "fyneAppID",
+				IconPath:           "testSourcePath/Icon.png",
+				OutputDir:          "outputDir",
+				ShortVersion:       "1.2.3",
+				BundleVersion:      "42",
+				UseFyneAppMetadata: true,
+			},
+			wantLoadedPath: "testSourcePath/FyneApp.toml",
+		},
+		{
+			name: "explicit values take precedence",
+			params: &CreateParams{
+				AppName:            "testAppName",
+				AppBinaryPath:      "bin/testAppBinaryPath",
+				BundleIdentifier:   "testBundleIdentifier",
+				IconPath:           "testIconPath",
+				ShortVersion:       "2.0.0",
+				BundleVersion:      "200",
+				OutputDir:          "outputDir",
+				UseFyneAppMetadata: true,
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					expectedFileExists: true,
+				}
+			},
+			mockFyneAppProvider: func() *mockFyneAppProvider {
+				return &mockFyneAppProvider{
+					expectedMetadata: metadata,
+				}
+			},
+			want: &CreateParams{
+				AppName:            "testAppName",
+				AppBinaryPath:      "bin/testAppBinaryPath",
+				BundleIdentifier:   "testBundleIdentifier",
+				IconPath:           "testIconPath",
+				ShortVersion:       "2.0.0",
+				BundleVersion:      "200",
+				OutputDir:          "outputDir",
+				UseFyneAppMetadata: true,
+			},
+			wantLoadedPath: "bin/FyneApp.toml",
+		},
+		{
+			name: "FyneApp.toml not found",
+			params: &CreateParams{
+				AppBinaryPaths:     []string{"bin/arm64/testAppBinaryPath", "bin/amd64/testAppBinaryPath"},
+				UseFyneAppMetadata: true,
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockFyneAppProvider: func() *mockFyneAppProvider {
+				return &mockFyneAppProvider{}
+			},
+			wantErr: errors.New("FyneApp.toml not found in [bin/arm64]"),
+		},
+		{
+			name: "no source nor binary",
+			params: &CreateParams{
+				UseFyneAppMetadata: true,
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockFyneAppProvider: func() *mockFyneAppProvider {
+				return &mockFyneAppProvider{}
+			},
+			wantErr: errors.New("a source path or an app binary path is required to find FyneApp.toml"),
+		},
+		{
+			name: "error when checking if FyneApp.toml exists",
+			params: &CreateParams{
+				SourcePath:         "testSourcePath",
+				UseFyneAppMetadata: true,
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					expectedFileExistsErr: os.ErrPermission,
+				}
+			},
+			mockFyneAppProvider: func() *mockFyneAppProvider {
+				return &mockFyneAppProvider{}
+			},
+			wantErr: errors.Wrap(os.ErrPermission, "error when checking if [testSourcePath/FyneApp.toml] exists"),
+		},
+		{
+			name: "error when loading FyneApp.toml",
+			params: &CreateParams{
+				SourcePath:         "testSourcePath",
+				UseFyneAppMetadata: true,
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					expectedFileExists: true,
+				}
+			},
+			mockFyneAppProvider: func() *mockFyneAppProvider {
+				return &mockFyneAppProvider{
+					expectedLoadErr: os.ErrPermission,
+				}
+			},
+			wantErr: os.ErrPermission,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fsOpsProvider = tc.mockFsOpsProvider()
+			mockFyneAppProvider := tc.mockFyneAppProvider()
+			fyneAppProvider = mockFyneAppProvider
+
+			got, err := applyFyneAppMetadata(tc.params)
+			if err != nil {
+				if tc.wantErr == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				if tc.wantErr.Error() != err.Error() {
+					t.Fatalf(`expected error "%v", got "%v"`, tc.wantErr, err)
+				}
+				return
+			}
+			if tc.wantErr != nil {
+				t.Fatalf(`expected error "%v", got nil`, tc.wantErr)
+			}
+			require.Equal(t, tc.want, got)
+			require.Equal(t, tc.wantLoadedPath, mockFyneAppProvider.loadedPath)
+		})
+	}
+}
+
+func Test_buildAppBinary(t *testing.T) {
+	testCases := []struct {
+		name                string
+		architectures       []string
+		mockFsOpsProvider   func() *mockFsOpsProvider
+		mockGoBuildProvider func() *mockGoBuildProvider
+		mockMachoProvider   func() *mockMachoProvider
+		want                string
+		wantBuiltArchs      []string
+		wantErr             error
+	}{
+		{
+			name: "happy path with default architecture",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockGoBuildProvider: func() *mockGoBuildProvider {
+				return &mockGoBuildProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			want:           "tmpWorkDir/build/arm64/testAppName",
+			wantBuiltArchs: []string{"arm64"},
+		},
+		{
+			name:          "happy path with several architectures",
+			architectures: []string{"arm64", "amd64"},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockGoBuildProvider: func() *mockGoBuildProvider {
+				return &mockGoBuildProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			want:           "tmpWorkDir/testAppName",
+			wantBuiltArchs: []string{"arm64", "amd64"},
+		},
+		{
+			name: "error when creating build directory",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					expectedMkdirAllErr: os.ErrPermission,
+				}
+			},
+			mockGoBuildProvider: func() *mockGoBuildProvider {
+				return &mockGoBuildProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: errors.Wrap(os.ErrPermission, "error when creating directory [tmpWorkDir/build/arm64]"),
+		},
+		{
+			name: "error when building",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockGoBuildProvider: func() *mockGoBuildProvider {
+				return &mockGoBuildProvider{
+					expectedBuildErr: os.ErrPermission,
+				}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
+			},
+			wantErr: os.ErrPermission,
 		},
 		{
-			name: "error when generating icon set",
-			mockSipsUtilityProvider: func() *mockSipsUtilityProvider {
-				return &mockSipsUtilityProvider{}
+			name:          "error when creating universal binary",
+			architectures: []string{"arm64", "amd64"},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
 			},
-			mockIconUtilProvider: func() *mockIconUtilProvider {
-				return &mockIconUtilProvider{
-					expectedGenerateIconSetErr: os.ErrPermission,
+			mockGoBuildProvider: func() *mockGoBuildProvider {
+				return &mockGoBuildProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{
+					expectedCreateUniversalErr: os.ErrPermission,
 				}
 			},
-			wantErr: errors.Wrap(os.ErrPermission, "error when generating icon set"),
+			wantErr: os.ErrPermission,
 		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			sipsUtilityProvider = tc.mockSipsUtilityProvider()
-			iconUtilProvider = tc.mockIconUtilProvider()
+			fsOpsProvider = tc.mockFsOpsProvider()
+			mockGoBuildProvider := tc.mockGoBuildProvider()
+			goBuildProvider = mockGoBuildProvider
+			machoProvider = tc.mockMachoProvider()
 
-			err := createIconSet(
-				"testIconPath",
-				"testAppBundleDirName",
-				"testAppBundleDirPath",
-			)
+			got, err := buildAppBinary(&CreateParams{
+				AppName:       "testAppName",
+				SourcePath:    "testSourcePath",
+				Architectures: tc.architectures,
+			}, "tmpWorkDir")
 
 			if err != nil {
 				if tc.wantErr == nil {
@@ -396,38 +2501,55 @@ func Test_createIconSet(t *testing.T) {
 				if tc.wantErr != nil {
 					t.Fatalf(`expected error "%v", got nil`, tc.wantErr)
 				}
+				var builtArchs []string
+				for _, params := range mockGoBuildProvider.builtParams {
+					builtArchs = append(builtArchs, params.Arch)
+				}
+				if strings.Join(builtArchs, ",") != strings.Join(tc.wantBuiltArchs, ",") {
+					t.Fatalf(`expected built architectures %v, got %v`, tc.wantBuiltArchs, builtArchs)
+				}
+			}
+			if got != tc.want {
+				t.Fatalf(`expected app binary path "%s", got "%s"`, tc.want, got)
 			}
 		})
 	}
 }
 
-func Test_copyAppBinary(t *testing.T) {
+func Test_createUniversalBinary(t *testing.T) {
 	testCases := []struct {
 		name              string
-		mockFsOpsProvider func() *mockFsOpsProvider
+		mockMachoProvider func() *mockMachoProvider
+		want              string
 		wantErr           error
 	}{
 		{
 			name: "happy path",
-			mockFsOpsProvider: func() *mockFsOpsProvider {
-				return &mockFsOpsProvider{}
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{}
 			},
+			want: "tmpWorkDir/testAppName",
 		},
 		{
-			name: "error when copying app binary",
-			mockFsOpsProvider: func() *mockFsOpsProvider {
-				return &mockFsOpsProvider{
-					expectedCopyFileErr: os.ErrPermission,
+			name: "error when creating universal binary",
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{
+					expectedCreateUniversalErr: os.ErrPermission,
 				}
 			},
-			wantErr: errors.Wrap(os.ErrPermission, "error when copying file [testAppBinaryPath] to [testOutputDir/Contents/MacOS]"),
+			wantErr: os.ErrPermission,
 		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			fsOpsProvider = tc.mockFsOpsProvider()
+			machoProvider = tc.mockMachoProvider()
+
+			got, err := createUniversalBinary(
+				"testAppName",
+				[]string{"testAppBinaryPath-arm64", "testAppBinaryPath-amd64"},
+				"tmpWorkDir",
+			)
 
-			err := copyAppBinary("testAppBinaryPath", "testOutputDir")
 			if err != nil {
 				if tc.wantErr == nil {
 					t.Fatalf(`expected no error, got "%v"`, err)
@@ -440,54 +2562,67 @@ func Test_copyAppBinary(t *testing.T) {
 					t.Fatalf(`expected error "%v", got nil`, tc.wantErr)
 				}
 			}
+			if got != tc.want {
+				t.Fatalf(`expected universal binary path "%s", got "%s"`, tc.want, got)
+			}
 		})
 	}
 }
 
-func Test_createInfoPlistFile(t *testing.T) {
+func Test_describeAppBinary(t *testing.T) {
 	testCases := []struct {
-		name              string
-		mockFsOpsProvider func() *mockFsOpsProvider
-		wantErr           error
+		name string
+		info *macho.Info
+		want string
 	}{
 		{
-			name: "happy path",
-			mockFsOpsProvider: func() *mockFsOpsProvider {
-				return &mockFsOpsProvider{}
-			},
+			name: "thin binary",
+			info: &macho.Info{Architectures: []string{"arm64"}, MinimumOS: "11.0"},
+			want: "[architectures: arm64 (thin), minimum macOS: 11.0]",
 		},
 		{
-			name: "error when writing file",
-			mockFsOpsProvider: func() *mockFsOpsProvider {
-				return &mockFsOpsProvider{
-					expectedWriteFileErr: os.ErrPermission,
-				}
-			},
-			wantErr: errors.Wrap(os.ErrPermission, "error when writing Info.plist file to [testIconPath/testBundleIdentifier/Contents/Info.plist]"),
+			name: "fat binary without minimum OS",
+			info: &macho.Info{Architectures: []string{"x86_64", "arm64"}, Fat: true},
+			want: "[architectures: x86_64, arm64 (fat), minimum macOS: not declared]",
 		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			fsOpsProvider = tc.mockFsOpsProvider()
-
-			err := createInfoPlistFile(
-				"testAppName",
-				"testBundleIdentifier",
-				"testIconPath",
-				"testOutputDir",
-			)
+			got := describeAppBinary(tc.info)
+			if got != tc.want {
+				t.Fatalf(`expected description "%s", got "%s"`, tc.want, got)
+			}
+		})
+	}
+}
 
-			if err != nil {
-				if tc.wantErr == nil {
-					t.Fatalf(`expected no error, got "%v"`, err)
-				}
-				if tc.wantErr.Error() != err.Error() {
-					t.Fatalf(`expected error "%v", got "%v"`, tc.wantErr, err)
-				}
-			} else {
-				if tc.wantErr != nil {
-					t.Fatalf(`expected error "%v", got nil`, tc.wantErr)
-				}
+func Test_describeAppBundle(t *testing.T) {
+	testCases := []struct {
+		name string
+		info *bundle.Info
+		want string
+	}{
+		{
+			name: "with versions",
+			info: &bundle.Info{Executable: "myapp", Identifier: "com.example.myapp", ShortVersion: "1.2.3", Version: "42"},
+			want: "[identifier: com.example.myapp, executable: myapp, version: 1.2.3 (42)]",
+		},
+		{
+			name: "with same versions",
+			info: &bundle.Info{Executable: "myapp", Identifier: "com.example.myapp", ShortVersion: "1.2.3", Version: "1.2.3"},
+			want: "[identifier: com.example.myapp, executable: myapp, version: 1.2.3]",
+		},
+		{
+			name: "without versions",
+			info: &bundle.Info{Executable: "myapp", Identifier: "com.example.myapp"},
+			want: "[identifier: com.example.myapp, executable: myapp, version: not declared]",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := describeAppBundle(tc.info)
+			if got != tc.want {
+				t.Fatalf(`expected description "%s", got "%s"`, tc.want, got)
 			}
 		})
 	}
@@ -559,6 +2694,18 @@ func Test_createAppDmg(t *testing.T) {
 			},
 			wantErr: errors.New("error when setting up DMG template: error when creating symlink for Applications folder: permission denied"),
 		},
+		{
+			name: "error reading size of DMG contents",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					expectedDirSizeErr: os.ErrPermission,
+				}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			wantErr: errors.Wrap(os.ErrPermission, "error when reading the size of the DMG contents"),
+		},
 		{
 			name: "error unmounting DMG template",
 			mockFsOpsProvider: func() *mockFsOpsProvider {
@@ -591,6 +2738,8 @@ func Test_createAppDmg(t *testing.T) {
 
 			got, err := createAppDmg(
 				"testAppBundleDirPath",
+				&volumeAppearance{},
+				&imageOptions{format: "UDZO"},
 				"tmpWorkDir",
 				"outputDir",
 			)
@@ -607,8 +2756,12 @@ func Test_createAppDmg(t *testing.T) {
 					t.Fatalf(`expected error "%v", got nil`, tc.wantErr)
 				}
 			}
-			if got != tc.want {
-				t.Fatalf(`expected DMG file path "%s", got "%s"`, tc.want, got)
+			var gotPath string
+			if got != nil {
+				gotPath = got.Path
+			}
+			if gotPath != tc.want {
+				t.Fatalf(`expected DMG file path "%s", got "%s"`, tc.want, gotPath)
 			}
 		})
 	}
@@ -696,10 +2849,12 @@ func Test_createDMGTemplate(t *testing.T) {
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			hdiutilProvider = tc.mockHdiutilProvider()
+			mockHdiutilProvider := tc.mockHdiutilProvider()
+			hdiutilProvider = mockHdiutilProvider
 
 			got, err := createDMGTemplate(
 				"dmgTemplateVolName",
+				&imageOptions{filesystem: "HFS+", layout: "SPUD"},
 				"outputDir",
 			)
 
@@ -719,6 +2874,8 @@ func Test_createDMGTemplate(t *testing.T) {
 			if got != tc.want {
 				t.Fatalf(`expected app bundle directory path "%s", got "%s"`, tc.want, got)
 			}
+			require.Equal(t, "HFS+", mockHdiutilProvider.filesystem)
+			require.Equal(t, "SPUD", mockHdiutilProvider.layout)
 		})
 	}
 }
@@ -779,7 +2936,10 @@ func Test_mountDMGTemplate(t *testing.T) {
 func Test_setupDMGTemplate(t *testing.T) {
 	testCases := []struct {
 		name              string
+		layout            *WindowLayout
+		background        *backgroundImage
 		mockFsOpsProvider func() *mockFsOpsProvider
+		wantDSStore       bool
 		wantErr           error
 	}{
 		{
@@ -787,6 +2947,32 @@ func Test_setupDMGTemplate(t *testing.T) {
 			mockFsOpsProvider: func() *mockFsOpsProvider {
 				return &mockFsOpsProvider{}
 			},
+			wantDSStore: true,
+		},
+		{
+			name:       "happy path with background image",
+			background: &backgroundImage{dirPath: "tmp/.background", fileName: "background.png", width: 660, height: 400},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			wantDSStore: true,
+		},
+		{
+			name:   "unknown item in window layout",
+			layout: &WindowLayout{IconPositions: map[string]dsstore.Point{"Other.app": {X: 100, Y: 100}}},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			wantErr: errors.New("error when laying out DMG window: no item named [Other.app] in the DMG; the items are [testAppBundleDirPath Applications]"),
+		},
+		{
+			name: "error writing .DS_Store file",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					expectedWriteFileErr: os.ErrPermission,
+				}
+			},
+			wantErr: errors.New("error when laying out DMG window: error when writing .DS_Store file to [/Volumes/dmgTemplateVolName/.DS_Store]: permission denied"),
 		},
 		{
 			name: "error creating symlink for Applications folder",
@@ -809,11 +2995,13 @@ func Test_setupDMGTemplate(t *testing.T) {
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			fsOpsProvider = tc.mockFsOpsProvider()
+			mockFsOpsProvider := tc.mockFsOpsProvider()
+			fsOpsProvider = mockFsOpsProvider
 
 			err := setupDMGTemplate(
 				"/Volumes/dmgTemplateVolName",
 				"testAppBundleDirPath",
+				&volumeAppearance{layout: tc.layout, background: tc.background},
 			)
 
 			if err != nil {
@@ -828,6 +3016,11 @@ func Test_setupDMGTemplate(t *testing.T) {
 					t.Fatalf(`expected error "%v", got nil`, tc.wantErr)
 				}
 			}
+			_, gotDSStore := mockFsOpsProvider.writtenFiles["/Volumes/dmgTemplateVolName/.DS_Store"]
+			require.Equal(t, tc.wantDSStore, gotDSStore)
+			if tc.background != nil {
+				require.Contains(t, mockFsOpsProvider.copiedPaths, "tmp/.background/ -> /Volumes/dmgTemplateVolName")
+			}
 		})
 	}
 }
@@ -878,19 +3071,68 @@ func Test_unmountDMGTemplate(t *testing.T) {
 func Test_convertDmg(t *testing.T) {
 	testCases := []struct {
 		name                string
+		options             *imageOptions
+		mockFsOpsProvider   func() *mockFsOpsProvider
 		mockHdiutilProvider func() *mockHdiutilProvider
-		want                string
+		want                *Result
+		wantConvertOptions  *hdiutil.ConvertOptions
 		wantErr             error
 	}{
 		{
-			name: "happy path",
+			name:    "happy path",
+			options: &imageOptions{format: "UDZO", zlibLevel: 9},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{fileSizes: map[string]int64{
+					"outputDir/testAppBundleDirPath.dmg": 20971520,
+				}}
+			},
 			mockHdiutilProvider: func() *mockHdiutilProvider {
 				return &mockHdiutilProvider{}
 			},
-			want: "outputDir/testAppBundleDirPath.dmg",
+			want: &Result{
+				Path:         "outputDir/testAppBundleDirPath.dmg",
+				Format:       "UDZO",
+				Size:         20971520,
+				ContentsSize: 41943040,
+			},
+			wantConvertOptions: &hdiutil.ConvertOptions{Format: "UDZO", ZlibLevel: 9},
 		},
 		{
-			name: "error converting DMG",
+			name:    "encrypted",
+			options: &imageOptions{format: "ULFO", encryption: "AES-256", passphrase: []byte("secret")},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{fileSizes: map[string]int64{
+					"outputDir/testAppBundleDirPath.dmg": 26214400,
+				}}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			want: &Result{
+				Path:         "outputDir/testAppBundleDirPath.dmg",
+				Format:       "ULFO",
+				Size:         26214400,
+				ContentsSize: 41943040,
+			},
+			wantConvertOptions: &hdiutil.ConvertOptions{Format: "ULFO", Encryption: "AES-256", Passphrase: []byte("secret")},
+		},
+		{
+			name:    "error reading DMG size",
+			options: &imageOptions{format: "UDZO"},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{expectedFileSizeErr: os.ErrPermission}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			wantErr: errors.Wrap(os.ErrPermission, "error when reading the size of the final DMG"),
+		},
+		{
+			name:    "error converting DMG",
+			options: &imageOptions{format: "UDZO"},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
 			mockHdiutilProvider: func() *mockHdiutilProvider {
 				return &mockHdiutilProvider{
 					expectedConvertDMGErr: os.ErrPermission,
@@ -901,30 +3143,26 @@ func Test_convertDmg(t *testing.T) {
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			hdiutilProvider = tc.mockHdiutilProvider()
+			fsOpsProvider = tc.mockFsOpsProvider()
+			mockHdiutilProvider := tc.mockHdiutilProvider()
+			hdiutilProvider = mockHdiutilProvider
 
 			got, err := convertDmg(
-				"outputDir/testAppBundleDirPath.app",
+				"testAppBundleDirPath",
 				"outputDir/tmp/dmgTemplateVolName-template.dmg",
+				41943040,
+				tc.options,
 				"outputDir",
 			)
-
-			if err != nil {
-				if tc.wantErr == nil {
-					t.Fatalf(`expected no error, got "%v"`, err)
-				}
-				if tc.wantErr.Error() != err.Error() {
-					t.Fatalf(`expected error "%v", got "%v"`, tc.wantErr, err)
-				}
-			} else {
-				if tc.wantErr != nil {
-					t.Fatalf(`expected error "%v", got nil`, tc.wantErr)
-				}
-			}
-
-			if got != tc.want {
-				t.Fatalf(`expected converted DMG path "%s", got "%s"`, tc.want, got)
+			if tc.wantErr != nil {
+				require.EqualError(t, err, tc.wantErr.Error())
+				return
 			}
+			require.NoError(t, err)
+			require.GreaterOrEqual(t, got.ConversionDuration, time.Duration(0))
+			got.ConversionDuration = 0
+			require.Equal(t, tc.want, got)
+			require.Equal(t, tc.wantConvertOptions, mockHdiutilProvider.convertOptions)
 		})
 	}
 }
@@ -942,12 +3180,40 @@ type mockFsOpsProvider struct {
 	expectedWriteFileErr           error
 	expectedCreateSymlinkErr       error
 	expectedDeleteDirErr           error
+	expectedGlobMatches            map[string][]string
+	expectedGlobErr                error
+	existingFiles                  map[string]bool
+	existingDirs                   map[string]bool
+	realPaths                      map[string]string
+	writtenFiles                   map[string][]byte
+	copiedPaths                    []string
+	fileIDs                        map[string]uint64
+	expectedFileIDErr              error
+	fileContents                   map[string][]byte
+	expectedReadFileErr            error
+	fileSizes                      map[string]int64
+	expectedFileSizeErr            error
+	expectedDirSizeErr             error
 }
 
 func (m *mockFsOpsProvider) DirExists(path string) (bool, error) {
+	if m.existingDirs[path] {
+		return true, m.expectedDirExistsErr
+	}
 	return m.expectedDirExists, m.expectedDirExistsErr
 }
 
+func (m *mockFsOpsProvider) Glob(pattern string) ([]string, error) {
+	return m.expectedGlobMatches[pattern], m.expectedGlobErr
+}
+
+func (m *mockFsOpsProvider) RealPath(path string) (string, error) {
+	if realPath, ok := m.realPaths[path]; ok {
+		return realPath, nil
+	}
+	return path, nil
+}
+
 func (m *mockFsOpsProvider) VolumeDoesNotExist(path string) (bool, error) {
 	return m.expectedVolumeDoesNotExists, m.expectedVolumeDoesNotExistsErr
 }
@@ -957,29 +3223,122 @@ func (m *mockFsOpsProvider) MkdirAll(path string, perm os.FileMode) error {
 }
 
 func (m *mockFsOpsProvider) CopyFile(src, dst string) error {
-	return m.expectedCopyFileErr
+	if m.expectedCopyFileErr != nil {
+		return m.expectedCopyFileErr
+	}
+	m.copiedPaths = append(m.copiedPaths, src+" -> "+dst)
+	return nil
 }
 
 func (m *mockFsOpsProvider) CopyDir(src, dst string) error {
-	return m.expectedCopyDirErr
+	if m.expectedCopyDirErr != nil {
+		return m.expectedCopyDirErr
+	}
+	m.copiedPaths = append(m.copiedPaths, src+"/ -> "+dst)
+	return nil
 }
 
 func (m *mockFsOpsProvider) DeleteDir(path string) error {
 	return m.expectedDeleteDirErr
 }
 
+func (m *mockFsOpsProvider) FileSize(path string) (int64, error) {
+	return m.fileSizes[path], m.expectedFileSizeErr
+}
+
+func (m *mockFsOpsProvider) DirSize(path string) (int64, error) {
+	return m.fileSizes[path], m.expectedDirSizeErr
+}
+
+func (m *mockFsOpsProvider) ReadFile(name string) ([]byte, error) {
+	return m.fileContents[name], m.expectedReadFileErr
+}
+
 func (m *mockFsOpsProvider) WriteFile(name string, data []byte, perm os.FileMode) error {
-	return m.expectedWriteFileErr
+	if m.expectedWriteFileErr != nil {
+		return m.expectedWriteFileErr
+	}
+	if m.writtenFiles == nil {
+		m.writtenFiles = make(map[string][]byte)
+	}
+	m.writtenFiles[name] = data
+	return nil
 }
 
 func (m *mockFsOpsProvider) CreateSymlink(src, dst string) error {
 	return m.expectedCreateSymlinkErr
 }
 
+func (m *mockFsOpsProvider) FileID(path string) (uint64, error) {
+	return m.fileIDs[path], m.expectedFileIDErr
+}
+
 func (m *mockFsOpsProvider) FileExists(path string) (bool, error) {
+	if m.existingFiles[path] {
+		return true, m.expectedFileExistsErr
+	}
 	return m.expectedFileExists, m.expectedFileExistsErr
 }
 
+type mockImageProvider struct {
+	expectedWidth          int
+	expectedHeight         int
+	expectedSizeErr        error
+	expectedCombineTIFFErr error
+	combinedImages         []string
+}
+
+func (m *mockImageProvider) Size(path string) (int, int, error) {
+	return m.expectedWidth, m.expectedHeight, m.expectedSizeErr
+}
+
+func (m *mockImageProvider) CombineTIFF(outputPath, imagePath, image2xPath string) error {
+	if m.expectedCombineTIFFErr != nil {
+		return m.expectedCombineTIFFErr
+	}
+	m.combinedImages = append(m.combinedImages, imagePath+" + "+image2xPath+" -> "+outputPath)
+	return nil
+}
+
+type mockXattrProvider struct {
+	expectedSetCustomIconErr error
+	customIconPaths          []string
+}
+
+func (m *mockXattrProvider) SetCustomIcon(path string) error {
+	if m.expectedSetCustomIconErr != nil {
+		return m.expectedSetCustomIconErr
+	}
+	m.customIconPaths = append(m.customIconPaths, path)
+	return nil
+}
+
+type mockUdifProvider struct {
+	expectedSetResourcesErr error
+	resources               map[string]udif.Resources
+}
+
+func (m *mockUdifProvider) SetResources(dmgPath string, resources udif.Resources) error {
+	if m.expectedSetResourcesErr != nil {
+		return m.expectedSetResourcesErr
+	}
+	if m.resources == nil {
+		m.resources = map[string]udif.Resources{}
+	}
+	m.resources[dmgPath] = resources
+	return nil
+}
+
+type mockOsascriptProvider struct {
+	expectedApplyLayoutErr error
+	window                 *osascript.Window
+}
+
+func (m *mockOsascriptProvider) ApplyLayout(w *osascript.Window) error {
+	m.window = w
+	return m.expectedApplyLayoutErr
+}
+
 type mockSipsUtilityProvider struct {
 	expectedGenerateIconsErr error
 }
@@ -997,20 +3356,32 @@ func (m *mockIconUtilProvider) GenerateIconSet(iconSetDirPath, resourcesDirPath
 }
 
 type mockHdiutilProvider struct {
-	expectedCreateDMGErr  error
-	expectedConvertDMGErr error
-	expectedMountDMGErr   error
-	expectedUnmountDMGErr error
+	expectedCreateDMGErr           error
+	expectedConvertDMGErr          error
+	expectedMountDMGErr            error
+	expectedUnmountDMGErr          error
+	expectedCreateDMGFromFolderErr error
+	filesystem                     string
+	layout                         string
+	convertOptions                 *hdiutil.ConvertOptions
+	srcFolder                      string
 }
 
 func (m *mockHdiutilProvider) CreateDMG(size, fs, volName, layout, output string) error {
+	m.filesystem, m.layout = fs, layout
 	return m.expectedCreateDMGErr
 }
 
-func (m *mockHdiutilProvider) ConvertDMG(dmgPath, dmgOutputFileName string) error {
+func (m *mockHdiutilProvider) ConvertDMG(dmgPath, dmgOutputFileName string, opts *hdiutil.ConvertOptions) error {
+	m.convertOptions = opts
 	return m.expectedConvertDMGErr
 }
 
+func (m *mockHdiutilProvider) CreateDMGFromFolder(srcFolder, fs, volName, layout, output string, opts *hdiutil.ConvertOptions) error {
+	m.srcFolder, m.filesystem, m.layout, m.convertOptions = srcFolder, fs, layout, opts
+	return m.expectedCreateDMGFromFolderErr
+}
+
 func (m *mockHdiutilProvider) MoundDMG(dmgVolName, dmgPath string) error {
 	return m.expectedMountDMGErr
 }
@@ -1018,3 +3389,139 @@ func (m *mockHdiutilProvider) MoundDMG(dmgVolName, dmgPath string) error {
 func (m *mockHdiutilProvider) UnmountDMG(volName string) error {
 	return m.expectedUnmountDMGErr
 }
+
+type mockMachoProvider struct {
+	expectedInfo               *macho.Info
+	expectedInspectErr         error
+	expectedCreateUniversalErr error
+	dylibs                     map[string]*macho.Dylibs
+	expectedReadDylibsErr      error
+	expectedRewriteDylibsErr   error
+	rewrittenDylibs            map[string]macho.DylibChanges
+}
+
+func (m *mockMachoProvider) Inspect(path string) (*macho.Info, error) {
+	if m.expectedInspectErr != nil {
+		return nil, m.expectedInspectErr
+	}
+	if m.expectedInfo != nil {
+		return m.expectedInfo, nil
+	}
+	return &macho.Info{Architectures: []string{"arm64"}, MinimumOS: "11.0"}, nil
+}
+
+func (m *mockMachoProvider) CreateUniversal(outputPath string, binaryPaths ...string) error {
+	return m.expectedCreateUniversalErr
+}
+
+func (m *mockMachoProvider) ReadDylibs(path string) (*macho.Dylibs, error) {
+	if m.expectedReadDylibsErr != nil {
+		return nil, m.expectedReadDylibsErr
+	}
+	if dylibs, ok := m.dylibs[path]; ok {
+		return dylibs, nil
+	}
+	return &macho.Dylibs{}, nil
+}
+
+func (m *mockMachoProvider) RewriteDylibs(path string, changes macho.DylibChanges) error {
+	if m.expectedRewriteDylibsErr != nil {
+		return m.expectedRewriteDylibsErr
+	}
+	if m.rewrittenDylibs == nil {
+		m.rewrittenDylibs = make(map[string]macho.DylibChanges)
+	}
+	m.rewrittenDylibs[path] = changes
+	return nil
+}
+
+type mockCodesignProvider struct {
+	expectedSignAdHocErr error
+	signedPaths          []string
+}
+
+func (m *mockCodesignProvider) SignAdHoc(path string) error {
+	if m.expectedSignAdHocErr != nil {
+		return m.expectedSignAdHocErr
+	}
+	m.signedPaths = append(m.signedPaths, path)
+	return nil
+}
+
+type mockGoBuildProvider struct {
+	expectedBuildErr error
+	builtParams      []*gobuild.Params
+}
+
+func (m *mockGoBuildProvider) Build(params *gobuild.Params) error {
+	if m.expectedBuildErr != nil {
+		return m.expectedBuildErr
+	}
+	m.builtParams = append(m.builtParams, params)
+	return nil
+}
+
+type mockBuildInfoProvider struct {
+	expectedInfo    *buildinfo.Info
+	expectedReadErr error
+}
+
+func (m *mockBuildInfoProvider) Read(path string) (*buildinfo.Info, error) {
+	return m.expectedInfo, m.expectedReadErr
+}
+
+type mockFyneAppProvider struct {
+	expectedMetadata *fyneapp.Metadata
+	expectedLoadErr  error
+	loadedPath       string
+}
+
+func (m *mockFyneAppProvider) Load(path string) (*fyneapp.Metadata, error) {
+	m.loadedPath = path
+	return m.expectedMetadata, m.expectedLoadErr
+}
+
+type mockBundleProvider struct {
+	expectedInfo              *bundle.Info
+	expectedValidateErr       error
+	expectedNormalizeModesErr error
+	expectedCheckLayoutErr    error
+	validatedPath             string
+	checkedPath               string
+}
+
+func (m *mockBundleProvider) Validate(path string) (*bundle.Info, error) {
+	m.validatedPath = path
+	return m.expectedInfo, m.expectedValidateErr
+}
+
+func (m *mockBundleProvider) NormalizeModes(path string) error {
+	return m.expectedNormalizeModesErr
+}
+
+func (m *mockBundleProvider) CheckLayout(path string) error {
+	m.checkedPath = path
+	return m.expectedCheckLayoutErr
+}
+
+type mockVerifyProvider struct {
+	expectedErr  error
+	verifiedPath string
+	expected     *verify.Expected
+}
+
+func (m *mockVerifyProvider) DMG(path string, expected *verify.Expected) error {
+	m.verifiedPath, m.expected = path, expected
+	return m.expectedErr
+}
+
+type mockScriptProvider struct {
+	expectedInfo       *script.Info
+	expectedInspectErr error
+	inspectedPath      string
+}
+
+func (m *mockScriptProvider) Inspect(path string) (*script.Info, error) {
+	m.inspectedPath = path
+	return m.expectedInfo, m.expectedInspectErr
+}
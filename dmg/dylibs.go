@@ -0,0 +1,243 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tiagomelo/macos-dmg-creator/macho"
+)
+
+const (
+	// frameworksRPath is the run path search path added to the application binary,
+	// so that the @rpath install names of the bundled libraries resolve.
+	frameworksRPath = "@executable_path/../Frameworks"
+
+	// bundledDylibPrefix is the prefix of the install names the
+	// application binary and the bundled libraries load them with.
+	bundledDylibPrefix = "@executable_path/../Frameworks/"
+)
+
+// systemLibraryPrefixes are the locations of the libraries
+// that ship with macOS, which are never bundled.
+var systemLibraryPrefixes = []string{"/usr/lib/", "/System/Library/"}
+
+// dylib is a non-system dynamic library the application binary depends on.
+type dylib struct {
+	// path is the path the library was found at.
+	path string
+
+	// name is the name of the library in the Frameworks directory.
+	name string
+
+	// deps maps the install names the library loads its dependencies with to them.
+	deps map[string]*dylib
+}
+
+// dylibDependencies holds the non-system dynamic libraries the application binary depends on.
+type dylibDependencies struct {
+	// binaryDeps maps the install names the application binary loads its dependencies with to them.
+	binaryDeps map[string]*dylib
+
+	// libraries are all the libraries, direct and indirect, in the order they were found.
+	libraries []*dylib
+
+	// problems describe the dependencies that could not be bundled.
+	problems []string
+}
+
+// bundleDylibs copies the non-system dynamic libraries the application binary depends on
+// into the Frameworks directory of the application bundle, rewrites the install names so
+// they are loaded from there and signs the changed binaries again.
+func bundleDylibs(appBinaryPath, executableName, appBundleDirPath string) (*dylibDependencies, error) {
+	deps, err := discoverDylibs(appBinaryPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(deps.libraries) == 0 {
+		return deps, nil
+	}
+
+	frameworksDirPath := filepath.Join(appBundleDirPath, frameworksDir)
+	if err := fsOpsProvider.MkdirAll(frameworksDirPath, bundleDirMode); err != nil {
+		return nil, errors.Wrapf(err, "error when creating directory [%s]", frameworksDirPath)
+	}
+	for _, lib := range deps.libraries {
+		if err := fsOpsProvider.CopyFile(lib.path, filepath.Join(frameworksDirPath, lib.name)); err != nil {
+			return nil, errors.Wrapf(err, "error when copying dynamic library [%s]", lib.path)
+		}
+	}
+
+	bundledAppBinaryPath := filepath.Join(appBundleDirPath, macOsDir, executableName)
+	if err := rewriteDylibs(bundledAppBinaryPath, macho.DylibChanges{
+		Dependencies: bundledInstallNames(deps.binaryDeps),
+		AddRPaths:    []string{frameworksRPath},
+	}); err != nil {
+		return nil, err
+	}
+	for _, lib := range deps.libraries {
+		if err := rewriteDylibs(filepath.Join(frameworksDirPath, lib.name), macho.DylibChanges{
+			InstallName:  "@rpath/" + lib.name,
+			Dependencies: bundledInstallNames(lib.deps),
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return deps, nil
+}
+
+// rewriteDylibs rewrites the load commands of the binary at the
+// given path and signs it again, since its signature is invalidated.
+func rewriteDylibs(path string, changes macho.DylibChanges) error {
+	if err := machoProvider.RewriteDylibs(path, changes); err != nil {
+		return err
+	}
+	return codesignProvider.SignAdHoc(path)
+}
+
+// bundledInstallNames maps the given install names to the ones
+// that load the libraries from the Frameworks directory.
+func bundledInstallNames(deps map[string]*dylib) map[string]string {
+	installNames := make(map[string]string, len(deps))
+	for installName, lib := range deps {
+		installNames[installName] = bundledDylibPrefix + lib.name
+	}
+	return installNames
+}
+
+// discoverDylibs finds the non-system dynamic libraries the application binary
+// depends on, directly or through other libraries.
+func discoverDylibs(appBinaryPath string) (*dylibDependencies, error) {
+	binaryDylibs, err := machoProvider.ReadDylibs(appBinaryPath)
+	if err != nil {
+		return nil, err
+	}
+	resolver := &dylibResolver{
+		deps:             &dylibDependencies{binaryDeps: map[string]*dylib{}},
+		executablePath:   appBinaryPath,
+		executableRPaths: binaryDylibs.RPaths,
+		byRealPath:       map[string]*dylib{},
+	}
+	if err := resolver.resolveAll(appBinaryPath, binaryDylibs, resolver.deps.binaryDeps); err != nil {
+		return nil, err
+	}
+	// the libraries found while resolving are appended, so this
+	// goes through the whole dependency graph breadth first.
+	for i := 0; i < len(resolver.deps.libraries); i++ {
+		lib := resolver.deps.libraries[i]
+		libDylibs, err := machoProvider.ReadDylibs(lib.path)
+		if err != nil {
+			return nil, err
+		}
+		if err := resolver.resolveAll(lib.path, libDylibs, lib.deps); err != nil {
+			return nil, err
+		}
+	}
+
+	names := map[string]string{}
+	for _, lib := range resolver.deps.libraries {
+		if other, ok := names[lib.name]; ok {
+			return nil, errors.Errorf("dynamic libraries [%s] and [%s] have the same name", other, lib.path)
+		}
+		names[lib.name] = lib.path
+	}
+	return resolver.deps, nil
+}
+
+// dylibResolver resolves install names the way dyld does.
+type dylibResolver struct {
+	deps             *dylibDependencies
+	executablePath   string
+	executableRPaths []string
+
+	// byRealPath holds the libraries found so far by their path with symbolic links
+	// resolved, since Homebrew links the same library from several locations.
+	byRealPath map[string]*dylib
+}
+
+// resolveAll resolves the non-system dependencies of a binary, adding
+// the ones that were not found before to the dependencies.
+func (r *dylibResolver) resolveAll(loaderPath string, loaderDylibs *macho.Dylibs, into map[string]*dylib) error {
+	for _, installName := range loaderDylibs.Dependencies {
+		if isSystemLibrary(installName) {
+			continue
+		}
+		if strings.Contains(installName, ".framework/") {
+			r.deps.problems = append(r.deps.problems, fmt.Sprintf("%s needed by [%s] is part of a framework, which is not bundled automatically; declare the framework in Frameworks", installName, loaderPath))
+			continue
+		}
+		path, err := r.resolve(installName, loaderPath, loaderDylibs.RPaths)
+		if err != nil {
+			return err
+		}
+		if path == "" {
+			r.deps.problems = append(r.deps.problems, fmt.Sprintf("could not resolve %s needed by [%s]", installName, loaderPath))
+			continue
+		}
+		realPath, err := fsOpsProvider.RealPath(path)
+		if err != nil {
+			return err
+		}
+		lib, ok := r.byRealPath[realPath]
+		if !ok {
+			lib = &dylib{path: path, name: filepath.Base(path), deps: map[string]*dylib{}}
+			r.byRealPath[realPath] = lib
+			r.deps.libraries = append(r.deps.libraries, lib)
+		}
+		into[installName] = lib
+	}
+	return nil
+}
+
+// resolve returns the path of the library with the given install name loaded
+// by the given binary, or an empty string when the library is not found.
+func (r *dylibResolver) resolve(installName, loaderPath string, loaderRPaths []string) (string, error) {
+	candidates := []string{r.expand(installName, loaderPath)}
+	if name, ok := strings.CutPrefix(installName, "@rpath/"); ok {
+		// the run paths of the loader come before the ones of the executable.
+		candidates = nil
+		for _, rpath := range slices.Concat(loaderRPaths, r.executableRPaths) {
+			candidates = append(candidates, filepath.Join(r.expand(rpath, loaderPath), name))
+		}
+	}
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, "@") {
+			continue
+		}
+		exists, err := fsOpsProvider.FileExists(candidate)
+		if err != nil {
+			return "", err
+		}
+		if exists {
+			return candidate, nil
+		}
+	}
+	return "", nil
+}
+
+// expand replaces the @executable_path and @loader_path prefixes of the path.
+func (r *dylibResolver) expand(path, loaderPath string) string {
+	if rest, ok := strings.CutPrefix(path, "@executable_path/"); ok {
+		return filepath.Join(filepath.Dir(r.executablePath), rest)
+	}
+	if rest, ok := strings.CutPrefix(path, "@loader_path/"); ok {
+		return filepath.Join(filepath.Dir(loaderPath), rest)
+	}
+	return path
+}
+
+// isSystemLibrary reports whether the install name is of a library that ships with macOS.
+func isSystemLibrary(installName string) bool {
+	for _, prefix := range systemLibraryPrefixes {
+		if strings.HasPrefix(installName, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,268 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import (
+	"os"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/macos-dmg-creator/macho"
+)
+
+func Test_bundleDylibs(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		mockFsOpsProvider    func() *mockFsOpsProvider
+		mockMachoProvider    func() *mockMachoProvider
+		mockCodesignProvider func() *mockCodesignProvider
+		wantCopiedPaths      []string
+		wantRewrittenDylibs  map[string]macho.DylibChanges
+		wantSignedPaths      []string
+		wantProblems         []string
+		wantErr              error
+	}{
+		{
+			name: "happy path",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					existingFiles: map[string]bool{
+						"/opt/homebrew/lib/libfoo.1.dylib":            true,
+						"/opt/homebrew/lib/libbar.dylib":              true,
+						"/opt/homebrew/opt/baz/lib/libbaz.dylib":      true,
+						"/opt/homebrew/opt/foo/lib/libfoo.1.dylib":    true,
+						"/opt/homebrew/opt/baz/lib/plugins/libqux.so": true,
+					},
+					realPaths: map[string]string{
+						"/opt/homebrew/lib/libfoo.1.dylib":         "/opt/homebrew/Cellar/foo/1.0/lib/libfoo.1.dylib",
+						"/opt/homebrew/opt/foo/lib/libfoo.1.dylib": "/opt/homebrew/Cellar/foo/1.0/lib/libfoo.1.dylib",
+					},
+				}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{
+					dylibs: map[string]*macho.Dylibs{
+						"bin/myapp-darwin-arm64": {
+							Dependencies: []string{
+								"/usr/lib/libSystem.B.dylib",
+								"/System/Library/Frameworks/Cocoa.framework/Versions/A/Cocoa",
+								"/opt/homebrew/lib/libfoo.1.dylib",
+								"@rpath/libbar.dylib",
+								"/usr/local/lib/libmissing.dylib",
+							},
+							RPaths: []string{"@executable_path/../lib", "/opt/homebrew/lib"},
+						},
+						"/opt/homebrew/lib/libfoo.1.dylib": {
+							InstallName:  "/opt/homebrew/opt/foo/lib/libfoo.1.dylib",
+							Dependencies: []string{"/opt/homebrew/opt/baz/lib/libbaz.dylib"},
+						},
+						"/opt/homebrew/lib/libbar.dylib": {
+							InstallName:  "@rpath/libbar.dylib",
+							Dependencies: []string{"/opt/homebrew/opt/foo/lib/libfoo.1.dylib", "/opt/homebrew/opt/qt/lib/QtCore.framework/Versions/A/QtCore"},
+						},
+						"/opt/homebrew/opt/baz/lib/libbaz.dylib": {
+							InstallName:  "/opt/homebrew/opt/baz/lib/libbaz.dylib",
+							Dependencies: []string{"@loader_path/plugins/libqux.so"},
+						},
+					},
+				}
+			},
+			mockCodesignProvider: func() *mockCodesignProvider {
+				return &mockCodesignProvider{}
+			},
+			wantCopiedPaths: []string{
+				"/opt/homebrew/lib/libfoo.1.dylib -> MyApp.app/Contents/Frameworks/libfoo.1.dylib",
+				"/opt/homebrew/lib/libbar.dylib -> MyApp.app/Contents/Frameworks/libbar.dylib",
+				"/opt/homebrew/opt/baz/lib/libbaz.dylib -> MyApp.app/Contents/Frameworks/libbaz.dylib",
+				"/opt/homebrew/opt/baz/lib/plugins/libqux.so -> MyApp.app/Contents/Frameworks/libqux.so",
+			},
+			wantRewrittenDylibs: map[string]macho.DylibChanges{
+				"MyApp.app/Contents/MacOS/myapp": {
+					Dependencies: map[string]string{
+						"/opt/homebrew/lib/libfoo.1.dylib": "@executable_path/../Frameworks/libfoo.1.dylib",
+						"@rpath/libbar.dylib":              "@executable_path/../Frameworks/libbar.dylib",
+					},
+					AddRPaths: []string{"@executable_path/../Frameworks"},
+				},
+				"MyApp.app/Contents/Frameworks/libfoo.1.dylib": {
+					InstallName: "@rpath/libfoo.1.dylib",
+					Dependencies: map[string]string{
+						"/opt/homebrew/opt/baz/lib/libbaz.dylib": "@executable_path/../Frameworks/libbaz.dylib",
+					},
+				},
+				"MyApp.app/Contents/Frameworks/libbar.dylib": {
+					InstallName: "@rpath/libbar.dylib",
+					Dependencies: map[string]string{
+						"/opt/homebrew/opt/foo/lib/libfoo.1.dylib": "@executable_path/../Frameworks/libfoo.1.dylib",
+					},
+				},
+				"MyApp.app/Contents/Frameworks/libbaz.dylib": {
+					InstallName: "@rpath/libbaz.dylib",
+					Dependencies: map[string]string{
+						"@loader_path/plugins/libqux.so": "@executable_path/../Frameworks/libqux.so",
+					},
+				},
+				"MyApp.app/Contents/Frameworks/libqux.so": {
+					InstallName:  "@rpath/libqux.so",
+					Dependencies: map[string]string{},
+				},
+			},
+			wantSignedPaths: []string{
+				"MyApp.app/Contents/MacOS/myapp",
+				"MyApp.app/Contents/Frameworks/libfoo.1.dylib",
+				"MyApp.app/Contents/Frameworks/libbar.dylib",
+				"MyApp.app/Contents/Frameworks/libbaz.dylib",
+				"MyApp.app/Contents/Frameworks/libqux.so",
+			},
+			wantProblems: []string{
+				"could not resolve /usr/local/lib/libmissing.dylib needed by [bin/myapp-darwin-arm64]",
+				"/opt/homebrew/opt/qt/lib/QtCore.framework/Versions/A/QtCore needed by [/opt/homebrew/lib/libbar.dylib] is part of a framework, which is not bundled automatically; declare the framework in Frameworks",
+			},
+		},
+		{
+			name: "only system libraries",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{
+					dylibs: map[string]*macho.Dylibs{
+						"bin/myapp-darwin-arm64": {Dependencies: []string{"/usr/lib/libSystem.B.dylib"}},
+					},
+				}
+			},
+			mockCodesignProvider: func() *mockCodesignProvider {
+				return &mockCodesignProvider{}
+			},
+		},
+		{
+			name: "libraries with the same name",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					existingFiles: map[string]bool{
+						"/opt/homebrew/lib/libfoo.dylib": true,
+						"/usr/local/lib/libfoo.dylib":    true,
+					},
+				}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{
+					dylibs: map[string]*macho.Dylibs{
+						"bin/myapp-darwin-arm64": {Dependencies: []string{"/opt/homebrew/lib/libfoo.dylib", "/usr/local/lib/libfoo.dylib"}},
+					},
+				}
+			},
+			mockCodesignProvider: func() *mockCodesignProvider {
+				return &mockCodesignProvider{}
+			},
+			wantErr: errors.New("dynamic libraries [/opt/homebrew/lib/libfoo.dylib] and [/usr/local/lib/libfoo.dylib] have the same name"),
+		},
+		{
+			name: "error when reading dynamic libraries",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{
+					expectedReadDylibsErr: errors.New("error when parsing [bin/myapp-darwin-arm64]: not a Mach-O binary"),
+				}
+			},
+			mockCodesignProvider: func() *mockCodesignProvider {
+				return &mockCodesignProvider{}
+			},
+			wantErr: errors.New("error when parsing [bin/myapp-darwin-arm64]: not a Mach-O binary"),
+		},
+		{
+			name: "error when copying dynamic library",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					existingFiles:       map[string]bool{"/opt/homebrew/lib/libfoo.dylib": true},
+					expectedCopyFileErr: os.ErrPermission,
+				}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{
+					dylibs: map[string]*macho.Dylibs{
+						"bin/myapp-darwin-arm64": {Dependencies: []string{"/opt/homebrew/lib/libfoo.dylib"}},
+					},
+				}
+			},
+			mockCodesignProvider: func() *mockCodesignProvider {
+				return &mockCodesignProvider{}
+			},
+			wantErr: errors.New("error when copying dynamic library [/opt/homebrew/lib/libfoo.dylib]: permission denied"),
+		},
+		{
+			name: "error when rewriting load commands",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					existingFiles: map[string]bool{"/opt/homebrew/lib/libfoo.dylib": true},
+				}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{
+					dylibs: map[string]*macho.Dylibs{
+						"bin/myapp-darwin-arm64": {Dependencies: []string{"/opt/homebrew/lib/libfoo.dylib"}},
+					},
+					expectedRewriteDylibsErr: errors.New("not enough space"),
+				}
+			},
+			mockCodesignProvider: func() *mockCodesignProvider {
+				return &mockCodesignProvider{}
+			},
+			wantErr: errors.New("not enough space"),
+		},
+		{
+			name: "error when signing",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					existingFiles: map[string]bool{"/opt/homebrew/lib/libfoo.dylib": true},
+				}
+			},
+			mockMachoProvider: func() *mockMachoProvider {
+				return &mockMachoProvider{
+					dylibs: map[string]*macho.Dylibs{
+						"bin/myapp-darwin-arm64": {Dependencies: []string{"/opt/homebrew/lib/libfoo.dylib"}},
+					},
+				}
+			},
+			mockCodesignProvider: func() *mockCodesignProvider {
+				return &mockCodesignProvider{
+					expectedSignAdHocErr: errors.New("error when signing [MyApp.app/Contents/MacOS/myapp]"),
+				}
+			},
+			wantErr: errors.New("error when signing [MyApp.app/Contents/MacOS/myapp]"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockFsOpsProvider := tc.mockFsOpsProvider()
+			fsOpsProvider = mockFsOpsProvider
+			mockMachoProvider := tc.mockMachoProvider()
+			machoProvider = mockMachoProvider
+			mockCodesignProvider := tc.mockCodesignProvider()
+			codesignProvider = mockCodesignProvider
+
+			got, err := bundleDylibs("bin/myapp-darwin-arm64", "myapp", "MyApp.app")
+			if err != nil {
+				if tc.wantErr == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				if tc.wantErr.Error() != err.Error() {
+					t.Fatalf(`expected error "%v", got "%v"`, tc.wantErr, err)
+				}
+				return
+			}
+			if tc.wantErr != nil {
+				t.Fatalf(`expected error "%v", got nil`, tc.wantErr)
+			}
+			require.Equal(t, tc.wantCopiedPaths, mockFsOpsProvider.copiedPaths)
+			require.Equal(t, tc.wantRewrittenDylibs, mockMachoProvider.rewrittenDylibs)
+			require.Equal(t, tc.wantSignedPaths, mockCodesignProvider.signedPaths)
+			require.Equal(t, tc.wantProblems, got.problems)
+		})
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tiagomelo/macos-dmg-creator/macho"
+)
+
+const (
+	// defaultFormat is the format of the final DMG when none is given:
+	// zlib-compressed and read-only.
+	defaultFormat = "UDZO"
+
+	// defaultFilesystem and defaultLayout are the filesystem and the
+	// partition layout of the DMG template when none is given.
+	defaultFilesystem = "APFS"
+	defaultLayout     = "GPTSPUD"
+
+	// legacyFilesystem is the default filesystem of the DMG template for
+	// applications that support macOS versions older than minimumAPFSVersion.
+	legacyFilesystem = "HFS+"
+
+	// minimumAPFSVersion is the first macOS version that mounts APFS DMGs.
+	minimumAPFSVersion = "10.13"
+)
+
+// compressedFormats are the formats of the final DMG whose contents are compressed.
+var compressedFormats = []string{"UDZO", "UDBZ", "ULFO", "ULMO"}
+
+// imageOptions are the options of the DMG template and of the final DMG it is converted to.
+type imageOptions struct {
+	// format is the hdiutil format of the final DMG, e.g. UDZO or ULFO.
+	format string
+
+	// zlibLevel is the compression level of UDZO images, from 1 to 9,
+	// or 0 for the default level of hdiutil.
+	zlibLevel int
+
+	// filesystem is the filesystem of the DMG template, e.g. APFS or HFS+.
+	// When empty, it is picked by resolveFilesystem.
+	filesystem string
+
+	// layout is the partition layout of the DMG template, e.g. GPTSPUD.
+	layout string
+
+	// encryption is the encryption of the final DMG, AES-128 or AES-256,
+	// or empty for an unencrypted DMG.
+	encryption string
+
+	// passphrase is the passphrase of the encrypted DMG.
+	passphrase []byte
+
+	// staging tells whether the DMG is made from a staging folder
+	// instead of a mounted DMG template.
+	staging bool
+}
+
+// newImageOptions returns the options of the DMG requested by the parameters,
+// checking that they can be combined.
+func newImageOptions(params *CreateParams) (*imageOptions, error) {
+	options := &imageOptions{
+		format:     cmp.Or(params.Format, defaultFormat),
+		zlibLevel:  params.ZlibLevel,
+		filesystem: params.Filesystem,
+		layout:     cmp.Or(params.Layout, defaultLayout),
+		encryption: params.Encryption,
+		staging:    params.Staging,
+	}
+	if options.zlibLevel != 0 && options.format != "UDZO" {
+		return nil, errors.Errorf("the zlib level only applies to the UDZO format, not to %s", options.format)
+	}
+	// APFS needs a GUID partition map, or no partition map at all.
+	apfsLayout := options.layout == "GPTSPUD" || options.layout == "NONE"
+	if options.filesystem == "" && !apfsLayout {
+		options.filesystem = legacyFilesystem
+	}
+	if isAPFS(options.filesystem) && !apfsLayout {
+		return nil, errors.Errorf("the %s filesystem needs the GPTSPUD layout or none, not %s", options.filesystem, options.layout)
+	}
+	if options.staging {
+		if params.Window != nil && params.Window.UseFinder {
+			return nil, errors.New("the window cannot be laid out by Finder when staging, since the DMG is never mounted")
+		}
+		// the alias of the background image records the file IDs of the volume it is on,
+		// and hdiutil does not carry the custom icon flag of the folder over to the volume,
+		// so neither would survive the staging folder being turned into the DMG.
+		if params.BackgroundImagePath != "" {
+			return nil, errors.New("a background image cannot be set when staging, since its alias would refer to the staging folder")
+		}
+		if params.VolumeIconPath != "" || params.UseAppIconAsVolumeIcon {
+			return nil, errors.New("a volume icon cannot be set when staging, since hdiutil does not mark the volume as having a custom icon")
+		}
+	}
+	if options.encryption != "" {
+		// the license agreement is written to the resources of the DMG after it is converted,
+		// and the resources of an encrypted DMG cannot be read without its passphrase.
+		if len(params.Licenses) > 0 {
+			return nil, errors.New("a license agreement cannot be attached to an encrypted DMG")
+		}
+		// the DMG is verified by attaching it, which needs no passphrase only when unencrypted.
+		if params.Verify {
+			return nil, errors.New("an encrypted DMG cannot be verified")
+		}
+		options.passphrase = []byte(params.Passphrase)
+	}
+	return options, nil
+}
+
+// resolveFilesystem picks the filesystem of the DMG template, when none is given, after
+// the minimum macOS version of the application: APFS, unless the application supports
+// macOS versions that cannot mount APFS DMGs. When APFS is given for such an application,
+// it is kept but a warning is printed.
+func (o *imageOptions) resolveFilesystem(minimumSystemVersion string) {
+	supportsLegacy := minimumSystemVersion != "" && macho.CompareVersions(minimumSystemVersion, minimumAPFSVersion) < 0
+	switch {
+	case o.filesystem == "" && supportsLegacy:
+		o.filesystem = legacyFilesystem
+	case o.filesystem == "":
+		o.filesystem = defaultFilesystem
+	case isAPFS(o.filesystem) && supportsLegacy:
+		printWarning("the application supports macOS %s, but %s DMGs only mount on macOS %s and later; use an HFS+ filesystem to support older versions",
+			minimumSystemVersion, o.filesystem, minimumAPFSVersion)
+	}
+}
+
+// isAPFS tells whether the filesystem is one of the APFS variants.
+func isAPFS(filesystem string) bool {
+	return strings.HasSuffix(filesystem, "APFS")
+}
+
+// Result describes the DMG created by Create.
+type Result struct {
+	// Path is the path to the created DMG file.
+	Path string
+
+	// Format is the format of the DMG, e.g. UDZO.
+	Format string
+
+	// Size is the size of the DMG file, in bytes.
+	Size int64
+
+	// ContentsSize is the size, in bytes, of the files the DMG holds: those copied
+	// into the mounted DMG template or, when staging, into the staging folder.
+	ContentsSize int64
+
+	// ConversionDuration is how long converting the DMG template to the final format,
+	// or creating the DMG from the staging folder, took.
+	ConversionDuration time.Duration
+}
+
+// Compressed tells whether the format of the DMG is a compressed one,
+// for which the compression ratio is meaningful.
+func (r *Result) Compressed() bool {
+	return slices.Contains(compressedFormats, r.Format)
+}
+
+// CompressionRatio returns how many times smaller the DMG is than the files it holds,
+// e.g. 4.2, to compare formats and compression levels. It is 0 when the size of the DMG is unknown.
+func (r *Result) CompressionRatio() float64 {
+	if r.Size == 0 {
+		return 0
+	}
+	return float64(r.ContentsSize) / float64(r.Size)
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_newImageOptions(t *testing.T) {
+	testCases := []struct {
+		name    string
+		params  *CreateParams
+		want    *imageOptions
+		wantErr error
+	}{
+		{
+			name:   "defaults",
+			params: &CreateParams{},
+			want:   &imageOptions{format: "UDZO", layout: "GPTSPUD"},
+		},
+		{
+			name:   "zlib level",
+			params: &CreateParams{ZlibLevel: 9},
+			want:   &imageOptions{format: "UDZO", zlibLevel: 9, layout: "GPTSPUD"},
+		},
+		{
+			name:   "other format",
+			params: &CreateParams{Format: "ULFO"},
+			want:   &imageOptions{format: "ULFO", layout: "GPTSPUD"},
+		},
+		{
+			name:    "zlib level of other format",
+			params:  &CreateParams{Format: "UDBZ", ZlibLevel: 9},
+			wantErr: errors.New("the zlib level only applies to the UDZO format, not to UDBZ"),
+		},
+		{
+			name:   "APFS without partition map",
+			params: &CreateParams{Filesystem: "Case-sensitive APFS", Layout: "NONE"},
+			want:   &imageOptions{format: "UDZO", filesystem: "Case-sensitive APFS", layout: "NONE"},
+		},
+		{
+			name:   "HFS+ with Apple partition map",
+			params: &CreateParams{Filesystem: "Journaled HFS+", Layout: "SPUD"},
+			want:   &imageOptions{format: "UDZO", filesystem: "Journaled HFS+", layout: "SPUD"},
+		},
+		{
+			name:   "default filesystem with master boot record",
+			params: &CreateParams{Layout: "MBRSPUD"},
+			want:   &imageOptions{format: "UDZO", filesystem: "HFS+", layout: "MBRSPUD"},
+		},
+		{
+			name:    "APFS with Apple partition map",
+			params:  &CreateParams{Filesystem: "APFS", Layout: "SPUD"},
+			wantErr: errors.New("the APFS filesystem needs the GPTSPUD layout or none, not SPUD"),
+		},
+		{
+			name:   "staging",
+			params: &CreateParams{Staging: true, Window: &WindowLayout{IconSize: 96}},
+			want:   &imageOptions{format: "UDZO", layout: "GPTSPUD", staging: true},
+		},
+		{
+			name:    "staging with Finder layout",
+			params:  &CreateParams{Staging: true, Window: &WindowLayout{UseFinder: true}},
+			wantErr: errors.New("the window cannot be laid out by Finder when staging, since the DMG is never mounted"),
+		},
+		{
+			name:    "staging with background image",
+			params:  &CreateParams{Staging: true, BackgroundImagePath: "background.png"},
+			wantErr: errors.New("a background image cannot be set when staging, since its alias would refer to the staging folder"),
+		},
+		{
+			name:    "staging with volume icon",
+			params:  &CreateParams{Staging: true, VolumeIconPath: "volume.icns"},
+			wantErr: errors.New("a volume icon cannot be set when staging, since hdiutil does not mark the volume as having a custom icon"),
+		},
+		{
+			name:    "staging with app icon as volume icon",
+			params:  &CreateParams{Staging: true, UseAppIconAsVolumeIcon: true},
+			wantErr: errors.New("a volume icon cannot be set when staging, since hdiutil does not mark the volume as having a custom icon"),
+		},
+		{
+			name:   "encryption",
+			params: &CreateParams{Encryption: "AES-128", Passphrase: "secret"},
+			want:   &imageOptions{format: "UDZO", layout: "GPTSPUD", encryption: "AES-128", passphrase: []byte("secret")},
+		},
+		{
+			name:    "encryption with verification",
+			params:  &CreateParams{Encryption: "AES-256", Passphrase: "secret", Verify: true},
+			wantErr: errors.New("an encrypted DMG cannot be verified"),
+		},
+		{
+			name:    "encryption with license agreement",
+			params:  &CreateParams{Encryption: "AES-256", Passphrase: "secret", Licenses: []License{{Language: "en", Path: "LICENSE"}}},
+			wantErr: errors.New("a license agreement cannot be attached to an encrypted DMG"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := newImageOptions(tc.params)
+			if tc.wantErr != nil {
+				require.EqualError(t, err, tc.wantErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func Test_resolveFilesystem(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		filesystem           string
+		minimumSystemVersion string
+		want                 string
+		wantWarning          string
+	}{
+		{
+			name: "unknown minimum version",
+			want: "APFS",
+		},
+		{
+			name:                 "APFS-capable minimum version",
+			minimumSystemVersion: "10.15",
+			want:                 "APFS",
+		},
+		{
+			name:                 "older minimum version",
+			minimumSystemVersion: "10.12",
+			want:                 "HFS+",
+		},
+		{
+			name:                 "HFS+ given",
+			filesystem:           "Case-sensitive HFS+",
+			minimumSystemVersion: "11.0",
+			want:                 "Case-sensitive HFS+",
+		},
+		{
+			name:                 "APFS given for older minimum version",
+			filesystem:           "APFS",
+			minimumSystemVersion: "10.11",
+			want:                 "APFS",
+			wantWarning:          "⚠ warning: the application supports macOS 10.11, but APFS DMGs only mount on macOS 10.13 and later; use an HFS+ filesystem to support older versions\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var output bytes.Buffer
+			warningOutput = &output
+			options := &imageOptions{filesystem: tc.filesystem}
+			options.resolveFilesystem(tc.minimumSystemVersion)
+			require.Equal(t, tc.want, options.filesystem)
+			require.Equal(t, tc.wantWarning, output.String())
+		})
+	}
+}
+
+func TestResult_CompressionRatio(t *testing.T) {
+	testCases := []struct {
+		name   string
+		result *Result
+		want   float64
+	}{
+		{
+			name:   "compressed",
+			result: &Result{Size: 25, ContentsSize: 100},
+			want:   4,
+		},
+		{
+			name:   "unknown size",
+			result: &Result{ContentsSize: 100},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, tc.result.CompressionRatio())
+		})
+	}
+}
+
+func TestResult_Compressed(t *testing.T) {
+	testCases := []struct {
+		format string
+		want   bool
+	}{
+		{format: "UDZO", want: true},
+		{format: "UDBZ", want: true},
+		{format: "ULFO", want: true},
+		{format: "ULMO", want: true},
+		{format: "UDRO"},
+		{format: "UDRW"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			require.Equal(t, tc.want, (&Result{Format: tc.format}).Compressed())
+		})
+	}
+}
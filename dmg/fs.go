@@ -25,17 +25,38 @@ type fsOps interface {
 	// FileExists checks if a file exists at the given path.
 	FileExists(path string) (bool, error)
 
+	// DirExists checks if a directory exists at the given path.
+	DirExists(path string) (bool, error)
+
+	// Glob returns the paths matching the given pattern.
+	Glob(pattern string) ([]string, error)
+
+	// RealPath returns the path with all symbolic links resolved.
+	RealPath(path string) (string, error)
+
 	// CopyDir copies a directory from src to dst.
 	CopyDir(src, dst string) error
 
 	// DeleteDir deletes a directory at the given path.
 	DeleteDir(path string) error
 
+	// ReadFile reads the file named name.
+	ReadFile(name string) ([]byte, error)
+
 	// WriteFile writes data to a file named name.
 	WriteFile(name string, data []byte, perm os.FileMode) error
 
 	// CreateSymlink creates a symbolic link from src to dst.
 	CreateSymlink(src, dst string) error
+
+	// FileID returns the identifier of the file or directory on its volume.
+	FileID(path string) (uint64, error)
+
+	// FileSize returns the size of the file, in bytes.
+	FileSize(path string) (int64, error)
+
+	// DirSize returns the total size of the files in the directory, in bytes.
+	DirSize(path string) (int64, error)
 }
 
 // defaultFsOps is the default implementation of fsOps.
@@ -53,6 +74,18 @@ func (d defaultFsOps) FileExists(path string) (bool, error) {
 	return fs.FileExists(path)
 }
 
+func (d defaultFsOps) DirExists(path string) (bool, error) {
+	return fs.DirExists(path)
+}
+
+func (d defaultFsOps) Glob(pattern string) ([]string, error) {
+	return fs.Glob(pattern)
+}
+
+func (d defaultFsOps) RealPath(path string) (string, error) {
+	return fs.RealPath(path)
+}
+
 func (d defaultFsOps) CopyDir(src, dst string) error {
 	return fs.CopyDir(src, dst)
 }
@@ -61,6 +94,10 @@ func (d defaultFsOps) DeleteDir(path string) error {
 	return fs.DeleteDir(path)
 }
 
+func (d defaultFsOps) ReadFile(name string) ([]byte, error) {
+	return fs.ReadFile(name)
+}
+
 func (d defaultFsOps) WriteFile(name string, data []byte, perm os.FileMode) error {
 	return fs.WriteFile(name, data, perm)
 }
@@ -68,3 +105,15 @@ func (d defaultFsOps) WriteFile(name string, data []byte, perm os.FileMode) erro
 func (d defaultFsOps) CreateSymlink(src, dst string) error {
 	return fs.CreateSymlink(src, dst)
 }
+
+func (d defaultFsOps) FileID(path string) (uint64, error) {
+	return fs.FileID(path)
+}
+
+func (d defaultFsOps) FileSize(path string) (int64, error) {
+	return fs.FileSize(path)
+}
+
+func (d defaultFsOps) DirSize(path string) (int64, error) {
+	return fs.DirSize(path)
+}
@@ -0,0 +1,24 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import "github.com/tiagomelo/macos-dmg-creator/fyneapp"
+
+// fyneAppProvider is a variable that holds the function
+// that loads the metadata of Fyne applications.
+var fyneAppProvider fyneAppOps = defaultFyneApp{}
+
+// fyneAppOps defines an interface for loading the metadata of Fyne applications.
+type fyneAppOps interface {
+	// Load loads the metadata from the FyneApp.toml file at the given path.
+	Load(path string) (*fyneapp.Metadata, error)
+}
+
+// defaultFyneApp is the default implementation of fyneAppOps.
+type defaultFyneApp struct{}
+
+func (d defaultFyneApp) Load(path string) (*fyneapp.Metadata, error) {
+	return fyneapp.Load(path)
+}
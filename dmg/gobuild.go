@@ -0,0 +1,24 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import "github.com/tiagomelo/macos-dmg-creator/gobuild"
+
+// goBuildProvider is a variable that holds the function
+// that builds Go main packages.
+var goBuildProvider goBuildOps = defaultGoBuild{}
+
+// goBuildOps defines an interface for building Go main packages.
+type goBuildOps interface {
+	// Build builds the Go main package for darwin with the given parameters.
+	Build(params *gobuild.Params) error
+}
+
+// defaultGoBuild is the default implementation of goBuildOps.
+type defaultGoBuild struct{}
+
+func (d defaultGoBuild) Build(params *gobuild.Params) error {
+	return gobuild.Build(params)
+}
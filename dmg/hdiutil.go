@@ -21,8 +21,13 @@ type hdiutilOps interface {
 	// UnmountDMG unmounts the DMG file that was previously mounted.
 	UnmountDMG(volName string) error
 
-	// ConvertDMG converts a DMG file to a different format.
-	ConvertDMG(dmgPath, dmgOutputFileName string) error
+	// ConvertDMG converts a DMG file to the format given in the options, e.g. UDZO,
+	// encrypting it when an encryption is given.
+	ConvertDMG(dmgPath, dmgOutputFileName string, opts *hdiutil.ConvertOptions) error
+
+	// CreateDMGFromFolder creates a DMG file in one step from the contents of
+	// the source folder, in the format given in the options, without mounting it.
+	CreateDMGFromFolder(srcFolder, fs, volName, layout, output string, opts *hdiutil.ConvertOptions) error
 }
 
 // defaultHdiutil is the default implementation of hdiutilOps.
@@ -40,6 +45,10 @@ func (d defaultHdiutil) UnmountDMG(volName string) error {
 	return hdiutil.UnmountDMG(volName)
 }
 
-func (d defaultHdiutil) ConvertDMG(dmgPath, dmgOutputFileName string) error {
-	return hdiutil.ConvertDMG(dmgPath, dmgOutputFileName)
+func (d defaultHdiutil) ConvertDMG(dmgPath, dmgOutputFileName string, opts *hdiutil.ConvertOptions) error {
+	return hdiutil.ConvertDMG(dmgPath, dmgOutputFileName, opts)
+}
+
+func (d defaultHdiutil) CreateDMGFromFolder(srcFolder, fs, volName, layout, output string, opts *hdiutil.ConvertOptions) error {
+	return hdiutil.CreateDMGFromFolder(srcFolder, fs, volName, layout, output, opts)
 }
@@ -0,0 +1,31 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import "github.com/tiagomelo/macos-dmg-creator/hidpi"
+
+// imageProvider is a variable that holds the function
+// that reads and combines images.
+var imageProvider imageOps = defaultImage{}
+
+// imageOps defines an interface for reading and combining images.
+type imageOps interface {
+	// Size returns the size, in pixels, of the image at the given path.
+	Size(path string) (width, height int, err error)
+
+	// CombineTIFF writes a multi-resolution TIFF file holding the image and its 2x version.
+	CombineTIFF(outputPath, imagePath, image2xPath string) error
+}
+
+// defaultImage is the default implementation of imageOps.
+type defaultImage struct{}
+
+func (d defaultImage) Size(path string) (int, int, error) {
+	return hidpi.Size(path)
+}
+
+func (d defaultImage) CombineTIFF(outputPath, imagePath, image2xPath string) error {
+	return hidpi.CombineTIFF(outputPath, imagePath, image2xPath)
+}
@@ -4,20 +4,74 @@
 
 package dmg
 
-const infoPlistTpl = `<?xml version="1.0" encoding="UTF-8"?>
-<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
-<plist version="1.0">
-<dict>
-	<key>CFBundleExecutable</key>
-	<string>{{.AppName}}</string>
-	<key>CFBundleIconFile</key>
-	<string>icon.icns</string>
-	<key>CFBundleIdentifier</key>
-	<string>{{.BundleIdentifier}}</string>
-	<key>NSHighResolutionCapable</key>
-	<true/>
-	<key>LSUIElement</key>
-	<true/>
-</dict>
-</plist>
-`
+// infoPlistData holds the values that are written to the Info.plist file.
+type infoPlistData struct {
+	Name                 string
+	Executable           string
+	BundleIdentifier     string
+	MinimumSystemVersion string
+	ShortVersion         string
+	BundleVersion        string
+	VCSRevision          string
+	PackageType          string
+	Signature            string
+	DevelopmentRegion    string
+	Localizations        []string
+	DisplayName          string
+	LocalizedDisplayName bool
+	UsageDescriptions    map[string]string
+}
+
+const (
+	// infoDictionaryVersion is the version of the Info.plist format (CFBundleInfoDictionaryVersion).
+	infoDictionaryVersion = "6.0"
+
+	// iconFile is the name of the icon of the application in the Resources directory.
+	iconFile = "icon.icns"
+)
+
+// infoPlist is the Info.plist file of the application bundle, as encoded by the plist package.
+type infoPlist struct {
+	InfoDictionaryVersion string            `plist:"CFBundleInfoDictionaryVersion"`
+	Name                  string            `plist:"CFBundleName,omitempty"`
+	DisplayName           string            `plist:"CFBundleDisplayName,omitempty"`
+	LocalizedDisplayName  bool              `plist:"LSHasLocalizedDisplayName,omitempty"`
+	DevelopmentRegion     string            `plist:"CFBundleDevelopmentRegion,omitempty"`
+	Localizations         []string          `plist:"CFBundleLocalizations,omitempty"`
+	Executable            string            `plist:"CFBundleExecutable"`
+	IconFile              string            `plist:"CFBundleIconFile"`
+	BundleIdentifier      string            `plist:"CFBundleIdentifier"`
+	PackageType           string            `plist:"CFBundlePackageType"`
+	Signature             string            `plist:"CFBundleSignature"`
+	HighResolutionCapable bool              `plist:"NSHighResolutionCapable"`
+	UIElement             bool              `plist:"LSUIElement"`
+	MinimumSystemVersion  string            `plist:"LSMinimumSystemVersion,omitempty"`
+	ShortVersion          string            `plist:"CFBundleShortVersionString,omitempty"`
+	BundleVersion         string            `plist:"CFBundleVersion,omitempty"`
+	UsageDescriptions     map[string]string `plist:",inline"`
+	VCSRevision           string            `plist:"GoVCSRevision,omitempty"`
+}
+
+// newInfoPlist returns the Info.plist file with the given values.
+func newInfoPlist(data *infoPlistData) *infoPlist {
+	return &infoPlist{
+		InfoDictionaryVersion: infoDictionaryVersion,
+		Name:                  data.Name,
+		DisplayName:           data.DisplayName,
+		LocalizedDisplayName:  data.LocalizedDisplayName,
+		DevelopmentRegion:     data.DevelopmentRegion,
+		Localizations:         data.Localizations,
+		Executable:            data.Executable,
+		IconFile:              iconFile,
+		BundleIdentifier:      data.BundleIdentifier,
+		PackageType:           data.PackageType,
+		Signature:             data.Signature,
+		HighResolutionCapable: true,
+		UIElement:             true,
+		MinimumSystemVersion:  data.MinimumSystemVersion,
+		ShortVersion:          data.ShortVersion,
+		BundleVersion:         data.BundleVersion,
+		UsageDescriptions:     data.UsageDescriptions,
+		VCSRevision:           data.VCSRevision,
+	}
+}
@@ -38,23 +38,24 @@ func TestMain(m *testing.M) {
 
 func TestCreateDMG(t *testing.T) {
 	const (
-		appName       = "GreeterApp"
-		appBinaryPath = "sampleapp/SampleApp"
-		bundleID      = "info.tiago.greeterapp"
-		iconPath      = "sampleapp/icon.png"
-		outputDir     = "sampleapp"
+		appName    = "GreeterApp"
+		sourcePath = "sampleapp/cmd"
+		bundleID   = "info.tiago.greeterapp"
+		iconPath   = "sampleapp/icon.png"
+		outputDir  = "sampleapp"
 	)
 
-	var err error
-	createdDMGPath, err = dmg.Create(&dmg.CreateParams{
+	result, err := dmg.Create(&dmg.CreateParams{
 		AppName:          appName,
-		AppBinaryPath:    appBinaryPath,
+		SourcePath:       sourcePath,
+		CGOEnabled:       true,
 		BundleIdentifier: bundleID,
 		IconPath:         iconPath,
 		OutputDir:        outputDir,
 	})
 
 	require.NoError(t, err)
+	createdDMGPath = result.Path
 	require.NotEmpty(t, createdDMGPath)
 	require.FileExists(t, createdDMGPath)
 }
@@ -0,0 +1,148 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tiagomelo/macos-dmg-creator/plist"
+)
+
+const (
+	launchAgentsDir = "Contents/Library/LaunchAgents"
+	loginItemsDir   = "Contents/Library/LoginItems"
+)
+
+// LaunchAgent declares a launchd agent embedded in the application bundle. Its plist
+// is written to Contents/Library/LaunchAgents/<Label>.plist, from where the application
+// registers it with SMAppService, e.g. to run a menu bar helper at login.
+type LaunchAgent struct {
+	// Label uniquely identifies the agent and names its plist file. It must start
+	// with the bundle identifier of the application, e.g. "com.example.myapp.agent".
+	Label string `validate:"required,excludesall=/"`
+
+	// Program is the path, relative to the application bundle, of the executable the
+	// agent runs (BundleProgram), e.g. "Contents/MacOS/myagent". It is copied into
+	// the bundle as a helper.
+	Program string `validate:"required"`
+
+	// Arguments are the arguments the program is run with.
+	Arguments []string
+
+	// RunAtLoad indicates whether the agent is started as soon as it is loaded.
+	RunAtLoad bool
+
+	// KeepAlive indicates whether the agent is restarted whenever it exits.
+	KeepAlive bool
+
+	// EnvironmentVariables are the environment variables the program is run with.
+	EnvironmentVariables map[string]string
+}
+
+// launchAgentPlist is the plist file of a launch agent, as encoded by the plist package.
+type launchAgentPlist struct {
+	Label                       string            `plist:"Label"`
+	BundleProgram               string            `plist:"BundleProgram"`
+	ProgramArguments            []string          `plist:"ProgramArguments,omitempty"`
+	RunAtLoad                   bool              `plist:"RunAtLoad,omitempty"`
+	KeepAlive                   bool              `plist:"KeepAlive,omitempty"`
+	EnvironmentVariables        map[string]string `plist:"EnvironmentVariables,omitempty"`
+	AssociatedBundleIdentifiers []string          `plist:"AssociatedBundleIdentifiers"`
+}
+
+// checkLaunchAgents checks that every launch agent is declared once, is labeled
+// after the bundle identifier and runs a program inside the Contents directory.
+func checkLaunchAgents(bundleIdentifier string, launchAgents []LaunchAgent) error {
+	labels := map[string]bool{}
+	for _, launchAgent := range launchAgents {
+		if !hasBundleIdentifierPrefix(launchAgent.Label, bundleIdentifier) {
+			return errors.Errorf("launch agent label [%s] must start with the bundle identifier [%s] followed by a dot", launchAgent.Label, bundleIdentifier)
+		}
+		if labels[launchAgent.Label] {
+			return errors.Errorf("launch agent [%s] is declared more than once", launchAgent.Label)
+		}
+		labels[launchAgent.Label] = true
+		if !filepath.IsLocal(launchAgent.Program) || !strings.HasPrefix(filepath.Clean(launchAgent.Program), contentsDir+"/") {
+			return errors.Errorf("program [%s] of launch agent [%s] is not a path inside the Contents directory of the app bundle", launchAgent.Program, launchAgent.Label)
+		}
+	}
+	return nil
+}
+
+// createLaunchAgentFiles writes the plist file of every launch agent, making
+// sure the program it runs was copied into the application bundle.
+func createLaunchAgentFiles(bundleIdentifier string, launchAgents []LaunchAgent, appBundleDirPath string) error {
+	if len(launchAgents) == 0 {
+		return nil
+	}
+	launchAgentsDirPath := filepath.Join(appBundleDirPath, launchAgentsDir)
+	if err := fsOpsProvider.MkdirAll(launchAgentsDirPath, bundleDirMode); err != nil {
+		return errors.Wrapf(err, "error when creating directory [%s]", launchAgentsDirPath)
+	}
+	for _, launchAgent := range launchAgents {
+		programPath := filepath.Join(appBundleDirPath, launchAgent.Program)
+		exists, err := fsOpsProvider.FileExists(programPath)
+		if err != nil {
+			return errors.Wrapf(err, "error when checking if program [%s] of launch agent [%s] exists", programPath, launchAgent.Label)
+		}
+		if !exists {
+			return errors.Errorf("program [%s] of launch agent [%s] is not in the app bundle; copy it as a helper", launchAgent.Program, launchAgent.Label)
+		}
+		data, err := plist.Encode(newLaunchAgentPlist(bundleIdentifier, launchAgent))
+		if err != nil {
+			return errors.Wrapf(err, "error when encoding plist of launch agent [%s]", launchAgent.Label)
+		}
+		plistPath := filepath.Join(launchAgentsDirPath, launchAgent.Label+".plist")
+		if err := fsOpsProvider.WriteFile(plistPath, data, bundleDataMode); err != nil {
+			return errors.Wrapf(err, "error when writing launch agent plist to [%s]", plistPath)
+		}
+	}
+	return nil
+}
+
+// newLaunchAgentPlist returns the plist file of the launch agent, associated with the application.
+func newLaunchAgentPlist(bundleIdentifier string, launchAgent LaunchAgent) *launchAgentPlist {
+	launchAgentPlist := &launchAgentPlist{
+		Label:                       launchAgent.Label,
+		BundleProgram:               filepath.Clean(launchAgent.Program),
+		RunAtLoad:                   launchAgent.RunAtLoad,
+		KeepAlive:                   launchAgent.KeepAlive,
+		EnvironmentVariables:        launchAgent.EnvironmentVariables,
+		AssociatedBundleIdentifiers: []string{bundleIdentifier},
+	}
+	if len(launchAgent.Arguments) > 0 {
+		// the first of the program arguments is the name the program is run as.
+		launchAgentPlist.ProgramArguments = append([]string{filepath.Base(launchAgent.Program)}, launchAgent.Arguments...)
+	}
+	return launchAgentPlist
+}
+
+// checkLoginItems checks that every login item copied into the application bundle
+// is an application bundle whose identifier starts with the one of the application.
+func checkLoginItems(bundleIdentifier, appBundleDirPath string) error {
+	loginItemsDirPath := filepath.Join(appBundleDirPath, loginItemsDir)
+	loginItemPaths, err := fsOpsProvider.Glob(filepath.Join(escapeGlobPattern(loginItemsDirPath), "*"))
+	if err != nil {
+		return errors.Wrapf(err, "error when listing [%s]", loginItemsDirPath)
+	}
+	for _, loginItemPath := range loginItemPaths {
+		info, err := bundleProvider.Validate(loginItemPath)
+		if err != nil {
+			return errors.Wrapf(err, "error when validating login item [%s]", filepath.Base(loginItemPath))
+		}
+		if !hasBundleIdentifierPrefix(info.Identifier, bundleIdentifier) {
+			return errors.Errorf("login item [%s] has the bundle identifier [%s], which must start with the bundle identifier [%s] followed by a dot", filepath.Base(loginItemPath), info.Identifier, bundleIdentifier)
+		}
+	}
+	return nil
+}
+
+// hasBundleIdentifierPrefix reports whether the identifier is
+// nested under the bundle identifier, e.g. com.example.myapp.agent.
+func hasBundleIdentifierPrefix(identifier, bundleIdentifier string) bool {
+	return len(identifier) > len(bundleIdentifier)+1 && strings.HasPrefix(identifier, bundleIdentifier+".")
+}
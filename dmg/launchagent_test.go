@@ -0,0 +1,267 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import (
+	"os"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/macos-dmg-creator/bundle"
+)
+
+func Test_checkLaunchAgents(t *testing.T) {
+	testCases := []struct {
+		name         string
+		launchAgents []LaunchAgent
+		wantErr      error
+	}{
+		{
+			name: "happy path",
+			launchAgents: []LaunchAgent{
+				{Label: "com.example.myapp.agent", Program: "Contents/MacOS/myagent"},
+				{Label: "com.example.myapp.updater", Program: "Contents/Library/Helpers/updater"},
+			},
+		},
+		{
+			name:         "label without bundle identifier",
+			launchAgents: []LaunchAgent{{Label: "com.example.myapplication", Program: "Contents/MacOS/myagent"}},
+			wantErr:      errors.New("launch agent label [com.example.myapplication] must start with the bundle identifier [com.example.myapp] followed by a dot"),
+		},
+		{
+			name: "label declared twice",
+			launchAgents: []LaunchAgent{
+				{Label: "com.example.myapp.agent", Program: "Contents/MacOS/myagent"},
+				{Label: "com.example.myapp.agent", Program: "Contents/MacOS/other"},
+			},
+			wantErr: errors.New("launch agent [com.example.myapp.agent] is declared more than once"),
+		},
+		{
+			name:         "program outside of the bundle",
+			launchAgents: []LaunchAgent{{Label: "com.example.myapp.agent", Program: "/usr/local/bin/myagent"}},
+			wantErr:      errors.New("program [/usr/local/bin/myagent] of launch agent [com.example.myapp.agent] is not a path inside the Contents directory of the app bundle"),
+		},
+		{
+			name:         "program outside of Contents",
+			launchAgents: []LaunchAgent{{Label: "com.example.myapp.agent", Program: "Contents/../myagent"}},
+			wantErr:      errors.New("program [Contents/../myagent] of launch agent [com.example.myapp.agent] is not a path inside the Contents directory of the app bundle"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkLaunchAgents("com.example.myapp", tc.launchAgents)
+			if err != nil {
+				if tc.wantErr == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				if tc.wantErr.Error() != err.Error() {
+					t.Fatalf(`expected error "%v", got "%v"`, tc.wantErr, err)
+				}
+				return
+			}
+			if tc.wantErr != nil {
+				t.Fatalf(`expected error "%v", got nil`, tc.wantErr)
+			}
+		})
+	}
+}
+
+func Test_createLaunchAgentFiles(t *testing.T) {
+	testCases := []struct {
+		name              string
+		mockFsOpsProvider func() *mockFsOpsProvider
+		want              map[string][]byte
+		wantErr           error
+	}{
+		{
+			name: "happy path",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					existingFiles: map[string]bool{"MyApp.app/Contents/MacOS/myagent": true},
+				}
+			},
+			want: map[string][]byte{
+				"MyApp.app/Contents/Library/LaunchAgents/com.example.myapp.agent.plist": []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>com.example.myapp.agent</string>
+	<key>BundleProgram</key>
+	<string>Contents/MacOS/myagent</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>myagent</string>
+		<string>--menu-bar</string>
+	</array>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<true/>
+	<key>EnvironmentVariables</key>
+	<dict>
+		<key>LOG_LEVEL</key>
+		<string>info</string>
+	</dict>
+	<key>AssociatedBundleIdentifiers</key>
+	<array>
+		<string>com.example.myapp</string>
+	</array>
+</dict>
+</plist>
+`),
+			},
+		},
+		{
+			name: "program not in the bundle",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			wantErr: errors.New("program [Contents/MacOS/myagent] of launch agent [com.example.myapp.agent] is not in the app bundle; copy it as a helper"),
+		},
+		{
+			name: "error when creating directory",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{expectedMkdirAllErr: os.ErrPermission}
+			},
+			wantErr: errors.New("error when creating directory [MyApp.app/Contents/Library/LaunchAgents]: permission denied"),
+		},
+		{
+			name: "error when writing plist",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					existingFiles:        map[string]bool{"MyApp.app/Contents/MacOS/myagent": true},
+					expectedWriteFileErr: os.ErrPermission,
+				}
+			},
+			wantErr: errors.New("error when writing launch agent plist to [MyApp.app/Contents/Library/LaunchAgents/com.example.myapp.agent.plist]: permission denied"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockFsOpsProvider := tc.mockFsOpsProvider()
+			fsOpsProvider = mockFsOpsProvider
+
+			err := createLaunchAgentFiles("com.example.myapp", []LaunchAgent{{
+				Label:                "com.example.myapp.agent",
+				Program:              "Contents/MacOS/myagent",
+				Arguments:            []string{"--menu-bar"},
+				RunAtLoad:            true,
+				KeepAlive:            true,
+				EnvironmentVariables: map[string]string{"LOG_LEVEL": "info"},
+			}}, "MyApp.app")
+			if err != nil {
+				if tc.wantErr == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				if tc.wantErr.Error() != err.Error() {
+					t.Fatalf(`expected error "%v", got "%v"`, tc.wantErr, err)
+				}
+				return
+			}
+			if tc.wantErr != nil {
+				t.Fatalf(`expected error "%v", got nil`, tc.wantErr)
+			}
+			require.Equal(t, tc.want, mockFsOpsProvider.writtenFiles)
+		})
+	}
+}
+
+func Test_checkLoginItems(t *testing.T) {
+	testCases := []struct {
+		name               string
+		mockFsOpsProvider  func() *mockFsOpsProvider
+		mockBundleProvider func() *mockBundleProvider
+		wantValidatedPath  string
+		wantErr            error
+	}{
+		{
+			name: "happy path",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					expectedGlobMatches: map[string][]string{
+						"MyApp.app/Contents/Library/LoginItems/*": {"MyApp.app/Contents/Library/LoginItems/MyAppHelper.app"},
+					},
+				}
+			},
+			mockBundleProvider: func() *mockBundleProvider {
+				return &mockBundleProvider{expectedInfo: &bundle.Info{Identifier: "com.example.myapp.helper"}}
+			},
+			wantValidatedPath: "MyApp.app/Contents/Library/LoginItems/MyAppHelper.app",
+		},
+		{
+			name: "no login items",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockBundleProvider: func() *mockBundleProvider {
+				return &mockBundleProvider{}
+			},
+		},
+		{
+			name: "login item without bundle identifier prefix",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					expectedGlobMatches: map[string][]string{
+						"MyApp.app/Contents/Library/LoginItems/*": {"MyApp.app/Contents/Library/LoginItems/MyAppHelper.app"},
+					},
+				}
+			},
+			mockBundleProvider: func() *mockBundleProvider {
+				return &mockBundleProvider{expectedInfo: &bundle.Info{Identifier: "com.example.myapp"}}
+			},
+			wantValidatedPath: "MyApp.app/Contents/Library/LoginItems/MyAppHelper.app",
+			wantErr:           errors.New("login item [MyAppHelper.app] has the bundle identifier [com.example.myapp], which must start with the bundle identifier [com.example.myapp] followed by a dot"),
+		},
+		{
+			name: "error when validating login item",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					expectedGlobMatches: map[string][]string{
+						"MyApp.app/Contents/Library/LoginItems/*": {"MyApp.app/Contents/Library/LoginItems/MyAppHelper.app"},
+					},
+				}
+			},
+			mockBundleProvider: func() *mockBundleProvider {
+				return &mockBundleProvider{expectedValidateErr: errors.New("[MyApp.app/Contents/Library/LoginItems/MyAppHelper.app/Contents/Info.plist] does not declare CFBundleIdentifier")}
+			},
+			wantValidatedPath: "MyApp.app/Contents/Library/LoginItems/MyAppHelper.app",
+			wantErr:           errors.New("error when validating login item [MyAppHelper.app]: [MyApp.app/Contents/Library/LoginItems/MyAppHelper.app/Contents/Info.plist] does not declare CFBundleIdentifier"),
+		},
+		{
+			name: "error when listing login items",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{expectedGlobErr: os.ErrPermission}
+			},
+			mockBundleProvider: func() *mockBundleProvider {
+				return &mockBundleProvider{}
+			},
+			wantErr: errors.New("error when listing [MyApp.app/Contents/Library/LoginItems]: permission denied"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fsOpsProvider = tc.mockFsOpsProvider()
+			mockBundleProvider := tc.mockBundleProvider()
+			bundleProvider = mockBundleProvider
+
+			err := checkLoginItems("com.example.myapp", "MyApp.app")
+			require.Equal(t, tc.wantValidatedPath, mockBundleProvider.validatedPath)
+			if err != nil {
+				if tc.wantErr == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				if tc.wantErr.Error() != err.Error() {
+					t.Fatalf(`expected error "%v", got "%v"`, tc.wantErr, err)
+				}
+				return
+			}
+			if tc.wantErr != nil {
+				t.Fatalf(`expected error "%v", got nil`, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/pkg/errors"
+	"github.com/tiagomelo/macos-dmg-creator/script"
+)
+
+// launcherFile is the name of the generated launcher in the temporary working directory.
+const launcherFile = "launcher"
+
+// systemInterpreterDirs are the directories of the interpreters that ship with macOS.
+var systemInterpreterDirs = []string{"/bin/", "/usr/bin/"}
+
+// removedInterpreters are the interpreters, by path or by the command /usr/bin/env
+// runs, that shipped with older versions of macOS and were removed in macOS 12.3.
+var removedInterpreters = []string{"/usr/bin/python", "/usr/bin/python2", "/usr/bin/python2.7", "python", "python2", "python2.7"}
+
+// launcherData holds the values that are rendered into the launcher.
+type launcherData struct {
+	// Script is the name of the script in the Resources directory.
+	Script string
+
+	// Terminal indicates whether the script is opened in Terminal.
+	Terminal bool
+}
+
+// launcherTpl is the template of the shell launcher that becomes the bundle
+// executable of script applications. It runs the script in the Resources
+// directory, passing the arguments along, or opens it in Terminal.
+var launcherTpl = template.Must(template.New("launcher").Funcs(template.FuncMap{
+	"sh": shellQuote,
+}).Parse(`#!/bin/sh
+# Runs the application script in Contents/Resources.
+resources="$(cd "$(dirname "$0")/../Resources" && pwd)" || exit 1
+{{- if .Terminal}}
+exec /usr/bin/open -a Terminal "$resources/"{{sh .Script}}
+{{- else}}
+exec "$resources/"{{sh .Script}} "$@"
+{{- end}}
+`))
+
+// createScriptLauncher inspects the script used as the entry point of the
+// application and writes the launcher that runs it, returning its path.
+func createScriptLauncher(params *CreateParams, tmpWorkDir string) (string, error) {
+	inspectSpinner := spinner.New(spinner.CharSets[14], 300*time.Millisecond)
+	inspectSpinner.Suffix = " inspecting script..."
+	inspectSpinner.FinalMSG = "✔ inspecting script...\n"
+	inspectSpinner.Start()
+
+	info, err := scriptProvider.Inspect(params.ScriptPath)
+	if err == nil {
+		inspectSpinner.FinalMSG = fmt.Sprintf("✔ inspecting script... [interpreter: %s]\n", info)
+	}
+	inspectSpinner.Stop()
+	if err != nil {
+		return "", errors.Wrap(err, "error when inspecting script")
+	}
+	if warning := interpreterWarning(info); warning != "" {
+		printWarning("%s", warning)
+	}
+
+	var launcher bytes.Buffer
+	if err := launcherTpl.Execute(&launcher, &launcherData{
+		Script:   filepath.Base(params.ScriptPath),
+		Terminal: params.RunInTerminal,
+	}); err != nil {
+		return "", errors.Wrap(err, "error when rendering launcher")
+	}
+	launcherPath := filepath.Join(tmpWorkDir, launcherFile)
+	if err := fsOpsProvider.WriteFile(launcherPath, launcher.Bytes(), bundleDirMode); err != nil {
+		return "", errors.Wrapf(err, "error when writing launcher to [%s]", launcherPath)
+	}
+	return launcherPath, nil
+}
+
+// interpreterWarning returns a warning about the interpreter of the
+// script when it may be missing on the machines running the application.
+func interpreterWarning(info *script.Info) string {
+	interpreter := info.Interpreter
+	if info.Command != "" {
+		interpreter = info.Command
+	}
+	if slices.Contains(removedInterpreters, interpreter) {
+		return fmt.Sprintf("the script runs with %s, which was removed in macOS 12.3", interpreter)
+	}
+	if info.Command != "" || slices.ContainsFunc(systemInterpreterDirs, func(dir string) bool {
+		return strings.HasPrefix(interpreter, dir)
+	}) {
+		return ""
+	}
+	return fmt.Sprintf("the script runs with %s, which is not part of macOS; it must be installed on the machines running the app", interpreter)
+}
+
+// shellQuote quotes the string so the shell reads it as a single word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
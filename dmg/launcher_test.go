@@ -0,0 +1,149 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/macos-dmg-creator/script"
+)
+
+func Test_createScriptLauncher(t *testing.T) {
+	testCases := []struct {
+		name               string
+		params             *CreateParams
+		mockScriptProvider func() *mockScriptProvider
+		mockFsOpsProvider  func() *mockFsOpsProvider
+		want               string
+		wantWarning        string
+		wantErr            error
+	}{
+		{
+			name:   "happy path",
+			params: &CreateParams{ScriptPath: "scripts/it's run.sh"},
+			mockScriptProvider: func() *mockScriptProvider {
+				return &mockScriptProvider{expectedInfo: &script.Info{Interpreter: "/bin/bash"}}
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			want: `#!/bin/sh
+# Runs the application script in Contents/Resources.
+resources="$(cd "$(dirname "$0")/../Resources" && pwd)" || exit 1
+exec "$resources/"'it'\''s run.sh' "$@"
+`,
+		},
+		{
+			name:   "happy path in Terminal",
+			params: &CreateParams{ScriptPath: "scripts/tool.py", RunInTerminal: true},
+			mockScriptProvider: func() *mockScriptProvider {
+				return &mockScriptProvider{expectedInfo: &script.Info{Interpreter: "/opt/homebrew/bin/python3"}}
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			want: `#!/bin/sh
+# Runs the application script in Contents/Resources.
+resources="$(cd "$(dirname "$0")/../Resources" && pwd)" || exit 1
+exec /usr/bin/open -a Terminal "$resources/"'tool.py'
+`,
+			wantWarning: "⚠ warning: the script runs with /opt/homebrew/bin/python3, which is not part of macOS; it must be installed on the machines running the app\n",
+		},
+		{
+			name:   "error when inspecting script",
+			params: &CreateParams{ScriptPath: "scripts/run.sh"},
+			mockScriptProvider: func() *mockScriptProvider {
+				return &mockScriptProvider{expectedInspectErr: errors.New("script [scripts/run.sh] is not executable; make it executable with chmod +x")}
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			wantErr: errors.New("error when inspecting script: script [scripts/run.sh] is not executable; make it executable with chmod +x"),
+		},
+		{
+			name:   "error when writing launcher",
+			params: &CreateParams{ScriptPath: "scripts/run.sh"},
+			mockScriptProvider: func() *mockScriptProvider {
+				return &mockScriptProvider{expectedInfo: &script.Info{Interpreter: "/bin/sh"}}
+			},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{expectedWriteFileErr: os.ErrPermission}
+			},
+			wantErr: errors.New("error when writing launcher to [tmp/launcher]: permission denied"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var output bytes.Buffer
+			warningOutput = &output
+			mockScriptProvider := tc.mockScriptProvider()
+			scriptProvider = mockScriptProvider
+			mockFsOpsProvider := tc.mockFsOpsProvider()
+			fsOpsProvider = mockFsOpsProvider
+
+			got, err := createScriptLauncher(tc.params, "tmp")
+			if err != nil {
+				if tc.wantErr == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				if tc.wantErr.Error() != err.Error() {
+					t.Fatalf(`expected error "%v", got "%v"`, tc.wantErr, err)
+				}
+				return
+			}
+			if tc.wantErr != nil {
+				t.Fatalf(`expected error "%v", got nil`, tc.wantErr)
+			}
+			require.Equal(t, "tmp/launcher", got)
+			require.Equal(t, tc.params.ScriptPath, mockScriptProvider.inspectedPath)
+			require.Equal(t, tc.want, string(mockFsOpsProvider.writtenFiles["tmp/launcher"]))
+			require.Equal(t, tc.wantWarning, output.String())
+		})
+	}
+}
+
+func Test_interpreterWarning(t *testing.T) {
+	testCases := []struct {
+		name string
+		info *script.Info
+		want string
+	}{
+		{
+			name: "system interpreter",
+			info: &script.Info{Interpreter: "/bin/zsh"},
+		},
+		{
+			name: "interpreter found with env",
+			info: &script.Info{Interpreter: "/usr/bin/env", Command: "python3"},
+		},
+		{
+			name: "removed interpreter",
+			info: &script.Info{Interpreter: "/usr/bin/python"},
+			want: "the script runs with /usr/bin/python, which was removed in macOS 12.3",
+		},
+		{
+			name: "removed interpreter found with env",
+			info: &script.Info{Interpreter: "/usr/bin/env", Command: "python2"},
+			want: "the script runs with python2, which was removed in macOS 12.3",
+		},
+		{
+			name: "third-party interpreter",
+			info: &script.Info{Interpreter: "/usr/local/bin/node"},
+			want: "the script runs with /usr/local/bin/node, which is not part of macOS; it must be installed on the machines running the app",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := interpreterWarning(tc.info)
+			if got != tc.want {
+				t.Fatalf(`expected warning "%s", got "%s"`, tc.want, got)
+			}
+		})
+	}
+}
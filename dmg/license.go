@@ -0,0 +1,65 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import (
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/pkg/errors"
+	"github.com/tiagomelo/macos-dmg-creator/udif"
+)
+
+// License is the software license agreement, in one language, that users accept
+// before the DMG mounts.
+type License struct {
+	// Language is the code of the language of the license: en, fr, de, it, nl, sv,
+	// es, da, pt, nb, fi, ja, ru, ko, zh-Hans or zh-Hant.
+	Language string `validate:"required"`
+
+	// Path is the path to the license, a plain UTF-8 text file or an RTF document.
+	Path string `validate:"required"`
+}
+
+// licenseResources reads the license agreements and encodes them into the UDIF
+// resources shown before the DMG mounts. It returns nil when there is no license.
+func licenseResources(licenses []License) (udif.Resources, error) {
+	if len(licenses) == 0 {
+		return nil, nil
+	}
+	var texts []udif.License
+	for _, license := range licenses {
+		text, err := fsOpsProvider.ReadFile(license.Path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error when reading license [%s]", license.Language)
+		}
+		texts = append(texts, udif.License{Language: license.Language, Text: text})
+	}
+	resources, err := udif.LicenseResources(texts)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when encoding license agreement")
+	}
+	return resources, nil
+}
+
+// attachLicense embeds the encoded license agreements into the final DMG,
+// so that they are shown before it mounts. Nothing is done when there is no license.
+func attachLicense(dmgPath string, resources udif.Resources) error {
+	if resources == nil {
+		return nil
+	}
+
+	attachLicenseSpinner := spinner.New(spinner.CharSets[14], 300*time.Millisecond)
+	attachLicenseSpinner.Suffix = " attaching license agreement..."
+	attachLicenseSpinner.FinalMSG = "✔ attaching license agreement...\n"
+	attachLicenseSpinner.Start()
+
+	err := udifProvider.SetResources(dmgPath, resources)
+	attachLicenseSpinner.Stop()
+	if err != nil {
+		return errors.Wrap(err, "error when attaching license agreement")
+	}
+	return nil
+}
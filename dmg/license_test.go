@@ -0,0 +1,106 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import (
+	"os"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/macos-dmg-creator/udif"
+)
+
+func Test_licenseResources(t *testing.T) {
+	testCases := []struct {
+		name              string
+		licenses          []License
+		mockFsOpsProvider *mockFsOpsProvider
+		wantTypes         []string
+		wantErr           error
+	}{
+		{
+			name:              "no license",
+			mockFsOpsProvider: &mockFsOpsProvider{},
+		},
+		{
+			name:     "plain text and RTF",
+			licenses: []License{{Language: "en", Path: "LICENSE.txt"}, {Language: "fr", Path: "LICENCE.rtf"}},
+			mockFsOpsProvider: &mockFsOpsProvider{fileContents: map[string][]byte{
+				"LICENSE.txt": []byte("MIT License"),
+				"LICENCE.rtf": []byte(`{\rtf1\ansi Licence MIT}`),
+			}},
+			wantTypes: []string{"LPic", "RTF ", "STR#", "TEXT"},
+		},
+		{
+			name:              "error reading license",
+			licenses:          []License{{Language: "en", Path: "LICENSE.txt"}},
+			mockFsOpsProvider: &mockFsOpsProvider{expectedReadFileErr: os.ErrNotExist},
+			wantErr:           errors.Wrap(os.ErrNotExist, "error when reading license [en]"),
+		},
+		{
+			name:              "language given twice",
+			licenses:          []License{{Language: "en", Path: "LICENSE.txt"}, {Language: "en", Path: "LICENSE.rtf"}},
+			mockFsOpsProvider: &mockFsOpsProvider{},
+			wantErr:           errors.New("error when encoding license agreement: license language [en] is given more than once"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fsOpsProvider = tc.mockFsOpsProvider
+			got, err := licenseResources(tc.licenses)
+			if tc.wantErr != nil {
+				require.EqualError(t, err, tc.wantErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			var gotTypes []string
+			for resourceType := range got {
+				gotTypes = append(gotTypes, resourceType)
+			}
+			require.ElementsMatch(t, tc.wantTypes, gotTypes)
+		})
+	}
+}
+
+func Test_attachLicense(t *testing.T) {
+	resources := udif.Resources{"TEXT": {{ID: 5000, Name: "English SLA", Data: []byte("MIT License")}}}
+	testCases := []struct {
+		name             string
+		resources        udif.Resources
+		mockUdifProvider *mockUdifProvider
+		want             map[string]udif.Resources
+		wantErr          error
+	}{
+		{
+			name:             "no license",
+			mockUdifProvider: &mockUdifProvider{},
+		},
+		{
+			name:             "license",
+			resources:        resources,
+			mockUdifProvider: &mockUdifProvider{},
+			want:             map[string]udif.Resources{"outputDir/MyApp.dmg": resources},
+		},
+		{
+			name:             "error",
+			resources:        resources,
+			mockUdifProvider: &mockUdifProvider{expectedSetResourcesErr: errors.New("some error")},
+			wantErr:          errors.New("error when attaching license agreement: some error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			udifProvider = tc.mockUdifProvider
+			err := attachLicense("outputDir/MyApp.dmg", tc.resources)
+			if tc.wantErr != nil {
+				require.EqualError(t, err, tc.wantErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, tc.mockUdifProvider.resources)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import (
+	"encoding/binary"
+	"maps"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// defaultDevelopmentRegion is the language the application is developed in when not given.
+	defaultDevelopmentRegion = "en"
+
+	// infoPlistStringsFile is the name of the file with the localized Info.plist values.
+	infoPlistStringsFile = "InfoPlist.strings"
+
+	// usageDescriptionPrefix and usageDescriptionSuffix delimit the
+	// Info.plist keys of the privacy usage descriptions,
+	// e.g. NSCameraUsageDescription.
+	usageDescriptionPrefix = "NS"
+	usageDescriptionSuffix = "UsageDescription"
+)
+
+// localizableNameKeys are the Info.plist keys of the application
+// name that can be localized, besides the privacy usage descriptions.
+var localizableNameKeys = []string{"CFBundleName", "CFBundleDisplayName"}
+
+// languagePattern matches the language identifiers used to name .lproj
+// directories, e.g. "en", "pt-BR", "zh-Hans" or "es_419".
+var languagePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(?:[-_][A-Za-z0-9]{2,8})*$`)
+
+// utf16LEByteOrderMark is written at the start of the strings files,
+// which Apple expects to be encoded in UTF-16.
+var utf16LEByteOrderMark = []byte{0xff, 0xfe}
+
+// Localization holds the localized Info.plist values of a language.
+type Localization struct {
+	// Language is the language identifier, e.g. "pt-BR", which names the .lproj directory.
+	Language string `validate:"required"`
+
+	// Strings maps the localized Info.plist keys, CFBundleName, CFBundleDisplayName
+	// and the privacy usage descriptions, e.g. NSCameraUsageDescription, to their values.
+	Strings map[string]string `validate:"required"`
+}
+
+// checkLocalizations checks that the usage descriptions and the localizations
+// declare only keys that can be localized, that every localized usage description
+// is declared in Info.plist and that no language is localized twice.
+func checkLocalizations(usageDescriptions map[string]string, localizations []Localization) error {
+	for key := range usageDescriptions {
+		if !isUsageDescriptionKey(key) {
+			return errors.Errorf("[%s] is not a privacy usage description key, e.g. NSCameraUsageDescription", key)
+		}
+	}
+	languages := map[string]bool{}
+	for _, localization := range localizations {
+		if !languagePattern.MatchString(localization.Language) {
+			return errors.Errorf("[%s] is not a language identifier, e.g. en or pt-BR", localization.Language)
+		}
+		if languages[localization.Language] {
+			return errors.Errorf("language [%s] is localized more than once", localization.Language)
+		}
+		languages[localization.Language] = true
+		for key := range localization.Strings {
+			switch {
+			case slices.Contains(localizableNameKeys, key):
+			case isUsageDescriptionKey(key):
+				if _, ok := usageDescriptions[key]; !ok {
+					return errors.Errorf("%s is localized for [%s] but has no usage description to localize", key, localization.Language)
+				}
+			default:
+				return errors.Errorf("%s cannot be localized for [%s]: only %s and privacy usage descriptions can", key, localization.Language, strings.Join(localizableNameKeys, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// isUsageDescriptionKey reports whether the Info.plist key is of a privacy usage description.
+func isUsageDescriptionKey(key string) bool {
+	return len(key) > len(usageDescriptionPrefix)+len(usageDescriptionSuffix) &&
+		strings.HasPrefix(key, usageDescriptionPrefix) &&
+		strings.HasSuffix(key, usageDescriptionSuffix)
+}
+
+// localizedLanguages returns the languages the application is localized
+// in, starting with the development region, for CFBundleLocalizations.
+func localizedLanguages(developmentRegion string, localizations []Localization) []string {
+	if len(localizations) == 0 {
+		return nil
+	}
+	languages := []string{developmentRegion}
+	for _, localization := range localizations {
+		if !slices.Contains(languages, localization.Language) {
+			languages = append(languages, localization.Language)
+		}
+	}
+	return languages
+}
+
+// hasLocalizedDisplayName reports whether any localization localizes the name of the application.
+func hasLocalizedDisplayName(localizations []Localization) bool {
+	for _, localization := range localizations {
+		for _, key := range localizableNameKeys {
+			if _, ok := localization.Strings[key]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// createInfoPlistStringsFiles creates the InfoPlist.strings file
+// of every localization in its .lproj directory.
+func createInfoPlistStringsFiles(localizations []Localization, appBundleDirPath string) error {
+	for _, localization := range localizations {
+		lprojDirPath := filepath.Join(appBundleDirPath, resourcesDir, localization.Language+".lproj")
+		if err := fsOpsProvider.MkdirAll(lprojDirPath, bundleDirMode); err != nil {
+			return errors.Wrapf(err, "error when creating directory [%s]", lprojDirPath)
+		}
+		stringsPath := filepath.Join(lprojDirPath, infoPlistStringsFile)
+		if err := fsOpsProvider.WriteFile(stringsPath, encodeStringsFile(localization.Strings), bundleDataMode); err != nil {
+			return errors.Wrapf(err, "error when writing %s file to [%s]", infoPlistStringsFile, stringsPath)
+		}
+	}
+	return nil
+}
+
+// encodeStringsFile encodes the strings, sorted by key, in the
+// strings file format, in UTF-16 little endian with a byte order mark.
+func encodeStringsFile(strs map[string]string) []byte {
+	var sb strings.Builder
+	for _, key := range slices.Sorted(maps.Keys(strs)) {
+		sb.WriteString(quoteString(key) + " = " + quoteString(strs[key]) + ";\n")
+	}
+	units := utf16.Encode([]rune(sb.String()))
+	data := make([]byte, len(utf16LEByteOrderMark), len(utf16LEByteOrderMark)+2*len(units))
+	copy(data, utf16LEByteOrderMark)
+	for _, unit := range units {
+		data = binary.LittleEndian.AppendUint16(data, unit)
+	}
+	return data
+}
+
+// quoteString quotes the string the way the strings file format expects.
+func quoteString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`).Replace(s) + `"`
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import (
+	"os"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_checkLocalizations(t *testing.T) {
+	testCases := []struct {
+		name              string
+		usageDescriptions map[string]string
+		localizations     []Localization
+		wantErr           error
+	}{
+		{
+			name:              "happy path",
+			usageDescriptions: map[string]string{"NSCameraUsageDescription": "Scans QR codes."},
+			localizations: []Localization{
+				{Language: "pt-BR", Strings: map[string]string{
+					"CFBundleName":             "Meu App",
+					"CFBundleDisplayName":      "Meu App",
+					"NSCameraUsageDescription": "Lê códigos QR.",
+				}},
+				{Language: "zh-Hans", Strings: map[string]string{"CFBundleDisplayName": "我的应用"}},
+			},
+		},
+		{
+			name:              "not a usage description key",
+			usageDescriptions: map[string]string{"CFBundleName": "My App"},
+			wantErr:           errors.New("[CFBundleName] is not a privacy usage description key, e.g. NSCameraUsageDescription"),
+		},
+		{
+			name: "invalid language",
+			localizations: []Localization{
+				{Language: "../en", Strings: map[string]string{"CFBundleName": "My App"}},
+			},
+			wantErr: errors.New("[../en] is not a language identifier, e.g. en or pt-BR"),
+		},
+		{
+			name: "language localized twice",
+			localizations: []Localization{
+				{Language: "fr", Strings: map[string]string{"CFBundleName": "Mon App"}},
+				{Language: "fr", Strings: map[string]string{"CFBundleDisplayName": "Mon App"}},
+			},
+			wantErr: errors.New("language [fr] is localized more than once"),
+		},
+		{
+			name: "usage description not declared",
+			localizations: []Localization{
+				{Language: "fr", Strings: map[string]string{"NSMicrophoneUsageDescription": "Enregistre des notes."}},
+			},
+			wantErr: errors.New("NSMicrophoneUsageDescription is localized for [fr] but has no usage description to localize"),
+		},
+		{
+			name: "key that cannot be localized",
+			localizations: []Localization{
+				{Language: "fr", Strings: map[string]string{"CFBundleIdentifier": "com.example.monapp"}},
+			},
+			wantErr: errors.New("CFBundleIdentifier cannot be localized for [fr]: only CFBundleName, CFBundleDisplayName and privacy usage descriptions can"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkLocalizations(tc.usageDescriptions, tc.localizations)
+			if err != nil {
+				if tc.wantErr == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				if tc.wantErr.Error() != err.Error() {
+					t.Fatalf(`expected error "%v", got "%v"`, tc.wantErr, err)
+				}
+				return
+			}
+			if tc.wantErr != nil {
+				t.Fatalf(`expected error "%v", got nil`, tc.wantErr)
+			}
+		})
+	}
+}
+
+func Test_localizedLanguages(t *testing.T) {
+	require.Nil(t, localizedLanguages("en", nil))
+	require.Equal(t, []string{"en", "pt-BR", "fr"}, localizedLanguages("en", []Localization{
+		{Language: "pt-BR"},
+		{Language: "en"},
+		{Language: "fr"},
+	}))
+}
+
+func Test_createInfoPlistStringsFiles(t *testing.T) {
+	testCases := []struct {
+		name              string
+		mockFsOpsProvider func() *mockFsOpsProvider
+		want              map[string][]byte
+		wantErr           error
+	}{
+		{
+			name: "happy path",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			want: map[string][]byte{
+				"MyApp.app/Contents/Resources/pt-BR.lproj/InfoPlist.strings": utf16LE("\"CFBundleName\" = \"Meu \\\"App\\\"\";\n\"NSCameraUsageDescription\" = \"Lê códigos QR.\\nSempre.\";\n"),
+				"MyApp.app/Contents/Resources/ja.lproj/InfoPlist.strings":    utf16LE("\"CFBundleDisplayName\" = \"マイアプリ 🚀\";\n"),
+			},
+		},
+		{
+			name: "error when creating directory",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					expectedMkdirAllErr: os.ErrPermission,
+				}
+			},
+			wantErr: errors.New("error when creating directory [MyApp.app/Contents/Resources/pt-BR.lproj]: permission denied"),
+		},
+		{
+			name: "error when writing file",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{
+					expectedWriteFileErr: os.ErrPermission,
+				}
+			},
+			wantErr: errors.New("error when writing InfoPlist.strings file to [MyApp.app/Contents/Resources/pt-BR.lproj/InfoPlist.strings]: permission denied"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockFsOpsProvider := tc.mockFsOpsProvider()
+			fsOpsProvider = mockFsOpsProvider
+
+			err := createInfoPlistStringsFiles([]Localization{
+				{Language: "pt-BR", Strings: map[string]string{
+					"NSCameraUsageDescription": "Lê códigos QR.\nSempre.",
+					"CFBundleName":             `Meu "App"`,
+				}},
+				{Language: "ja", Strings: map[string]string{"CFBundleDisplayName": "マイアプリ 🚀"}},
+			}, "MyApp.app")
+			if err != nil {
+				if tc.wantErr == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				if tc.wantErr.Error() != err.Error() {
+					t.Fatalf(`expected error "%v", got "%v"`, tc.wantErr, err)
+				}
+				return
+			}
+			if tc.wantErr != nil {
+				t.Fatalf(`expected error "%v", got nil`, tc.wantErr)
+			}
+			require.Equal(t, tc.want, mockFsOpsProvider.writtenFiles)
+		})
+	}
+}
+
+// utf16LE returns the string encoded in UTF-16 little endian with a byte order mark,
+// written out by hand so the test does not depend on the encoder under test.
+func utf16LE(s string) []byte {
+	data := []byte{0xff, 0xfe}
+	for _, r := range s {
+		if r >= 0x10000 {
+			r -= 0x10000
+			high, low := 0xd800+(r>>10), 0xdc00+(r&0x3ff)
+			data = append(data, byte(high), byte(high>>8), byte(low), byte(low>>8))
+			continue
+		}
+		data = append(data, byte(r), byte(r>>8))
+	}
+	return data
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import "github.com/tiagomelo/macos-dmg-creator/macho"
+
+// machoProvider is a variable that holds the function
+// that inspects and assembles Mach-O binaries.
+var machoProvider machoOps = defaultMacho{}
+
+// machoOps defines an interface for inspecting and assembling Mach-O binaries.
+type machoOps interface {
+	// Inspect inspects the Mach-O binary at the given path.
+	Inspect(path string) (*macho.Info, error)
+
+	// CreateUniversal merges the given binaries into a universal binary.
+	CreateUniversal(outputPath string, binaryPaths ...string) error
+
+	// ReadDylibs reads the dynamic library information of the Mach-O binary at the given path.
+	ReadDylibs(path string) (*macho.Dylibs, error)
+
+	// RewriteDylibs changes the dynamic library load commands of the Mach-O binary at the given path.
+	RewriteDylibs(path string, changes macho.DylibChanges) error
+}
+
+// defaultMacho is the default implementation of machoOps.
+type defaultMacho struct{}
+
+func (d defaultMacho) Inspect(path string) (*macho.Info, error) {
+	return macho.Inspect(path)
+}
+
+func (d defaultMacho) CreateUniversal(outputPath string, binaryPaths ...string) error {
+	return macho.CreateUniversal(outputPath, binaryPaths...)
+}
+
+func (d defaultMacho) ReadDylibs(path string) (*macho.Dylibs, error) {
+	return macho.ReadDylibs(path)
+}
+
+func (d defaultMacho) RewriteDylibs(path string, changes macho.DylibChanges) error {
+	return macho.RewriteDylibs(path, changes)
+}
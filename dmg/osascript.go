@@ -0,0 +1,24 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import "github.com/tiagomelo/macos-dmg-creator/osascript"
+
+// osascriptProvider is a variable that holds the function
+// that lays out Finder windows using osascript.
+var osascriptProvider osascriptOps = defaultOsascript{}
+
+// osascriptOps defines an interface for laying out Finder windows using osascript.
+type osascriptOps interface {
+	// ApplyLayout lays out the Finder window of a mounted disk.
+	ApplyLayout(w *osascript.Window) error
+}
+
+// defaultOsascript is the default implementation of osascriptOps.
+type defaultOsascript struct{}
+
+func (d defaultOsascript) ApplyLayout(w *osascript.Window) error {
+	return osascript.ApplyLayout(w)
+}
@@ -0,0 +1,24 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import "github.com/tiagomelo/macos-dmg-creator/script"
+
+// scriptProvider is a variable that holds the function
+// that inspects the scripts used as entry points.
+var scriptProvider scriptOps = defaultScript{}
+
+// scriptOps defines an interface for inspecting scripts.
+type scriptOps interface {
+	// Inspect checks that the file at the given path is a script macOS can run.
+	Inspect(path string) (*script.Info, error)
+}
+
+// defaultScript is the default implementation of scriptOps.
+type defaultScript struct{}
+
+func (d defaultScript) Inspect(path string) (*script.Info, error) {
+	return script.Inspect(path)
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/pkg/errors"
+	"github.com/tiagomelo/macos-dmg-creator/hdiutil"
+)
+
+// stagingDir is the directory of the temporary working directory
+// where the contents of the DMG are assembled when staging.
+const stagingDir = "staging"
+
+// createStagedDmg creates a DMG file with the given name without mounting it: the setup
+// function assembles its contents in a staging folder, named after the volume so that
+// the window layout refers to it like to a mounted DMG, which hdiutil then turns into
+// the final DMG in one step.
+func createStagedDmg(dmgName string, setup func(stagingDirPath string) error, options *imageOptions, tmpWorkDir, outputDir string) (*Result, error) {
+	stageDmgSpinner := spinner.New(spinner.CharSets[14], 300*time.Millisecond)
+	stageDmgSpinner.Suffix = " staging DMG contents..."
+	stageDmgSpinner.FinalMSG = "✔ staging DMG contents...\n"
+	stageDmgSpinner.Start()
+
+	stagingDirPath, err := stageDmgContents(dmgName, setup, tmpWorkDir)
+	stageDmgSpinner.Stop()
+	if err != nil {
+		return nil, errors.Wrap(err, "error when staging DMG contents")
+	}
+
+	return createDmgFromFolder(dmgName, stagingDirPath, options, outputDir)
+}
+
+// stageDmgContents creates the staging folder and has the setup function
+// assemble the contents of the DMG in it. It returns the path of the folder.
+func stageDmgContents(dmgName string, setup func(stagingDirPath string) error, tmpWorkDir string) (string, error) {
+	stagingDirPath := filepath.Join(tmpWorkDir, stagingDir, dmgName)
+	if err := fsOpsProvider.MkdirAll(stagingDirPath, os.ModePerm); err != nil {
+		return "", errors.Wrapf(err, "error when creating staging folder [%s]", stagingDirPath)
+	}
+	if err := setup(stagingDirPath); err != nil {
+		return "", err
+	}
+	return stagingDirPath, nil
+}
+
+// createDmgFromFolder creates the final DMG file from the staging folder, in the given
+// format, and reports its size compared to the contents and how long hdiutil took.
+func createDmgFromFolder(dmgName, stagingDirPath string, options *imageOptions, outputDir string) (*Result, error) {
+	contentsSize, err := fsOpsProvider.DirSize(stagingDirPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when reading the size of the staging folder")
+	}
+
+	createDMGSpinner := spinner.New(spinner.CharSets[14], 300*time.Millisecond)
+	createDMGSpinner.Suffix = fmt.Sprintf(" creating final %s DMG from staging folder...", options.format)
+	createDMGSpinner.FinalMSG = fmt.Sprintf("✔ creating final %s DMG from staging folder...\n", options.format)
+	createDMGSpinner.Start()
+
+	appDMGPath := filepath.Join(outputDir, fmt.Sprintf("%s.dmg", dmgName))
+
+	start := time.Now()
+	err = hdiutilProvider.CreateDMGFromFolder(stagingDirPath, options.filesystem, dmgName, options.layout, appDMGPath, &hdiutil.ConvertOptions{
+		Format:     options.format,
+		ZlibLevel:  options.zlibLevel,
+		Encryption: options.encryption,
+		Passphrase: options.passphrase,
+	})
+	duration := time.Since(start)
+	createDMGSpinner.Stop()
+	if err != nil {
+		return nil, errors.Wrap(err, "error when creating final DMG from staging folder")
+	}
+
+	size, err := fsOpsProvider.FileSize(appDMGPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when reading the size of the final DMG")
+	}
+	return &Result{
+		Path:               appDMGPath,
+		Format:             options.format,
+		Size:               size,
+		ContentsSize:       contentsSize,
+		ConversionDuration: duration,
+	}, nil
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/macos-dmg-creator/hdiutil"
+)
+
+func Test_createStagedDmg(t *testing.T) {
+	testCases := []struct {
+		name                string
+		options             *imageOptions
+		mockFsOpsProvider   func() *mockFsOpsProvider
+		mockHdiutilProvider func() *mockHdiutilProvider
+		want                *Result
+		wantCopiedPaths     []string
+		wantConvertOptions  *hdiutil.ConvertOptions
+		wantErr             error
+	}{
+		{
+			name:    "happy path",
+			options: &imageOptions{format: "UDZO", zlibLevel: 9, filesystem: "APFS", layout: "GPTSPUD"},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{fileSizes: map[string]int64{
+					"tmp/staging/testAppName":   41943040,
+					"outputDir/testAppName.dmg": 10485760,
+				}}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			want: &Result{
+				Path:         "outputDir/testAppName.dmg",
+				Format:       "UDZO",
+				Size:         10485760,
+				ContentsSize: 41943040,
+			},
+			wantCopiedPaths:    []string{"tmp/testAppName.app/ -> tmp/staging/testAppName"},
+			wantConvertOptions: &hdiutil.ConvertOptions{Format: "UDZO", ZlibLevel: 9},
+		},
+		{
+			name:    "encrypted",
+			options: &imageOptions{format: "ULFO", filesystem: "HFS+", layout: "SPUD", encryption: "AES-256", passphrase: []byte("secret")},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{fileSizes: map[string]int64{
+					"tmp/staging/testAppName":   41943040,
+					"outputDir/testAppName.dmg": 20971520,
+				}}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			want: &Result{
+				Path:         "outputDir/testAppName.dmg",
+				Format:       "ULFO",
+				Size:         20971520,
+				ContentsSize: 41943040,
+			},
+			wantCopiedPaths:    []string{"tmp/testAppName.app/ -> tmp/staging/testAppName"},
+			wantConvertOptions: &hdiutil.ConvertOptions{Format: "ULFO", Encryption: "AES-256", Passphrase: []byte("secret")},
+		},
+		{
+			name:    "error creating staging folder",
+			options: &imageOptions{format: "UDZO"},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{expectedMkdirAllErr: os.ErrPermission}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			wantErr: errors.Wrap(os.ErrPermission, "error when staging DMG contents: error when creating staging folder [tmp/staging/testAppName]"),
+		},
+		{
+			name:    "error copying app bundle",
+			options: &imageOptions{format: "UDZO"},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{expectedCopyDirErr: os.ErrPermission}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			wantErr: errors.Wrap(os.ErrPermission, "error when staging DMG contents: error when copying app bundle to mounted DMG template at [tmp/staging/testAppName]"),
+		},
+		{
+			name:    "error reading staging folder size",
+			options: &imageOptions{format: "UDZO"},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{expectedDirSizeErr: os.ErrPermission}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			wantErr: errors.Wrap(os.ErrPermission, "error when reading the size of the staging folder"),
+		},
+		{
+			name:    "error creating DMG",
+			options: &imageOptions{format: "UDZO"},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{expectedCreateDMGFromFolderErr: os.ErrPermission}
+			},
+			wantErr: errors.Wrap(os.ErrPermission, "error when creating final DMG from staging folder"),
+		},
+		{
+			name:    "error reading DMG size",
+			options: &imageOptions{format: "UDZO"},
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{expectedFileSizeErr: os.ErrPermission}
+			},
+			mockHdiutilProvider: func() *mockHdiutilProvider {
+				return &mockHdiutilProvider{}
+			},
+			wantErr: errors.Wrap(os.ErrPermission, "error when reading the size of the final DMG"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockFsOpsProvider := tc.mockFsOpsProvider()
+			fsOpsProvider = mockFsOpsProvider
+			mockHdiutilProvider := tc.mockHdiutilProvider()
+			hdiutilProvider = mockHdiutilProvider
+
+			got, err := createStagedDmg("testAppName", func(stagingDirPath string) error {
+				return setupDMGTemplate(stagingDirPath, "tmp/testAppName.app", &volumeAppearance{})
+			}, tc.options, "tmp", "outputDir")
+			if tc.wantErr != nil {
+				require.EqualError(t, err, tc.wantErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.GreaterOrEqual(t, got.ConversionDuration, time.Duration(0))
+			got.ConversionDuration = 0
+			require.Equal(t, tc.want, got)
+			require.Equal(t, tc.wantCopiedPaths, mockFsOpsProvider.copiedPaths)
+			require.Equal(t, "tmp/staging/testAppName", mockHdiutilProvider.srcFolder)
+			require.Equal(t, tc.options.filesystem, mockHdiutilProvider.filesystem)
+			require.Equal(t, tc.options.layout, mockHdiutilProvider.layout)
+			require.Equal(t, tc.wantConvertOptions, mockHdiutilProvider.convertOptions)
+		})
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"text/template"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/pkg/errors"
+)
+
+const (
+	// installPrefix is the directory the command-line tool is installed into.
+	installPrefix = "/usr/local"
+
+	// installScript and uninstallScript are the names of the scripts
+	// that install and remove the command-line tool.
+	installScript   = "Install.command"
+	uninstallScript = "Uninstall.command"
+
+	// payloadDir is the directory of the DMG holding the files
+	// to be installed, laid out as they are under installPrefix.
+	payloadDir = "payload"
+)
+
+// manPagePattern matches the extension of man pages, which is their
+// section, e.g. ".1" or ".3pm", capturing the number of the section.
+var manPagePattern = regexp.MustCompile(`^\.([1-9])[a-z]*$`)
+
+// toolFile is a file of the command-line tool to be installed.
+type toolFile struct {
+	// Source is the path to the file.
+	Source string
+
+	// Path is the path the file is installed to, relative to installPrefix.
+	Path string
+
+	// Mode is the mode the file is installed with.
+	Mode os.FileMode
+}
+
+// toolScriptData holds the values that are rendered into the install and uninstall scripts.
+type toolScriptData struct {
+	// Tool is the name of the command-line tool.
+	Tool string
+
+	// Dirs are the directories, under installPrefix, the files are installed to.
+	Dirs []string
+
+	// Files are the files to be installed.
+	Files []toolFile
+}
+
+// toolScriptFuncs are the functions used by the install and uninstall scripts.
+var toolScriptFuncs = template.FuncMap{
+	"sh": shellQuote,
+	"installed": func(path string) string {
+		return filepath.Join(installPrefix, path)
+	},
+	"mode": func(mode os.FileMode) string {
+		return fmt.Sprintf("%04o", mode.Perm())
+	},
+}
+
+// installScriptTpl is the template of the script that installs the command-line
+// tool. It is opened by double-clicking it in Finder, which runs it in Terminal,
+// and copies the files from the payload directory next to it with install(1).
+var installScriptTpl = template.Must(template.New(installScript).Funcs(toolScriptFuncs).Parse(`#!/bin/sh
+# Installs the command-line tool into ` + installPrefix + `.
+set -e
+payload="$(cd "$(dirname "$0")/` + payloadDir + `" && pwd)"
+echo {{sh (printf "Installing %s into ` + installPrefix + `; you may be asked for your password." .Tool)}}
+{{- range .Dirs}}
+sudo /usr/bin/install -d -m 0755 {{sh (installed .)}}
+{{- end}}
+{{- range .Files}}
+sudo /usr/bin/install -m {{mode .Mode}} "$payload/"{{sh .Path}} {{sh (installed .Path)}}
+{{- end}}
+echo {{sh (printf "%s has been installed." .Tool)}}
+`))
+
+// uninstallScriptTpl is the template of the script that removes
+// the files installed by the install script.
+var uninstallScriptTpl = template.Must(template.New(uninstallScript).Funcs(toolScriptFuncs).Parse(`#!/bin/sh
+# Removes the command-line tool from ` + installPrefix + `.
+set -e
+echo {{sh (printf "Removing %s from ` + installPrefix + `; you may be asked for your password." .Tool)}}
+{{- range .Files}}
+sudo /bin/rm -f {{sh (installed .Path)}}
+{{- end}}
+echo {{sh (printf "%s has been removed." .Tool)}}
+`))
+
+// createToolDmg creates the DMG file for the command-line tool, holding the install
+// and uninstall scripts and the payload directory with the files to be installed.
+func createToolDmg(params *CreateParams, appearance *volumeAppearance, options *imageOptions, tmpWorkDir string) (*Result, error) {
+	toolName, err := resolveExecutableName(params)
+	if err != nil {
+		return nil, err
+	}
+
+	// build or merge the tool binary, if needed, and make sure it runs on macOS.
+	toolBinaryPath, err := resolveAppBinary(params, tmpWorkDir)
+	if err != nil {
+		return nil, err
+	}
+	toolBinaryInfo, err := inspectAppBinary(toolBinaryPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when inspecting tool binary")
+	}
+	options.resolveFilesystem(toolBinaryInfo.MinimumOS)
+	appearance.filesystem = options.filesystem
+
+	files, err := toolFiles(params, toolName, toolBinaryPath)
+	if err != nil {
+		return nil, err
+	}
+
+	stageToolSpinner := spinner.New(spinner.CharSets[14], 300*time.Millisecond)
+	stageToolSpinner.Suffix = " staging command-line tool..."
+	stageToolSpinner.FinalMSG = "✔ staging command-line tool...\n"
+	stageToolSpinner.Start()
+
+	err = stageTool(toolName, files, tmpWorkDir)
+	stageToolSpinner.Stop()
+	if err != nil {
+		return nil, errors.Wrap(err, "error when staging command-line tool")
+	}
+
+	return createDmg(params.AppName, func(mountPoint string) error {
+		return setupToolDMGTemplate(mountPoint, tmpWorkDir, appearance)
+	}, options, tmpWorkDir, params.OutputDir)
+}
+
+// toolFiles returns the files of the command-line tool to be installed: the
+// binary, named after the tool, its man pages and its shell completions.
+func toolFiles(params *CreateParams, toolName, toolBinaryPath string) ([]toolFile, error) {
+	files := []toolFile{{Source: toolBinaryPath, Path: filepath.Join("bin", toolName), Mode: bundleDirMode}}
+	for _, manPage := range params.ManPages {
+		match := manPagePattern.FindStringSubmatch(filepath.Ext(manPage))
+		if match == nil {
+			return nil, errors.Errorf("[%s] is not a man page: its extension must be its section, e.g. .1", manPage)
+		}
+		files = append(files, toolFile{Source: manPage, Path: filepath.Join("share", "man", "man"+match[1], filepath.Base(manPage)), Mode: bundleDataMode})
+	}
+	if params.BashCompletion != "" {
+		files = append(files, toolFile{Source: params.BashCompletion, Path: filepath.Join("etc", "bash_completion.d", toolName), Mode: bundleDataMode})
+	}
+	if params.ZshCompletion != "" {
+		files = append(files, toolFile{Source: params.ZshCompletion, Path: filepath.Join("share", "zsh", "site-functions", "_"+toolName), Mode: bundleDataMode})
+	}
+	if params.FishCompletion != "" {
+		files = append(files, toolFile{Source: params.FishCompletion, Path: filepath.Join("share", "fish", "vendor_completions.d", toolName+".fish"), Mode: bundleDataMode})
+	}
+	installed := map[string]string{}
+	for _, file := range files {
+		if source, ok := installed[file.Path]; ok {
+			return nil, errors.Errorf("[%s] and [%s] are both installed to [%s]", source, file.Source, filepath.Join(installPrefix, file.Path))
+		}
+		installed[file.Path] = file.Source
+	}
+	return files, nil
+}
+
+// stageTool copies the files of the command-line tool to the payload directory
+// and writes the install and uninstall scripts next to it, in the temporary working directory.
+func stageTool(toolName string, files []toolFile, tmpWorkDir string) error {
+	for _, file := range files {
+		dstPath := filepath.Join(tmpWorkDir, payloadDir, file.Path)
+		if err := fsOpsProvider.MkdirAll(filepath.Dir(dstPath), bundleDirMode); err != nil {
+			return errors.Wrapf(err, "error when creating directory [%s]", filepath.Dir(dstPath))
+		}
+		if err := fsOpsProvider.CopyFile(file.Source, dstPath); err != nil {
+			return errors.Wrapf(err, "error when copying file [%s] to [%s]", file.Source, dstPath)
+		}
+	}
+	data := &toolScriptData{Tool: toolName, Files: files}
+	for _, file := range files {
+		if dir := filepath.Dir(file.Path); !slices.Contains(data.Dirs, dir) {
+			data.Dirs = append(data.Dirs, dir)
+		}
+	}
+	for _, tpl := range []*template.Template{installScriptTpl, uninstallScriptTpl} {
+		name := tpl.Name()
+		var script bytes.Buffer
+		if err := tpl.Execute(&script, data); err != nil {
+			return errors.Wrapf(err, "error when rendering %s", name)
+		}
+		scriptPath := filepath.Join(tmpWorkDir, name)
+		if err := fsOpsProvider.WriteFile(scriptPath, script.Bytes(), bundleDirMode); err != nil {
+			return errors.Wrapf(err, "error when writing %s to [%s]", name, scriptPath)
+		}
+	}
+	return nil
+}
+
+// setupToolDMGTemplate sets up the mounted DMG template with the install
+// and uninstall scripts and the payload directory, and applies its appearance.
+func setupToolDMGTemplate(mountedDmgTemplatePath, tmpWorkDir string, appearance *volumeAppearance) error {
+	for _, name := range []string{installScript, uninstallScript} {
+		scriptPath := filepath.Join(tmpWorkDir, name)
+		if err := fsOpsProvider.CopyFile(scriptPath, mountedDmgTemplatePath); err != nil {
+			return errors.Wrapf(err, "error when copying %s to mounted DMG template at [%s]", name, mountedDmgTemplatePath)
+		}
+	}
+	if err := fsOpsProvider.CopyDir(filepath.Join(tmpWorkDir, payloadDir), mountedDmgTemplatePath); err != nil {
+		return errors.Wrapf(err, "error when copying payload to mounted DMG template at [%s]", mountedDmgTemplatePath)
+	}
+	return applyAppearance(appearance, []string{installScript, uninstallScript, payloadDir}, mountedDmgTemplatePath)
+}
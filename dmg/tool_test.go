@@ -0,0 +1,231 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import (
+	"os"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_toolFiles(t *testing.T) {
+	testCases := []struct {
+		name    string
+		params  *CreateParams
+		want    []toolFile
+		wantErr error
+	}{
+		{
+			name: "happy path",
+			params: &CreateParams{
+				ManPages:       []string{"docs/mytool.1", "docs/mytool.conf.5"},
+				BashCompletion: "completions/mytool.bash",
+				ZshCompletion:  "completions/mytool.zsh",
+				FishCompletion: "completions/mytool.fish",
+			},
+			want: []toolFile{
+				{Source: "build/mytool", Path: "bin/mytool", Mode: 0o755},
+				{Source: "docs/mytool.1", Path: "share/man/man1/mytool.1", Mode: 0o644},
+				{Source: "docs/mytool.conf.5", Path: "share/man/man5/mytool.conf.5", Mode: 0o644},
+				{Source: "completions/mytool.bash", Path: "etc/bash_completion.d/mytool", Mode: 0o644},
+				{Source: "completions/mytool.zsh", Path: "share/zsh/site-functions/_mytool", Mode: 0o644},
+				{Source: "completions/mytool.fish", Path: "share/fish/vendor_completions.d/mytool.fish", Mode: 0o644},
+			},
+		},
+		{
+			name:   "binary only",
+			params: &CreateParams{},
+			want: []toolFile{
+				{Source: "build/mytool", Path: "bin/mytool", Mode: 0o755},
+			},
+		},
+		{
+			name:    "not a man page",
+			params:  &CreateParams{ManPages: []string{"docs/mytool.md"}},
+			wantErr: errors.New("[docs/mytool.md] is not a man page: its extension must be its section, e.g. .1"),
+		},
+		{
+			name:    "man pages installed to the same path",
+			params:  &CreateParams{ManPages: []string{"docs/mytool.1", "man/mytool.1"}},
+			wantErr: errors.New("[docs/mytool.1] and [man/mytool.1] are both installed to [/usr/local/share/man/man1/mytool.1]"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := toolFiles(tc.params, "mytool", "build/mytool")
+			if err != nil {
+				if tc.wantErr == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				if tc.wantErr.Error() != err.Error() {
+					t.Fatalf(`expected error "%v", got "%v"`, tc.wantErr, err)
+				}
+				return
+			}
+			if tc.wantErr != nil {
+				t.Fatalf(`expected error "%v", got nil`, tc.wantErr)
+			}
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func Test_stageTool(t *testing.T) {
+	testCases := []struct {
+		name              string
+		mockFsOpsProvider func() *mockFsOpsProvider
+		wantCopiedPaths   []string
+		wantInstall       string
+		wantUninstall     string
+		wantErr           error
+	}{
+		{
+			name: "happy path",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			wantCopiedPaths: []string{
+				"build/my tool -> tmp/payload/bin/my tool",
+				"docs/my tool.1 -> tmp/payload/share/man/man1/my tool.1",
+				"docs/it's.1 -> tmp/payload/share/man/man1/it's.1",
+			},
+			wantInstall: `#!/bin/sh
+# Installs the command-line tool into /usr/local.
+set -e
+payload="$(cd "$(dirname "$0")/payload" && pwd)"
+echo 'Installing my tool into /usr/local; you may be asked for your password.'
+sudo /usr/bin/install -d -m 0755 '/usr/local/bin'
+sudo /usr/bin/install -d -m 0755 '/usr/local/share/man/man1'
+sudo /usr/bin/install -m 0755 "$payload/"'bin/my tool' '/usr/local/bin/my tool'
+sudo /usr/bin/install -m 0644 "$payload/"'share/man/man1/my tool.1' '/usr/local/share/man/man1/my tool.1'
+sudo /usr/bin/install -m 0644 "$payload/"'share/man/man1/it'\''s.1' '/usr/local/share/man/man1/it'\''s.1'
+echo 'my tool has been installed.'
+`,
+			wantUninstall: `#!/bin/sh
+# Removes the command-line tool from /usr/local.
+set -e
+echo 'Removing my tool from /usr/local; you may be asked for your password.'
+sudo /bin/rm -f '/usr/local/bin/my tool'
+sudo /bin/rm -f '/usr/local/share/man/man1/my tool.1'
+sudo /bin/rm -f '/usr/local/share/man/man1/it'\''s.1'
+echo 'my tool has been removed.'
+`,
+		},
+		{
+			name: "error when creating directory",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{expectedMkdirAllErr: os.ErrPermission}
+			},
+			wantErr: errors.New("error when creating directory [tmp/payload/bin]: permission denied"),
+		},
+		{
+			name: "error when copying file",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{expectedCopyFileErr: os.ErrPermission}
+			},
+			wantErr: errors.New("error when copying file [build/my tool] to [tmp/payload/bin/my tool]: permission denied"),
+		},
+		{
+			name: "error when writing script",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{expectedWriteFileErr: os.ErrPermission}
+			},
+			wantErr: errors.New("error when writing Install.command to [tmp/Install.command]: permission denied"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockFsOpsProvider := tc.mockFsOpsProvider()
+			fsOpsProvider = mockFsOpsProvider
+
+			err := stageTool("my tool", []toolFile{
+				{Source: "build/my tool", Path: "bin/my tool", Mode: 0o755},
+				{Source: "docs/my tool.1", Path: "share/man/man1/my tool.1", Mode: 0o644},
+				{Source: "docs/it's.1", Path: "share/man/man1/it's.1", Mode: 0o644},
+			}, "tmp")
+			if err != nil {
+				if tc.wantErr == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				if tc.wantErr.Error() != err.Error() {
+					t.Fatalf(`expected error "%v", got "%v"`, tc.wantErr, err)
+				}
+				return
+			}
+			if tc.wantErr != nil {
+				t.Fatalf(`expected error "%v", got nil`, tc.wantErr)
+			}
+			require.Equal(t, tc.wantCopiedPaths, mockFsOpsProvider.copiedPaths)
+			require.Equal(t, tc.wantInstall, string(mockFsOpsProvider.writtenFiles["tmp/Install.command"]))
+			require.Equal(t, tc.wantUninstall, string(mockFsOpsProvider.writtenFiles["tmp/Uninstall.command"]))
+		})
+	}
+}
+
+func Test_setupToolDMGTemplate(t *testing.T) {
+	testCases := []struct {
+		name              string
+		mockFsOpsProvider func() *mockFsOpsProvider
+		wantCopiedPaths   []string
+		wantErr           error
+	}{
+		{
+			name: "happy path",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{}
+			},
+			wantCopiedPaths: []string{
+				"tmp/Install.command -> /Volumes/mytool",
+				"tmp/Uninstall.command -> /Volumes/mytool",
+				"tmp/payload/ -> /Volumes/mytool",
+			},
+		},
+		{
+			name: "error when copying script",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{expectedCopyFileErr: os.ErrPermission}
+			},
+			wantErr: errors.New("error when copying Install.command to mounted DMG template at [/Volumes/mytool]: permission denied"),
+		},
+		{
+			name: "error when copying payload",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{expectedCopyDirErr: os.ErrPermission}
+			},
+			wantErr: errors.New("error when copying payload to mounted DMG template at [/Volumes/mytool]: permission denied"),
+		},
+		{
+			name: "error when writing .DS_Store file",
+			mockFsOpsProvider: func() *mockFsOpsProvider {
+				return &mockFsOpsProvider{expectedWriteFileErr: os.ErrPermission}
+			},
+			wantErr: errors.New("error when laying out DMG window: error when writing .DS_Store file to [/Volumes/mytool/.DS_Store]: permission denied"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockFsOpsProvider := tc.mockFsOpsProvider()
+			fsOpsProvider = mockFsOpsProvider
+
+			err := setupToolDMGTemplate("/Volumes/mytool", "tmp", &volumeAppearance{})
+			if err != nil {
+				if tc.wantErr == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				if tc.wantErr.Error() != err.Error() {
+					t.Fatalf(`expected error "%v", got "%v"`, tc.wantErr, err)
+				}
+				return
+			}
+			if tc.wantErr != nil {
+				t.Fatalf(`expected error "%v", got nil`, tc.wantErr)
+			}
+			require.Equal(t, tc.wantCopiedPaths, mockFsOpsProvider.copiedPaths)
+			require.Contains(t, mockFsOpsProvider.writtenFiles, "/Volumes/mytool/.DS_Store")
+		})
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import "github.com/tiagomelo/macos-dmg-creator/udif"
+
+// udifProvider is a variable that holds the function
+// that writes the resources of UDIF disk images.
+var udifProvider udifOps = defaultUdif{}
+
+// udifOps defines an interface for writing the resources of UDIF disk images.
+type udifOps interface {
+	// SetResources writes the resources to the disk image, replacing those of the same types.
+	SetResources(dmgPath string, resources udif.Resources) error
+}
+
+// defaultUdif is the default implementation of udifOps.
+type defaultUdif struct{}
+
+func (d defaultUdif) SetResources(dmgPath string, resources udif.Resources) error {
+	return udif.SetResources(dmgPath, resources)
+}
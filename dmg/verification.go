@@ -0,0 +1,73 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/pkg/errors"
+	"github.com/tiagomelo/macos-dmg-creator/bundle"
+	"github.com/tiagomelo/macos-dmg-creator/verify"
+)
+
+// appExpectations returns the contents expected in the volume of the DMG of the
+// application bundle: the bundle, next to the Applications symlink, with the
+// Info.plist values it was packaged with.
+func appExpectations(appBundlePath string) (*verify.Expected, error) {
+	info, err := bundleProvider.Validate(appBundlePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when reading the expected Info.plist values")
+	}
+	return &verify.Expected{
+		AppBundleName: filepath.Base(appBundlePath),
+		InfoPlist:     expectedInfoPlist(info),
+	}, nil
+}
+
+// expectedInfoPlist returns the Info.plist values of the application
+// that must be found in the DMG, leaving out the ones it does not declare.
+func expectedInfoPlist(info *bundle.Info) map[string]string {
+	values := map[string]string{
+		"CFBundleExecutable":         info.Executable,
+		"CFBundleIdentifier":         info.Identifier,
+		"CFBundleShortVersionString": info.ShortVersion,
+		"CFBundleVersion":            info.Version,
+		"LSMinimumSystemVersion":     info.MinimumSystemVersion,
+	}
+	for key, value := range values {
+		if value == "" {
+			delete(values, key)
+		}
+	}
+	return values
+}
+
+// toolExpectations returns the contents expected in the volume of the DMG
+// of a command-line tool: the install and uninstall scripts and the payload.
+func toolExpectations() *verify.Expected {
+	return &verify.Expected{Files: []string{installScript, uninstallScript, payloadDir}}
+}
+
+// verifyDmg verifies the final DMG against the expected contents. Nothing
+// is done when no verification is requested, i.e. expected is nil.
+func verifyDmg(dmgPath string, expected *verify.Expected) error {
+	if expected == nil {
+		return nil
+	}
+
+	verifyDMGSpinner := spinner.New(spinner.CharSets[14], 300*time.Millisecond)
+	verifyDMGSpinner.Suffix = " verifying DMG..."
+	verifyDMGSpinner.FinalMSG = "✔ verifying DMG...\n"
+	verifyDMGSpinner.Start()
+
+	err := verifyProvider.DMG(dmgPath, expected)
+	verifyDMGSpinner.Stop()
+	if err != nil {
+		return errors.Wrap(err, "error when verifying DMG")
+	}
+	return nil
+}
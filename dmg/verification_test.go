@@ -0,0 +1,112 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import (
+	"os"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/macos-dmg-creator/bundle"
+	"github.com/tiagomelo/macos-dmg-creator/verify"
+)
+
+func Test_appExpectations(t *testing.T) {
+	testCases := []struct {
+		name               string
+		mockBundleProvider func() *mockBundleProvider
+		want               *verify.Expected
+		wantErr            error
+	}{
+		{
+			name: "happy path",
+			mockBundleProvider: func() *mockBundleProvider {
+				return &mockBundleProvider{expectedInfo: &bundle.Info{
+					Executable:           "MyApp",
+					Identifier:           "com.example.myapp",
+					ShortVersion:         "1.2.0",
+					MinimumSystemVersion: "11.0",
+				}}
+			},
+			want: &verify.Expected{
+				AppBundleName: "MyApp.app",
+				InfoPlist: map[string]string{
+					"CFBundleExecutable":         "MyApp",
+					"CFBundleIdentifier":         "com.example.myapp",
+					"CFBundleShortVersionString": "1.2.0",
+					"LSMinimumSystemVersion":     "11.0",
+				},
+			},
+		},
+		{
+			name: "error",
+			mockBundleProvider: func() *mockBundleProvider {
+				return &mockBundleProvider{expectedValidateErr: os.ErrNotExist}
+			},
+			wantErr: errors.Wrap(os.ErrNotExist, "error when reading the expected Info.plist values"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bundleProvider = tc.mockBundleProvider()
+			got, err := appExpectations("tmp/MyApp.app")
+			if tc.wantErr != nil {
+				require.EqualError(t, err, tc.wantErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func Test_verifyDmg(t *testing.T) {
+	testCases := []struct {
+		name               string
+		expected           *verify.Expected
+		mockVerifyProvider func() *mockVerifyProvider
+		wantVerifiedPath   string
+		wantErr            error
+	}{
+		{
+			name:     "happy path",
+			expected: toolExpectations(),
+			mockVerifyProvider: func() *mockVerifyProvider {
+				return &mockVerifyProvider{}
+			},
+			wantVerifiedPath: "outputDir/mytool.dmg",
+		},
+		{
+			name: "not requested",
+			mockVerifyProvider: func() *mockVerifyProvider {
+				return &mockVerifyProvider{expectedErr: os.ErrPermission}
+			},
+		},
+		{
+			name:     "error",
+			expected: toolExpectations(),
+			mockVerifyProvider: func() *mockVerifyProvider {
+				return &mockVerifyProvider{expectedErr: os.ErrPermission}
+			},
+			wantVerifiedPath: "outputDir/mytool.dmg",
+			wantErr:          errors.Wrap(os.ErrPermission, "error when verifying DMG"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockVerifyProvider := tc.mockVerifyProvider()
+			verifyProvider = mockVerifyProvider
+			err := verifyDmg("outputDir/mytool.dmg", tc.expected)
+			require.Equal(t, tc.wantVerifiedPath, mockVerifyProvider.verifiedPath)
+			require.Equal(t, tc.expected, mockVerifyProvider.expected)
+			if tc.wantErr != nil {
+				require.EqualError(t, err, tc.wantErr.Error())
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import "github.com/tiagomelo/macos-dmg-creator/verify"
+
+// verifyProvider is a variable that holds the function
+// that verifies created disk images.
+var verifyProvider verifyOps = defaultVerify{}
+
+// verifyOps defines an interface for verifying created disk images.
+type verifyOps interface {
+	// DMG verifies the checksums of the disk image and checks that its volume
+	// holds the expected contents, returning a *verify.VerificationError if not.
+	DMG(path string, expected *verify.Expected) error
+}
+
+// defaultVerify is the default implementation of verifyOps.
+type defaultVerify struct{}
+
+func (d defaultVerify) DMG(path string, expected *verify.Expected) error {
+	return verify.DMG(path, expected)
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import (
+	"cmp"
+	"path/filepath"
+	"slices"
+
+	"github.com/pkg/errors"
+	"github.com/tiagomelo/macos-dmg-creator/dsstore"
+	"github.com/tiagomelo/macos-dmg-creator/osascript"
+)
+
+const (
+	dsStoreFile = ".DS_Store"
+
+	applicationsSymlinkName = "Applications"
+)
+
+const (
+	defaultWindowWidth  = 640
+	defaultWindowHeight = 400
+	defaultIconSize     = 128
+	defaultTextSize     = 12
+)
+
+// WindowLayout is the layout of the Finder window the DMG opens in. The window shows
+// the items of the DMG as icons, without toolbar, sidebar nor status bar.
+type WindowLayout struct {
+	// X and Y are the position of the window on the screen.
+	X int `validate:"min=0"`
+	Y int `validate:"min=0"`
+
+	// Width and Height are the size of the window. Default to the size of
+	// the background image, if any, or to 640x400.
+	Width  int `validate:"omitempty,min=100"`
+	Height int `validate:"omitempty,min=100"`
+
+	// IconSize is the size of the icons, from 16 to 512 points. Defaults to 128.
+	IconSize int `validate:"omitempty,min=16,max=512"`
+
+	// TextSize is the size of the icon labels, from 10 to 16 points. Defaults to 12.
+	TextSize int `validate:"omitempty,min=10,max=16"`
+
+	// IconPositions are the positions of the centers of the icons, by item name,
+	// e.g. "MyApp.app" or "Applications". The items left out are spread in a row
+	// across the middle of the window.
+	IconPositions map[string]dsstore.Point
+
+	// UseFinder indicates whether the window is laid out by Finder, scripted with
+	// osascript, instead of by writing the .DS_Store file directly. It needs a logged-in
+	// session where Finder runs and the permission to control it.
+	UseFinder bool
+}
+
+// newWindow returns the window showing the items, laid out as requested.
+func newWindow(layout *WindowLayout, items []string) (*dsstore.Window, error) {
+	window := &dsstore.Window{
+		X:         layout.X,
+		Y:         layout.Y,
+		Width:     cmp.Or(layout.Width, defaultWindowWidth),
+		Height:    cmp.Or(layout.Height, defaultWindowHeight),
+		IconSize:  cmp.Or(layout.IconSize, defaultIconSize),
+		TextSize:  cmp.Or(layout.TextSize, defaultTextSize),
+		Positions: map[string]dsstore.Point{},
+	}
+	for i, item := range items {
+		window.Positions[item] = dsstore.Point{
+			X: window.Width * (2*i + 1) / (2 * len(items)),
+			Y: window.Height / 2,
+		}
+	}
+	for name, position := range layout.IconPositions {
+		if !slices.Contains(items, name) {
+			return nil, errors.Errorf("no item named [%s] in the DMG; the items are %v", name, items)
+		}
+		window.Positions[name] = position
+	}
+	return window, nil
+}
+
+// layoutWindow lays out the Finder window of the mounted DMG template, of the given
+// filesystem, over the background image if one is given, by writing its .DS_Store
+// file or, if requested, by scripting Finder.
+func layoutWindow(layout *WindowLayout, background *backgroundImage, filesystem string, items []string, mountedDmgTemplatePath string) error {
+	if layout == nil {
+		layout = &WindowLayout{}
+	}
+	if background != nil {
+		sized := *layout
+		sized.Width = cmp.Or(sized.Width, background.width)
+		sized.Height = cmp.Or(sized.Height, background.height)
+		layout = &sized
+	}
+	window, err := newWindow(layout, items)
+	if err != nil {
+		return err
+	}
+	if layout.UseFinder {
+		return applyFinderLayout(window, background, mountedDmgTemplatePath)
+	}
+	return createDSStoreFile(window, background, filesystem, mountedDmgTemplatePath)
+}
+
+// createDSStoreFile writes the .DS_Store file that lays out the window.
+func createDSStoreFile(window *dsstore.Window, background *backgroundImage, filesystem, mountedDmgTemplatePath string) error {
+	if background != nil {
+		var err error
+		window.Background, err = backgroundAlias(background, filesystem, mountedDmgTemplatePath)
+		if err != nil {
+			return err
+		}
+	}
+	records, err := window.Records()
+	if err != nil {
+		return errors.Wrap(err, "error when laying out window")
+	}
+	data, err := dsstore.Encode(records)
+	if err != nil {
+		return errors.Wrap(err, "error when encoding .DS_Store file")
+	}
+	dsStorePath := filepath.Join(mountedDmgTemplatePath, dsStoreFile)
+	if err := fsOpsProvider.WriteFile(dsStorePath, data, bundleDataMode); err != nil {
+		return errors.Wrapf(err, "error when writing .DS_Store file to [%s]", dsStorePath)
+	}
+	return nil
+}
+
+// applyFinderLayout has Finder lay out the window with AppleScript,
+// which makes Finder write the .DS_Store file itself.
+func applyFinderLayout(window *dsstore.Window, background *backgroundImage, mountedDmgTemplatePath string) error {
+	finderWindow := &osascript.Window{
+		VolumeName: filepath.Base(mountedDmgTemplatePath),
+		X:          window.X,
+		Y:          window.Y,
+		Width:      window.Width,
+		Height:     window.Height,
+		IconSize:   window.IconSize,
+		TextSize:   window.TextSize,
+	}
+	for name, position := range window.Positions {
+		finderWindow.Icons = append(finderWindow.Icons, osascript.Icon{Name: name, X: position.X, Y: position.Y})
+	}
+	if background != nil {
+		finderWindow.BackgroundPath = backgroundDir + "/" + background.fileName
+	}
+	if err := osascriptProvider.ApplyLayout(finderWindow); err != nil {
+		return errors.Wrap(err, "error when laying out window with Finder")
+	}
+	return nil
+}
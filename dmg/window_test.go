@@ -0,0 +1,129 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/macos-dmg-creator/dsstore"
+	"github.com/tiagomelo/macos-dmg-creator/osascript"
+)
+
+func Test_newWindow(t *testing.T) {
+	testCases := []struct {
+		name    string
+		layout  *WindowLayout
+		want    *dsstore.Window
+		wantErr error
+	}{
+		{
+			name:   "defaults",
+			layout: &WindowLayout{},
+			want: &dsstore.Window{
+				Width: 640, Height: 400, IconSize: 128, TextSize: 12,
+				Positions: map[string]dsstore.Point{
+					"MyApp.app":    {X: 160, Y: 200},
+					"Applications": {X: 480, Y: 200},
+				},
+			},
+		},
+		{
+			name: "custom layout",
+			layout: &WindowLayout{
+				X: 200, Y: 100, Width: 800, Height: 500, IconSize: 96, TextSize: 14,
+				IconPositions: map[string]dsstore.Point{"Applications": {X: 600, Y: 300}},
+			},
+			want: &dsstore.Window{
+				X: 200, Y: 100, Width: 800, Height: 500, IconSize: 96, TextSize: 14,
+				Positions: map[string]dsstore.Point{
+					"MyApp.app":    {X: 200, Y: 250},
+					"Applications": {X: 600, Y: 300},
+				},
+			},
+		},
+		{
+			name: "unknown item",
+			layout: &WindowLayout{
+				IconPositions: map[string]dsstore.Point{"README": {X: 100, Y: 100}},
+			},
+			wantErr: errors.New("no item named [README] in the DMG; the items are [MyApp.app Applications]"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := newWindow(tc.layout, []string{"MyApp.app", "Applications"})
+			if err != nil {
+				if tc.wantErr == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				if tc.wantErr.Error() != err.Error() {
+					t.Fatalf(`expected error "%v", got "%v"`, tc.wantErr, err)
+				}
+				return
+			}
+			if tc.wantErr != nil {
+				t.Fatalf(`expected error "%v", got nil`, tc.wantErr)
+			}
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func Test_layoutWindowWithFinder(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		background            *backgroundImage
+		mockOsascriptProvider *mockOsascriptProvider
+		want                  *osascript.Window
+		wantErr               error
+	}{
+		{
+			name:                  "happy path",
+			mockOsascriptProvider: &mockOsascriptProvider{},
+			want: &osascript.Window{
+				VolumeName: "MyApp", X: 10, Y: 20, Width: 640, Height: 400, IconSize: 128, TextSize: 12,
+				Icons: []osascript.Icon{{Name: "Applications", X: 480, Y: 200}, {Name: "MyApp.app", X: 160, Y: 200}},
+			},
+		},
+		{
+			name:                  "background image",
+			background:            &backgroundImage{dirPath: "tmp/.background", fileName: "background.tiff", width: 660, height: 420},
+			mockOsascriptProvider: &mockOsascriptProvider{},
+			want: &osascript.Window{
+				VolumeName: "MyApp", X: 10, Y: 20, Width: 660, Height: 420, IconSize: 128, TextSize: 12,
+				Icons:          []osascript.Icon{{Name: "Applications", X: 495, Y: 210}, {Name: "MyApp.app", X: 165, Y: 210}},
+				BackgroundPath: ".background/background.tiff",
+			},
+		},
+		{
+			name:                  "error",
+			mockOsascriptProvider: &mockOsascriptProvider{expectedApplyLayoutErr: errors.New("some error")},
+			wantErr:               errors.New("error when laying out window with Finder: some error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockFsOpsProvider := &mockFsOpsProvider{}
+			fsOpsProvider = mockFsOpsProvider
+			osascriptProvider = tc.mockOsascriptProvider
+
+			err := layoutWindow(&WindowLayout{X: 10, Y: 20, UseFinder: true}, tc.background, "APFS", []string{"MyApp.app", "Applications"}, "/Volumes/MyApp")
+			if tc.wantErr != nil {
+				require.EqualError(t, err, tc.wantErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			slices.SortFunc(tc.mockOsascriptProvider.window.Icons, func(a, b osascript.Icon) int {
+				return strings.Compare(a.Name, b.Name)
+			})
+			require.Equal(t, tc.want, tc.mockOsascriptProvider.window)
+			require.Empty(t, mockFsOpsProvider.writtenFiles, "the .DS_Store file is written by Finder")
+		})
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dmg
+
+import "github.com/tiagomelo/macos-dmg-creator/xattr"
+
+// xattrProvider is a variable that holds the function
+// that sets the Finder flags of files and directories.
+var xattrProvider xattrOps = defaultXattr{}
+
+// xattrOps defines an interface for setting the Finder flags of files and directories.
+type xattrOps interface {
+	// SetCustomIcon sets the custom icon Finder flag of the file or directory.
+	SetCustomIcon(path string) error
+}
+
+// defaultXattr is the default implementation of xattrOps.
+type defaultXattr struct{}
+
+func (d defaultXattr) SetCustomIcon(path string) error {
+	return xattr.SetCustomIcon(path)
+}
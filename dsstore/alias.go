@@ -0,0 +1,159 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dsstore
+
+import (
+	"cmp"
+	"encoding/binary"
+	"path"
+	"unicode/utf16"
+
+	"github.com/pkg/errors"
+	"golang.org/x/text/encoding/charmap"
+)
+
+const (
+	// aliasVersion is the version of the alias records written by Encode.
+	aliasVersion = 2
+
+	// aliasHeaderSize is the size of the fixed part of an alias record.
+	aliasHeaderSize = 150
+
+	// aliasDiskTypeEjectable identifies volumes on removable media, such as disk images.
+	aliasDiskTypeEjectable = 5
+
+	// aliasVolumeAttributes are the volume attributes Finder writes for disk images.
+	aliasVolumeAttributes = 0x00000d02
+
+	// maxVolumeNameLength and maxFileNameLength are the lengths of
+	// the fixed-size fields holding the names in the Mac OS Roman encoding.
+	maxVolumeNameLength = 27
+	maxFileNameLength   = 63
+
+	// defaultVolumeFilesystem is the filesystem of the volume when none is given.
+	defaultVolumeFilesystem = "HFS+"
+)
+
+// volumeSignatures are the signatures alias records identify the filesystems of volumes with, by
+// the name hdiutil gives the filesystem. Case-sensitive HFS+ is HFSX, which has its own signature.
+var volumeSignatures = map[string]string{
+	"APFS":                          "KB",
+	"Case-sensitive APFS":           "KB",
+	"HFS+":                          "H+",
+	"Journaled HFS+":                "H+",
+	"Case-sensitive HFS+":           "HX",
+	"Case-sensitive Journaled HFS+": "HX",
+}
+
+// tags of the variable-length fields that follow the fixed part of an alias record.
+const (
+	aliasTagParentName        = 0
+	aliasTagParentID          = 1
+	aliasTagUnicodeFileName   = 14
+	aliasTagUnicodeVolumeName = 15
+	aliasTagPOSIXPath         = 18
+	aliasTagVolumePOSIXPath   = 19
+	aliasTagEnd               = 0xffff
+)
+
+// Alias is a classic Mac OS alias record pointing to a file on a volume, which is how
+// Finder refers to the background image of a window. It records the name of the volume,
+// the path of the file and the identifiers of the file and of its directory.
+type Alias struct {
+	// VolumeName is the name of the volume the file is on.
+	VolumeName string
+
+	// Filesystem is the filesystem of the volume, as named by hdiutil, e.g. APFS
+	// or Journaled HFS+. It defaults to HFS+.
+	Filesystem string
+
+	// Path is the path of the file, relative to the root of the volume,
+	// e.g. ".background/background.png".
+	Path string
+
+	// ParentID and FileID are the catalog node identifiers (inode numbers)
+	// of the directory of the file and of the file.
+	ParentID, FileID uint32
+}
+
+// Encode encodes the alias record.
+func (a *Alias) Encode() ([]byte, error) {
+	if a.VolumeName == "" {
+		return nil, errors.New("the volume name is empty")
+	}
+	dir, fileName := path.Split(path.Clean("/" + a.Path))
+	if fileName == "" {
+		return nil, errors.Errorf("[%s] is not the path of a file", a.Path)
+	}
+	filesystem := cmp.Or(a.Filesystem, defaultVolumeFilesystem)
+	signature, ok := volumeSignatures[filesystem]
+	if !ok {
+		return nil, errors.Errorf("unknown filesystem %s", filesystem)
+	}
+
+	data := make([]byte, aliasHeaderSize)
+	binary.BigEndian.PutUint16(data[6:8], aliasVersion)
+	putPascalString(data[10:38], a.VolumeName, maxVolumeNameLength)
+	copy(data[42:44], signature)
+	binary.BigEndian.PutUint16(data[44:46], aliasDiskTypeEjectable)
+	binary.BigEndian.PutUint32(data[46:50], a.ParentID)
+	putPascalString(data[50:114], fileName, maxFileNameLength)
+	binary.BigEndian.PutUint32(data[114:118], a.FileID)
+	// the levels from and to the target are unknown.
+	binary.BigEndian.PutUint16(data[130:132], 0xffff)
+	binary.BigEndian.PutUint16(data[132:134], 0xffff)
+	binary.BigEndian.PutUint32(data[134:138], aliasVolumeAttributes)
+
+	appendField := func(tag uint16, value []byte) {
+		data = binary.BigEndian.AppendUint16(data, tag)
+		data = binary.BigEndian.AppendUint16(data, uint16(len(value)))
+		data = append(data, value...)
+		if len(value)%2 != 0 {
+			data = append(data, 0)
+		}
+	}
+	if parentName := path.Base(dir); parentName != "/" {
+		appendField(aliasTagParentName, []byte(parentName))
+	}
+	appendField(aliasTagParentID, binary.BigEndian.AppendUint32(nil, a.ParentID))
+	appendField(aliasTagUnicodeFileName, unicodeString(fileName))
+	appendField(aliasTagUnicodeVolumeName, unicodeString(a.VolumeName))
+	appendField(aliasTagPOSIXPath, []byte(dir+fileName))
+	appendField(aliasTagVolumePOSIXPath, []byte("/Volumes/"+a.VolumeName))
+	data = binary.BigEndian.AppendUint16(data, aliasTagEnd)
+	data = binary.BigEndian.AppendUint16(data, 0)
+
+	binary.BigEndian.PutUint16(data[4:6], uint16(len(data)))
+	return data, nil
+}
+
+// putPascalString writes the string in the Mac OS Roman encoding, prefixed by its length and
+// truncated to the given length, replacing the characters it cannot represent with question
+// marks; the full names are stored in the variable-length fields in Unicode.
+func putPascalString(field []byte, s string, maxLength int) {
+	encoded := make([]byte, 0, maxLength)
+	for _, r := range s {
+		if len(encoded) == maxLength {
+			break
+		}
+		b, ok := charmap.Macintosh.EncodeRune(r)
+		if !ok {
+			b = '?'
+		}
+		encoded = append(encoded, b)
+	}
+	field[0] = byte(len(encoded))
+	copy(field[1:], encoded)
+}
+
+// unicodeString encodes the string in UTF-16 big endian, prefixed by its length in code units.
+func unicodeString(s string) []byte {
+	units := utf16.Encode([]rune(s))
+	data := binary.BigEndian.AppendUint16(nil, uint16(len(units)))
+	for _, unit := range units {
+		data = binary.BigEndian.AppendUint16(data, unit)
+	}
+	return data
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dsstore
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAliasEncode(t *testing.T) {
+	testCases := []struct {
+		name               string
+		alias              *Alias
+		expectedSignature  string
+		expectedVolumeName string
+		expectedFields     map[uint16][]byte
+		expectedError      string
+	}{
+		{
+			name: "happy path",
+			alias: &Alias{
+				VolumeName: "MyApp",
+				Path:       ".background/background.png",
+				ParentID:   17,
+				FileID:     18,
+			},
+			expectedFields: map[uint16][]byte{
+				aliasTagParentName:        []byte(".background"),
+				aliasTagParentID:          {0, 0, 0, 17},
+				aliasTagUnicodeFileName:   append([]byte{0, 14}, utf16BE("background.png")...),
+				aliasTagUnicodeVolumeName: append([]byte{0, 5}, utf16BE("MyApp")...),
+				aliasTagPOSIXPath:         []byte("/.background/background.png"),
+				aliasTagVolumePOSIXPath:   []byte("/Volumes/MyApp"),
+			},
+		},
+		{
+			name: "long volume name",
+			alias: &Alias{
+				VolumeName: strings.Repeat("v", 30),
+				Path:       "background.png",
+				ParentID:   2,
+				FileID:     18,
+			},
+			expectedFields: map[uint16][]byte{
+				aliasTagParentID:          {0, 0, 0, 2},
+				aliasTagUnicodeFileName:   append([]byte{0, 14}, utf16BE("background.png")...),
+				aliasTagUnicodeVolumeName: append([]byte{0, 30}, utf16BE(strings.Repeat("v", 30))...),
+				aliasTagPOSIXPath:         []byte("/background.png"),
+				aliasTagVolumePOSIXPath:   []byte("/Volumes/" + strings.Repeat("v", 30)),
+			},
+		},
+		{
+			name: "non-ASCII volume name",
+			alias: &Alias{
+				VolumeName: "✓ Résumé Générateur d'Été 2025",
+				Path:       "background.png",
+				ParentID:   2,
+				FileID:     18,
+			},
+			expectedVolumeName: "? R\x8esum\x8e G\x8en\x8erateur d'\x83t\x8e 2",
+			expectedFields: map[uint16][]byte{
+				aliasTagParentID:          {0, 0, 0, 2},
+				aliasTagUnicodeFileName:   append([]byte{0, 14}, utf16BE("background.png")...),
+				aliasTagUnicodeVolumeName: append([]byte{0, 30}, utf16BE("✓ Résumé Générateur d'Été 2025")...),
+				aliasTagPOSIXPath:         []byte("/background.png"),
+				aliasTagVolumePOSIXPath:   []byte("/Volumes/✓ Résumé Générateur d'Été 2025"),
+			},
+		},
+		{
+			name: "APFS volume",
+			alias: &Alias{
+				VolumeName: "MyApp",
+				Filesystem: "APFS",
+				Path:       "background.png",
+				ParentID:   2,
+				FileID:     18,
+			},
+			expectedSignature: "KB",
+			expectedFields: map[uint16][]byte{
+				aliasTagParentID:          {0, 0, 0, 2},
+				aliasTagUnicodeFileName:   append([]byte{0, 14}, utf16BE("background.png")...),
+				aliasTagUnicodeVolumeName: append([]byte{0, 5}, utf16BE("MyApp")...),
+				aliasTagPOSIXPath:         []byte("/background.png"),
+				aliasTagVolumePOSIXPath:   []byte("/Volumes/MyApp"),
+			},
+		},
+		{
+			name: "case-sensitive HFS+ volume",
+			alias: &Alias{
+				VolumeName: "MyApp",
+				Filesystem: "Case-sensitive Journaled HFS+",
+				Path:       "background.png",
+				ParentID:   2,
+				FileID:     18,
+			},
+			expectedSignature: "HX",
+			expectedFields: map[uint16][]byte{
+				aliasTagParentID:          {0, 0, 0, 2},
+				aliasTagUnicodeFileName:   append([]byte{0, 14}, utf16BE("background.png")...),
+				aliasTagUnicodeVolumeName: append([]byte{0, 5}, utf16BE("MyApp")...),
+				aliasTagPOSIXPath:         []byte("/background.png"),
+				aliasTagVolumePOSIXPath:   []byte("/Volumes/MyApp"),
+			},
+		},
+		{
+			name:          "unknown filesystem",
+			alias:         &Alias{VolumeName: "MyApp", Filesystem: "ExFAT", Path: "background.png"},
+			expectedError: "unknown filesystem ExFAT",
+		},
+		{
+			name:          "empty volume name",
+			alias:         &Alias{Path: "background.png"},
+			expectedError: "the volume name is empty",
+		},
+		{
+			name:          "not a file",
+			alias:         &Alias{VolumeName: "MyApp", Path: "/"},
+			expectedError: "[/] is not the path of a file",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := tc.alias.Encode()
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, len(data), int(binary.BigEndian.Uint16(data[4:6])))
+			require.Equal(t, uint16(2), binary.BigEndian.Uint16(data[6:8]))
+			expectedVolumeName := cmp.Or(tc.expectedVolumeName, tc.alias.VolumeName[:min(len(tc.alias.VolumeName), maxVolumeNameLength)])
+			require.Equal(t, expectedVolumeName, string(data[11:11+data[10]]))
+			require.Equal(t, "background.png", string(data[51:51+data[50]]))
+			require.Equal(t, cmp.Or(tc.expectedSignature, "H+"), string(data[42:44]))
+			require.Equal(t, tc.alias.ParentID, binary.BigEndian.Uint32(data[46:50]))
+			require.Equal(t, tc.alias.FileID, binary.BigEndian.Uint32(data[114:118]))
+			require.Equal(t, tc.expectedFields, aliasFields(t, data))
+		})
+	}
+}
+
+// aliasFields returns the variable-length fields of the alias record, by tag.
+func aliasFields(t *testing.T, data []byte) map[uint16][]byte {
+	t.Helper()
+	fields := map[uint16][]byte{}
+	r := bytes.NewReader(data[aliasHeaderSize:])
+	for {
+		var header struct{ Tag, Length uint16 }
+		require.NoError(t, binary.Read(r, binary.BigEndian, &header))
+		if header.Tag == aliasTagEnd {
+			require.Zero(t, r.Len())
+			return fields
+		}
+		value := make([]byte, header.Length+header.Length%2)
+		_, err := r.Read(value)
+		require.NoError(t, err)
+		fields[header.Tag] = value[:header.Length]
+	}
+}
+
+func utf16BE(s string) []byte {
+	var data []byte
+	for _, r := range s {
+		data = binary.BigEndian.AppendUint16(data, uint16(r))
+	}
+	return data
+}
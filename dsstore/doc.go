@@ -0,0 +1,4 @@
+// Package dsstore provides functionality to encode and decode the .DS_Store
+// files in which Finder keeps the view settings of a directory, such as the
+// layout of its window and the positions of its icons.
+package dsstore
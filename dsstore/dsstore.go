@@ -0,0 +1,526 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dsstore
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"slices"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// magic identifies the buddy allocator that holds the records.
+	magic = "Bud1"
+
+	// headerSize is the size of the allocator header,
+	// which follows a four-byte alignment prefix.
+	headerSize = 32
+
+	// pageSize is the size of the B-tree nodes.
+	pageSize = 0x1000
+
+	// maxRecordSize is the size of the largest record, so that
+	// every node holds at least two records.
+	maxRecordSize = (pageSize-8)/2 - 4
+
+	// directoryName is the name under which the allocator lists the B-tree.
+	directoryName = "DSDB"
+
+	// freeListCount is the number of free lists of the allocator,
+	// one per power of two of the block sizes.
+	freeListCount = 32
+
+	// minBlockWidth is the log2 of the size of the smallest block.
+	minBlockWidth = 5
+)
+
+// Type is a four-character code stored as the value of a record, e.g. "icnv".
+type Type string
+
+// Record is a property of a file in the directory of the .DS_Store file.
+type Record struct {
+	// Name is the name of the file, or "." for the directory itself.
+	Name string
+
+	// Code is the four-character code of the property, e.g. "Iloc".
+	Code string
+
+	// Value is the value of the property: a bool, a uint16 ("shor"),
+	// a uint32 ("long"), a uint64 ("comp"), a Type, a []byte ("blob")
+	// or a string ("ustr").
+	Value any
+}
+
+// Encode encodes the records as a .DS_Store file,
+// sorted the way Finder expects them.
+func Encode(records []Record) ([]byte, error) {
+	sorted := slices.Clone(records)
+	slices.SortStableFunc(sorted, compareRecords)
+	encoded := make([][]byte, len(sorted))
+	for i, record := range sorted {
+		if i > 0 && compareRecords(sorted[i-1], record) == 0 {
+			return nil, errors.Errorf("record %s of [%s] is declared more than once", record.Code, record.Name)
+		}
+		var err error
+		if encoded[i], err = encodeRecord(record); err != nil {
+			return nil, err
+		}
+		if len(encoded[i]) > maxRecordSize {
+			return nil, errors.Errorf("record %s of [%s] is %d bytes long, more than %d", record.Code, record.Name, len(encoded[i]), maxRecordSize)
+		}
+	}
+
+	// blocks 0 and 1 are the bookkeeping block and the
+	// B-tree header; the B-tree nodes come after them.
+	t := buildTree(encoded, 2)
+	blocks := [][]byte{nil, nil}
+	blocks = append(blocks, t.nodes...)
+
+	a := newAllocator()
+	a.allocate(headerSize)
+	addresses := make([]uint32, len(blocks))
+	addresses[0] = a.allocate(bookkeepingSizeLimit(len(blocks)))
+	addresses[1] = a.allocate(20)
+	for i := 2; i < len(blocks); i++ {
+		addresses[i] = a.allocate(pageSize)
+	}
+
+	blocks[1] = binary.BigEndian.AppendUint32(nil, t.root)
+	blocks[1] = binary.BigEndian.AppendUint32(blocks[1], t.levels)
+	blocks[1] = binary.BigEndian.AppendUint32(blocks[1], uint32(len(records)))
+	blocks[1] = binary.BigEndian.AppendUint32(blocks[1], uint32(len(t.nodes)))
+	blocks[1] = binary.BigEndian.AppendUint32(blocks[1], pageSize)
+	blocks[0] = encodeBookkeeping(addresses, a.free)
+
+	fileSize := 0
+	for _, address := range addresses {
+		fileSize = max(fileSize, 4+blockOffset(address)+blockSize(address))
+	}
+	data := make([]byte, fileSize)
+	binary.BigEndian.PutUint32(data[0:4], 1)
+	copy(data[4:8], magic)
+	binary.BigEndian.PutUint32(data[8:12], uint32(blockOffset(addresses[0])))
+	binary.BigEndian.PutUint32(data[12:16], uint32(blockSize(addresses[0])))
+	binary.BigEndian.PutUint32(data[16:20], uint32(blockOffset(addresses[0])))
+	for i, block := range blocks {
+		copy(data[4+blockOffset(addresses[i]):], block)
+	}
+	return data, nil
+}
+
+// compareRecords orders the records by file name, ignoring case, and then by code.
+func compareRecords(a, b Record) int {
+	if c := strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)); c != 0 {
+		return c
+	}
+	return strings.Compare(a.Code, b.Code)
+}
+
+// encodeRecord encodes the file name, the code, the data type and the value of the record.
+func encodeRecord(record Record) ([]byte, error) {
+	if len(record.Code) != 4 {
+		return nil, errors.Errorf("code %q of record of [%s] is not four characters long", record.Code, record.Name)
+	}
+	data := appendUTF16(nil, record.Name)
+	data = append(data, record.Code...)
+	switch v := record.Value.(type) {
+	case bool:
+		data = append(data, "bool"...)
+		if v {
+			return append(data, 1), nil
+		}
+		return append(data, 0), nil
+	case uint16:
+		return binary.BigEndian.AppendUint32(append(data, "shor"...), uint32(v)), nil
+	case uint32:
+		return binary.BigEndian.AppendUint32(append(data, "long"...), v), nil
+	case uint64:
+		return binary.BigEndian.AppendUint64(append(data, "comp"...), v), nil
+	case Type:
+		if len(v) != 4 {
+			return nil, errors.Errorf("value %q of record %s of [%s] is not four characters long", v, record.Code, record.Name)
+		}
+		return append(append(data, "type"...), v...), nil
+	case []byte:
+		data = binary.BigEndian.AppendUint32(append(data, "blob"...), uint32(len(v)))
+		return append(data, v...), nil
+	case string:
+		return appendUTF16(append(data, "ustr"...), v), nil
+	}
+	return nil, errors.Errorf("value of record %s of [%s] has the unsupported type %T", record.Code, record.Name, record.Value)
+}
+
+// appendUTF16 appends the length of the string, in UTF-16 code
+// units, followed by the string in UTF-16 big endian.
+func appendUTF16(data []byte, s string) []byte {
+	units := utf16.Encode([]rune(s))
+	data = binary.BigEndian.AppendUint32(data, uint32(len(units)))
+	for _, unit := range units {
+		data = binary.BigEndian.AppendUint16(data, unit)
+	}
+	return data
+}
+
+// tree is a B-tree of records laid out in nodes of pageSize bytes.
+type tree struct {
+	nodes  [][]byte
+	root   uint32
+	levels uint32
+}
+
+// buildTree lays out the sorted records in a B-tree, numbering its
+// nodes from the given block number. Leaves hold the records, and each
+// internal node holds the records that separate the nodes below it.
+func buildTree(records [][]byte, firstBlock uint32) *tree {
+	t := &tree{}
+	addNode := func(children []uint32, entries [][]byte) uint32 {
+		node := make([]byte, 8, pageSize)
+		if len(children) > 0 {
+			binary.BigEndian.PutUint32(node[0:4], children[len(children)-1])
+		}
+		binary.BigEndian.PutUint32(node[4:8], uint32(len(entries)))
+		for i, entry := range entries {
+			if len(children) > 0 {
+				node = binary.BigEndian.AppendUint32(node, children[i])
+			}
+			node = append(node, entry...)
+		}
+		t.nodes = append(t.nodes, node)
+		return firstBlock + uint32(len(t.nodes)-1)
+	}
+
+	groups, separators := splitEntries(records, 0)
+	var level []uint32
+	for _, group := range groups {
+		level = append(level, addNode(nil, group))
+	}
+	for len(level) > 1 {
+		t.levels++
+		// the separators of the level below are the entries of this level:
+		// a node holding n of them points to the n+1 nodes around them.
+		groups, promoted := splitEntries(separators, 4)
+		var next []uint32
+		child := 0
+		for _, group := range groups {
+			next = append(next, addNode(level[child:child+len(group)+1], group))
+			child += len(group) + 1
+		}
+		level, separators = next, promoted
+	}
+	t.root = level[0]
+	return t
+}
+
+// splitEntries splits the entries in groups that fit in a node, each entry
+// taking the given overhead besides its size, and returns the entries
+// that separate the groups, which go to the level above.
+func splitEntries(entries [][]byte, overhead int) ([][][]byte, [][]byte) {
+	groups := [][][]byte{nil}
+	var separators [][]byte
+	size := 8
+	for i := 0; i < len(entries); i++ {
+		if size+overhead+len(entries[i]) <= pageSize {
+			groups[len(groups)-1] = append(groups[len(groups)-1], entries[i])
+			size += overhead + len(entries[i])
+			continue
+		}
+		if i == len(entries)-1 {
+			// the last entry cannot separate the full group from an empty
+			// one, so the last entry of the full group separates them instead.
+			last := groups[len(groups)-1]
+			groups[len(groups)-1] = last[:len(last)-1]
+			separators = append(separators, last[len(last)-1])
+			groups = append(groups, [][]byte{entries[i]})
+			break
+		}
+		separators = append(separators, entries[i])
+		groups = append(groups, nil)
+		size = 8
+	}
+	return groups, separators
+}
+
+// allocator is a buddy allocator of the blocks of a .DS_Store file.
+type allocator struct {
+	// free holds, for each block width, the offsets of the free blocks, in order.
+	free [freeListCount][]uint32
+}
+
+// newAllocator returns an allocator whose whole space is free.
+func newAllocator() *allocator {
+	a := &allocator{}
+	a.free[freeListCount-1] = []uint32{0}
+	return a
+}
+
+// allocate allocates the lowest free block that holds the given size,
+// splitting larger blocks as needed, and returns its address: its
+// offset combined with the log2 of its size.
+func (a *allocator) allocate(size int) uint32 {
+	width := max(minBlockWidth, bits.Len(uint(size-1)))
+	w := width
+	for len(a.free[w]) == 0 {
+		w++
+	}
+	offset := a.free[w][0]
+	a.free[w] = a.free[w][1:]
+	for w > width {
+		w--
+		a.free[w] = append(a.free[w], offset+1<<w)
+		slices.Sort(a.free[w])
+	}
+	return offset | uint32(width)
+}
+
+// bookkeepingSizeLimit returns the size the bookkeeping block of a file with
+// the given number of blocks cannot exceed: each allocation splits at most
+// one block per free list.
+func bookkeepingSizeLimit(blockCount int) int {
+	return 8 + 4*roundUp(blockCount, 256) + 4 + 1 + len(directoryName) + 4 + 4*freeListCount*(blockCount+2)
+}
+
+// encodeBookkeeping encodes the block that lists the addresses of
+// the blocks, the B-tree directory and the free lists of the allocator.
+func encodeBookkeeping(addresses []uint32, free [freeListCount][]uint32) []byte {
+	data := binary.BigEndian.AppendUint32(nil, uint32(len(addresses)))
+	data = binary.BigEndian.AppendUint32(data, 0)
+	for i := range roundUp(len(addresses), 256) {
+		var address uint32
+		if i < len(addresses) {
+			address = addresses[i]
+		}
+		data = binary.BigEndian.AppendUint32(data, address)
+	}
+	data = binary.BigEndian.AppendUint32(data, 1)
+	data = append(data, byte(len(directoryName)))
+	data = append(data, directoryName...)
+	data = binary.BigEndian.AppendUint32(data, 1)
+	for _, offsets := range free {
+		data = binary.BigEndian.AppendUint32(data, uint32(len(offsets)))
+		for _, offset := range offsets {
+			data = binary.BigEndian.AppendUint32(data, offset)
+		}
+	}
+	return data
+}
+
+// roundUp rounds n up to a multiple of m.
+func roundUp(n, m int) int {
+	return (n + m - 1) / m * m
+}
+
+// blockOffset returns the offset of the block with the given address.
+func blockOffset(address uint32) int {
+	return int(address &^ 0x1f)
+}
+
+// blockSize returns the size of the block with the given address.
+func blockSize(address uint32) int {
+	return 1 << (address & 0x1f)
+}
+
+// Decode decodes the records of a .DS_Store file, in the order they are stored.
+func Decode(data []byte) ([]Record, error) {
+	if len(data) < 4+headerSize || binary.BigEndian.Uint32(data[0:4]) != 1 || string(data[4:8]) != magic {
+		return nil, errors.New("not a .DS_Store file")
+	}
+	bookkeepingOffset := binary.BigEndian.Uint32(data[8:12])
+	bookkeepingSize := binary.BigEndian.Uint32(data[12:16])
+	if binary.BigEndian.Uint32(data[16:20]) != bookkeepingOffset {
+		return nil, errors.New("invalid .DS_Store header")
+	}
+	bookkeeping, err := slice(data, 4+int(bookkeepingOffset), int(bookkeepingSize))
+	if err != nil {
+		return nil, errors.Wrap(err, "error when reading the bookkeeping block")
+	}
+
+	r := &reader{data: bookkeeping}
+	addresses := make([]uint32, r.uint32())
+	r.skip(4)
+	for i := range roundUp(len(addresses), 256) {
+		address := r.uint32()
+		if i < len(addresses) {
+			addresses[i] = address
+		}
+	}
+	directoryBlock := -1
+	for range r.uint32() {
+		name := r.bytes(int(r.byte()))
+		block := r.uint32()
+		if string(name) == directoryName {
+			directoryBlock = int(block)
+		}
+	}
+	if r.err != nil {
+		return nil, errors.Wrap(r.err, "error when reading the bookkeeping block")
+	}
+	d := &decoder{data: data, addresses: addresses}
+	if directoryBlock < 0 {
+		return nil, errors.Errorf("directory %s not found", directoryName)
+	}
+	header, err := d.block(uint32(directoryBlock))
+	if err != nil {
+		return nil, err
+	}
+	r = &reader{data: header}
+	root, levels, count := r.uint32(), r.uint32(), r.uint32()
+	if r.err != nil {
+		return nil, errors.Wrap(r.err, "error when reading the B-tree header")
+	}
+	if err := d.node(root, int(levels)); err != nil {
+		return nil, err
+	}
+	if len(d.records) != int(count) {
+		return nil, errors.Errorf("found %d records, expected %d", len(d.records), count)
+	}
+	return d.records, nil
+}
+
+// decoder decodes the B-tree of a .DS_Store file.
+type decoder struct {
+	data      []byte
+	addresses []uint32
+	records   []Record
+}
+
+// block returns the contents of the block with the given number.
+func (d *decoder) block(number uint32) ([]byte, error) {
+	if int(number) >= len(d.addresses) {
+		return nil, errors.Errorf("invalid block number %d", number)
+	}
+	address := d.addresses[number]
+	block, err := slice(d.data, 4+blockOffset(address), blockSize(address))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when reading block %d", number)
+	}
+	return block, nil
+}
+
+// node decodes the records of the node with the given block number and of the
+// nodes below it, in order. The depth is the number of levels below the node.
+func (d *decoder) node(number uint32, depth int) error {
+	block, err := d.block(number)
+	if err != nil {
+		return err
+	}
+	r := &reader{data: block}
+	rightmost, count := r.uint32(), r.uint32()
+	if (rightmost != 0) != (depth > 0) {
+		return errors.Errorf("node %d does not match the depth of the B-tree", number)
+	}
+	for range count {
+		if depth > 0 {
+			child := r.uint32()
+			if r.err != nil {
+				break
+			}
+			if err := d.node(child, depth-1); err != nil {
+				return err
+			}
+		}
+		record := r.record()
+		if r.err != nil {
+			break
+		}
+		d.records = append(d.records, record)
+	}
+	if r.err != nil {
+		return errors.Wrapf(r.err, "error when reading node %d", number)
+	}
+	if depth > 0 {
+		return d.node(rightmost, depth-1)
+	}
+	return nil
+}
+
+// reader reads big-endian values from a block, remembering the first error.
+type reader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+// bytes returns the next n bytes.
+func (r *reader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	b, err := slice(r.data, r.pos, n)
+	if err != nil {
+		r.err = err
+		return nil
+	}
+	r.pos += n
+	return b
+}
+
+func (r *reader) skip(n int) {
+	r.bytes(n)
+}
+
+func (r *reader) byte() byte {
+	if b := r.bytes(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *reader) uint32() uint32 {
+	if b := r.bytes(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+// utf16 reads a string prefixed by its length in UTF-16 code units.
+func (r *reader) utf16() string {
+	raw := r.bytes(2 * int(r.uint32()))
+	units := make([]uint16, len(raw)/2)
+	for i := range units {
+		units[i] = binary.BigEndian.Uint16(raw[2*i:])
+	}
+	return string(utf16.Decode(units))
+}
+
+// record reads a record.
+func (r *reader) record() Record {
+	record := Record{Name: r.utf16(), Code: string(r.bytes(4))}
+	dataType := string(r.bytes(4))
+	switch dataType {
+	case "bool":
+		record.Value = r.byte() != 0
+	case "shor":
+		record.Value = uint16(r.uint32())
+	case "long":
+		record.Value = r.uint32()
+	case "comp":
+		if b := r.bytes(8); b != nil {
+			record.Value = binary.BigEndian.Uint64(b)
+		}
+	case "type":
+		record.Value = Type(r.bytes(4))
+	case "blob":
+		record.Value = append([]byte(nil), r.bytes(int(r.uint32()))...)
+	case "ustr":
+		record.Value = r.utf16()
+	default:
+		if r.err == nil {
+			r.err = errors.Errorf("record %s of [%s] has the unsupported data type %q", record.Code, record.Name, dataType)
+		}
+	}
+	return record
+}
+
+// slice returns the n bytes of data that start at the given offset.
+func slice(data []byte, offset, n int) ([]byte, error) {
+	if offset < 0 || n < 0 || offset+n > len(data) {
+		return nil, errors.Errorf("%d bytes at offset %d are out of bounds", n, offset)
+	}
+	return data[offset : offset+n], nil
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dsstore
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeRoundTrip(t *testing.T) {
+	var many []Record
+	for i := range 2000 {
+		many = append(many, Record{Name: fmt.Sprintf("file %04d.txt", i), Code: "Iloc", Value: make([]byte, 16)})
+	}
+	testCases := []struct {
+		name     string
+		records  []Record
+		expected []Record
+	}{
+		{
+			name: "every type, sorted",
+			records: []Record{
+				{Name: "Zebra.app", Code: "Iloc", Value: []byte{0, 0, 0, 10, 0, 0, 0, 20}},
+				{Name: ".", Code: "vstl", Value: Type("icnv")},
+				{Name: "applications", Code: "cmmt", Value: "Drag here — ☕"},
+				{Name: ".", Code: "bwsp", Value: []byte("bplist00")},
+				{Name: ".", Code: "ICVO", Value: true},
+				{Name: ".", Code: "icvt", Value: uint16(12)},
+				{Name: ".", Code: "vSrn", Value: uint32(1)},
+				{Name: "Zebra.app", Code: "lg1S", Value: uint64(1 << 40)},
+			},
+			expected: []Record{
+				{Name: ".", Code: "ICVO", Value: true},
+				{Name: ".", Code: "bwsp", Value: []byte("bplist00")},
+				{Name: ".", Code: "icvt", Value: uint16(12)},
+				{Name: ".", Code: "vSrn", Value: uint32(1)},
+				{Name: ".", Code: "vstl", Value: Type("icnv")},
+				{Name: "applications", Code: "cmmt", Value: "Drag here — ☕"},
+				{Name: "Zebra.app", Code: "Iloc", Value: []byte{0, 0, 0, 10, 0, 0, 0, 20}},
+				{Name: "Zebra.app", Code: "lg1S", Value: uint64(1 << 40)},
+			},
+		},
+		{
+			name:     "no records",
+			expected: nil,
+		},
+		{
+			name:     "several levels",
+			records:  many,
+			expected: many,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := Encode(tc.records)
+			require.NoError(t, err)
+			decoded, err := Decode(data)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, decoded)
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	data, err := Encode([]Record{{Name: ".", Code: "vSrn", Value: uint32(1)}})
+	require.NoError(t, err)
+	require.Equal(t, "\x00\x00\x00\x01Bud1", string(data[:8]))
+	bookkeepingOffset := binary.BigEndian.Uint32(data[8:12])
+	require.Equal(t, bookkeepingOffset, binary.BigEndian.Uint32(data[16:20]))
+	require.Zero(t, bookkeepingOffset%uint32(binary.BigEndian.Uint32(data[12:16])))
+
+	// the only leaf holds the record after its header.
+	leaf := data[len(data)-pageSize:]
+	require.Equal(t, []byte{0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 1, 0, 0x2e, 'v', 'S', 'r', 'n', 'l', 'o', 'n', 'g', 0, 0, 0, 1}, leaf[:26])
+}
+
+func TestEncodeErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		records       []Record
+		expectedError string
+	}{
+		{
+			name:          "invalid code",
+			records:       []Record{{Name: "a", Code: "Il", Value: true}},
+			expectedError: `code "Il" of record of [a] is not four characters long`,
+		},
+		{
+			name:          "invalid type",
+			records:       []Record{{Name: ".", Code: "vstl", Value: Type("icon view")}},
+			expectedError: `value "icon view" of record vstl of [.] is not four characters long`,
+		},
+		{
+			name:          "unsupported value",
+			records:       []Record{{Name: "a", Code: "Iloc", Value: 1.5}},
+			expectedError: "value of record Iloc of [a] has the unsupported type float64",
+		},
+		{
+			name: "duplicate record",
+			records: []Record{
+				{Name: "A", Code: "Iloc", Value: []byte{}},
+				{Name: "a", Code: "Iloc", Value: []byte{}},
+			},
+			expectedError: "record Iloc of [a] is declared more than once",
+		},
+		{
+			name:          "record too large",
+			records:       []Record{{Name: "a", Code: "bwsp", Value: make([]byte, pageSize)}},
+			expectedError: "record bwsp of [a] is 4114 bytes long, more than 2040",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Encode(tc.records)
+			require.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	valid, err := Encode([]Record{{Name: "a", Code: "Iloc", Value: []byte{1, 2}}})
+	require.NoError(t, err)
+	testCases := []struct {
+		name          string
+		data          func() []byte
+		expectedError string
+	}{
+		{
+			name: "not a .DS_Store file",
+			data: func() []byte {
+				return []byte(strings.Repeat("x", 64))
+			},
+			expectedError: "not a .DS_Store file",
+		},
+		{
+			name: "invalid header",
+			data: func() []byte {
+				data := append([]byte(nil), valid...)
+				data[19]++
+				return data
+			},
+			expectedError: "invalid .DS_Store header",
+		},
+		{
+			name: "truncated",
+			data: func() []byte {
+				return valid[:len(valid)-pageSize]
+			},
+			expectedError: "error when reading block 2: 4096 bytes at offset 4100 are out of bounds",
+		},
+		{
+			name: "unsupported data type",
+			data: func() []byte {
+				data := append([]byte(nil), valid...)
+				copy(data[len(data)-pageSize+18:], "dutc")
+				return data
+			},
+			expectedError: `error when reading node 2: record Iloc of [a] has the unsupported data type "dutc"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Decode(tc.data())
+			require.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
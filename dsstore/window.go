@@ -0,0 +1,134 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dsstore
+
+import (
+	"encoding/binary"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/pkg/errors"
+	"github.com/tiagomelo/macos-dmg-creator/plist"
+)
+
+// directory is the name under which the records of the directory itself are stored.
+const directory = "."
+
+// backgroundTypeImage is the background type of windows with a background image.
+const backgroundTypeImage = 2
+
+// Point is a position in the Finder window, in points from its top left corner.
+type Point struct {
+	X, Y int
+}
+
+// Window is the layout of the Finder window of a directory shown in icon view,
+// with the toolbar, the sidebar and the status bar hidden.
+type Window struct {
+	// X and Y are the position of the window on the screen.
+	X, Y int
+
+	// Width and Height are the size of the window.
+	Width, Height int
+
+	// IconSize is the size of the icons, in points.
+	IconSize int
+
+	// TextSize is the size of the text of the icon labels, in points.
+	TextSize int
+
+	// Positions are the positions of the centers of the icons, by item name.
+	Positions map[string]Point
+
+	// Background points to the background image of the window, if any.
+	Background *Alias
+}
+
+// browserWindowSettings is the property list stored in the bwsp record.
+type browserWindowSettings struct {
+	WindowBounds          string `plist:"WindowBounds"`
+	ShowToolbar           bool   `plist:"ShowToolbar"`
+	ShowStatusBar         bool   `plist:"ShowStatusBar"`
+	ShowSidebar           bool   `plist:"ShowSidebar"`
+	ShowPathbar           bool   `plist:"ShowPathbar"`
+	ShowTabView           bool   `plist:"ShowTabView"`
+	ContainerShowSidebar  bool   `plist:"ContainerShowSidebar"`
+	PreviewPaneVisibility bool   `plist:"PreviewPaneVisibility"`
+}
+
+// iconViewSettings is the property list stored in the icvp record.
+type iconViewSettings struct {
+	ArrangeBy            string  `plist:"arrangeBy"`
+	BackgroundType       int     `plist:"backgroundType"`
+	BackgroundColorRed   float64 `plist:"backgroundColorRed"`
+	BackgroundColorGreen float64 `plist:"backgroundColorGreen"`
+	BackgroundColorBlue  float64 `plist:"backgroundColorBlue"`
+	GridOffsetX          float64 `plist:"gridOffsetX"`
+	GridOffsetY          float64 `plist:"gridOffsetY"`
+	GridSpacing          float64 `plist:"gridSpacing"`
+	IconSize             float64 `plist:"iconSize"`
+	TextSize             float64 `plist:"textSize"`
+	LabelOnBottom        bool    `plist:"labelOnBottom"`
+	ShowIconPreview      bool    `plist:"showIconPreview"`
+	ShowItemInfo         bool    `plist:"showItemInfo"`
+	ViewOptionsVersion   int     `plist:"viewOptionsVersion"`
+	BackgroundImageAlias []byte  `plist:"backgroundImageAlias,omitempty"`
+}
+
+// Records returns the records that lay out the window.
+func (w *Window) Records() ([]Record, error) {
+	bwsp, err := plist.EncodeBinary(&browserWindowSettings{
+		WindowBounds: fmt.Sprintf("{{%d, %d}, {%d, %d}}", w.X, w.Y, w.Width, w.Height),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error when encoding window settings")
+	}
+	iconView := &iconViewSettings{
+		ArrangeBy:            "none",
+		BackgroundColorRed:   1,
+		BackgroundColorGreen: 1,
+		BackgroundColorBlue:  1,
+		GridSpacing:          100,
+		IconSize:             float64(w.IconSize),
+		TextSize:             float64(w.TextSize),
+		LabelOnBottom:        true,
+		ShowIconPreview:      true,
+		ViewOptionsVersion:   1,
+	}
+	if w.Background != nil {
+		alias, err := w.Background.Encode()
+		if err != nil {
+			return nil, errors.Wrap(err, "error when encoding background image alias")
+		}
+		iconView.BackgroundType = backgroundTypeImage
+		iconView.BackgroundImageAlias = alias
+	}
+	icvp, err := plist.EncodeBinary(iconView)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when encoding icon view settings")
+	}
+	records := []Record{
+		{Name: directory, Code: "bwsp", Value: bwsp},
+		{Name: directory, Code: "icvp", Value: icvp},
+		{Name: directory, Code: "vSrn", Value: uint32(1)},
+		{Name: directory, Code: "vstl", Value: Type("icnv")},
+	}
+	for _, name := range slices.Sorted(maps.Keys(w.Positions)) {
+		position := w.Positions[name]
+		if position.X < 0 || position.Y < 0 {
+			return nil, errors.Errorf("position (%d, %d) of [%s] is outside of the window", position.X, position.Y, name)
+		}
+		records = append(records, Record{Name: name, Code: "Iloc", Value: iconLocation(position)})
+	}
+	return records, nil
+}
+
+// iconLocation encodes the position of an icon as stored in the Iloc record.
+func iconLocation(position Point) []byte {
+	data := binary.BigEndian.AppendUint32(nil, uint32(position.X))
+	data = binary.BigEndian.AppendUint32(data, uint32(position.Y))
+	return append(data, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0, 0)
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package dsstore
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/macos-dmg-creator/plist"
+)
+
+func TestWindowRecords(t *testing.T) {
+	testCases := []struct {
+		name          string
+		window        *Window
+		expectedIloc  map[string][]byte
+		expectedError string
+	}{
+		{
+			name: "happy path",
+			window: &Window{
+				X: 100, Y: 120, Width: 640, Height: 400,
+				IconSize: 128, TextSize: 12,
+				Positions: map[string]Point{
+					"MyApp.app":    {X: 160, Y: 200},
+					"Applications": {X: 480, Y: 200},
+				},
+			},
+			expectedIloc: map[string][]byte{
+				"Applications": {0, 0, 1, 0xe0, 0, 0, 0, 0xc8, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0, 0},
+				"MyApp.app":    {0, 0, 0, 0xa0, 0, 0, 0, 0xc8, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0, 0},
+			},
+		},
+		{
+			name: "background image",
+			window: &Window{
+				Width: 640, Height: 400, IconSize: 128, TextSize: 12,
+				Background: &Alias{VolumeName: "MyApp", Path: ".background/background.png", ParentID: 17, FileID: 18},
+			},
+		},
+		{
+			name: "position outside of the window",
+			window: &Window{
+				Width: 640, Height: 400, IconSize: 128, TextSize: 12,
+				Positions: map[string]Point{"MyApp.app": {X: -10, Y: 200}},
+			},
+			expectedError: "position (-10, 200) of [MyApp.app] is outside of the window",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			records, err := tc.window.Records()
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+
+			data, err := Encode(records)
+			require.NoError(t, err)
+			decoded, err := Decode(data)
+			require.NoError(t, err)
+
+			values := map[string]map[string]any{}
+			for _, record := range decoded {
+				if values[record.Name] == nil {
+					values[record.Name] = map[string]any{}
+				}
+				values[record.Name][record.Code] = record.Value
+			}
+			require.Equal(t, uint32(1), values["."]["vSrn"])
+			require.Equal(t, Type("icnv"), values["."]["vstl"])
+			for name, iloc := range tc.expectedIloc {
+				require.Equal(t, iloc, values[name]["Iloc"])
+			}
+
+			bwsp, err := plist.Decode(values["."]["bwsp"].([]byte))
+			require.NoError(t, err)
+			require.Equal(t, map[string]any{
+				"WindowBounds":          fmt.Sprintf("{{%d, %d}, {%d, %d}}", tc.window.X, tc.window.Y, tc.window.Width, tc.window.Height),
+				"ShowToolbar":           false,
+				"ShowStatusBar":         false,
+				"ShowSidebar":           false,
+				"ShowPathbar":           false,
+				"ShowTabView":           false,
+				"ContainerShowSidebar":  false,
+				"PreviewPaneVisibility": false,
+			}, bwsp)
+
+			icvp, err := plist.Decode(values["."]["icvp"].([]byte))
+			require.NoError(t, err)
+			expectedIcvp := map[string]any{
+				"arrangeBy":            "none",
+				"backgroundType":       int64(0),
+				"backgroundColorRed":   1.0,
+				"backgroundColorGreen": 1.0,
+				"backgroundColorBlue":  1.0,
+				"gridOffsetX":          0.0,
+				"gridOffsetY":          0.0,
+				"gridSpacing":          100.0,
+				"iconSize":             float64(tc.window.IconSize),
+				"textSize":             12.0,
+				"labelOnBottom":        true,
+				"showIconPreview":      true,
+				"showItemInfo":         false,
+				"viewOptionsVersion":   int64(1),
+			}
+			if tc.window.Background != nil {
+				alias, err := tc.window.Background.Encode()
+				require.NoError(t, err)
+				expectedIcvp["backgroundType"] = int64(2)
+				expectedIcvp["backgroundImageAlias"] = alias
+			}
+			require.Equal(t, expectedIcvp, icvp)
+		})
+	}
+}
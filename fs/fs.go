@@ -6,6 +6,7 @@ package fs
 
 import (
 	"os"
+	"path/filepath"
 
 	"github.com/pkg/errors"
 	"github.com/tiagomelo/macos-dmg-creator/syscall"
@@ -13,8 +14,12 @@ import (
 
 // for ease of unit testing.
 var (
-	osStat      = os.Stat
-	osWriteFile = os.WriteFile
+	osStat               = os.Stat
+	osReadFile           = os.ReadFile
+	osWriteFile          = os.WriteFile
+	filepathGlob         = filepath.Glob
+	filepathEvalSymlinks = filepath.EvalSymlinks
+	filepathWalkDir      = filepath.WalkDir
 )
 
 // osCommandExecutorProvider is a variable that holds the function
@@ -42,9 +47,10 @@ func CopyFile(src, dst string) error {
 	return nil
 }
 
-// CopyDir copies a directory from src to dst.
+// CopyDir copies a directory from src to dst, preserving
+// symbolic links, permissions and extended attributes.
 func CopyDir(src, dst string) error {
-	if _, err := osCommandExecutorProvider.ExecCommand("cp", "-r", src, dst); err != nil {
+	if _, err := osCommandExecutorProvider.ExecCommand("cp", "-a", src, dst); err != nil {
 		return errors.Wrapf(err, "error when copying directory from [%s] to [%s]", src, dst)
 	}
 	return nil
@@ -65,6 +71,81 @@ func FileExists(path string) (bool, error) {
 	return true, nil
 }
 
+// DirExists checks if a directory exists.
+// It returns false when the path exists but is not a directory.
+func DirExists(path string) (bool, error) {
+	info, err := osStat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "error when checking if [%s] exists", path)
+	}
+	return info.IsDir(), nil
+}
+
+// FileID returns the identifier of the file or directory on its volume,
+// i.e. its inode number, which is its catalog node identifier on macOS.
+func FileID(path string) (uint64, error) {
+	info, err := osStat(path)
+	if err != nil {
+		return 0, errors.Wrapf(err, "error when reading file info of [%s]", path)
+	}
+	id, ok := inode(info)
+	if !ok {
+		return 0, errors.Errorf("the file system of [%s] does not provide file identifiers", path)
+	}
+	return id, nil
+}
+
+// FileSize returns the size of the file, in bytes.
+func FileSize(path string) (int64, error) {
+	info, err := osStat(path)
+	if err != nil {
+		return 0, errors.Wrapf(err, "error when reading file info of [%s]", path)
+	}
+	return info.Size(), nil
+}
+
+// DirSize returns the total size, in bytes, of the regular files
+// in the directory and its subdirectories. Symbolic links are not followed.
+func DirSize(path string) (int64, error) {
+	var size int64
+	err := filepathWalkDir(path, func(_ string, entry os.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	if err != nil {
+		return 0, errors.Wrapf(err, "error when reading the size of directory [%s]", path)
+	}
+	return size, nil
+}
+
+// Glob returns the paths of the files and directories matching the pattern.
+func Glob(pattern string) ([]string, error) {
+	matches, err := filepathGlob(pattern)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when matching pattern [%s]", pattern)
+	}
+	return matches, nil
+}
+
+// RealPath returns the path with all symbolic links resolved.
+func RealPath(path string) (string, error) {
+	realPath, err := filepathEvalSymlinks(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "error when resolving symbolic links of [%s]", path)
+	}
+	return realPath, nil
+}
+
 // DeleteFile deletes a file.
 func DeleteFile(path string) error {
 	if _, err := osCommandExecutorProvider.ExecCommand("rm", "-f", path); err != nil {
@@ -89,6 +170,15 @@ func MkdirAll(path string, perm os.FileMode) error {
 	return nil
 }
 
+// ReadFile reads the file named name.
+func ReadFile(name string) ([]byte, error) {
+	data, err := osReadFile(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when reading file [%s]", name)
+	}
+	return data, nil
+}
+
 // WriteFile writes data to a file named name.
 func WriteFile(name string, data []byte, perm os.FileMode) error {
 	if err := osWriteFile(name, data, perm); err != nil {
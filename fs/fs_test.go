@@ -7,6 +7,8 @@ package fs
 import (
 	sysFs "io/fs"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -155,6 +157,288 @@ func TestFileExists(t *testing.T) {
 	}
 }
 
+func TestDirExists(t *testing.T) {
+	testCases := []struct {
+		name       string
+		mockOsStat func(name string) (sysFs.FileInfo, error)
+		want       bool
+		wanterror  error
+	}{
+		{
+			name: "exists",
+			mockOsStat: func(name string) (sysFs.FileInfo, error) {
+				return &mockFileInfo{isDir: true}, nil
+			},
+			want: true,
+		},
+		{
+			name: "does not exist",
+			mockOsStat: func(name string) (sysFs.FileInfo, error) {
+				return nil, os.ErrNotExist
+			},
+		},
+		{
+			name: "is a file",
+			mockOsStat: func(name string) (sysFs.FileInfo, error) {
+				return &mockFileInfo{isDir: false}, nil
+			},
+		},
+		{
+			name: "error",
+			mockOsStat: func(name string) (sysFs.FileInfo, error) {
+				return nil, errors.New("some error")
+			},
+			wanterror: errors.New("error when checking if [someDir] exists: some error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			osStat = tc.mockOsStat
+
+			output, err := DirExists("someDir")
+			if err != nil {
+				if tc.wanterror == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				if tc.wanterror.Error() != err.Error() {
+					t.Fatalf(`expected error "%v", got "%v"`, tc.wanterror, err)
+				}
+			} else {
+				if tc.wanterror != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.wanterror)
+				}
+				require.Equal(t, tc.want, output)
+			}
+		})
+	}
+}
+
+func TestFileID(t *testing.T) {
+	testCases := []struct {
+		name       string
+		mockOsStat func(name string) (sysFs.FileInfo, error)
+		want       uint64
+		wanterror  error
+	}{
+		{
+			name: "no file identifiers",
+			mockOsStat: func(name string) (sysFs.FileInfo, error) {
+				return &mockFileInfo{}, nil
+			},
+			wanterror: errors.New("the file system of [someFile] does not provide file identifiers"),
+		},
+		{
+			name: "error",
+			mockOsStat: func(name string) (sysFs.FileInfo, error) {
+				return nil, errors.New("some error")
+			},
+			wanterror: errors.New("error when reading file info of [someFile]: some error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			osStat = tc.mockOsStat
+
+			output, err := FileID("someFile")
+			if err != nil {
+				if tc.wanterror == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				if tc.wanterror.Error() != err.Error() {
+					t.Fatalf(`expected error "%v", got "%v"`, tc.wanterror, err)
+				}
+			} else {
+				if tc.wanterror != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.wanterror)
+				}
+				require.Equal(t, tc.want, output)
+			}
+		})
+	}
+}
+
+func TestFileSize(t *testing.T) {
+	testCases := []struct {
+		name       string
+		mockOsStat func(name string) (sysFs.FileInfo, error)
+		want       int64
+		wanterror  error
+	}{
+		{
+			name: "happy path",
+			mockOsStat: func(name string) (sysFs.FileInfo, error) {
+				return &mockFileInfo{size: 1024}, nil
+			},
+			want: 1024,
+		},
+		{
+			name: "error",
+			mockOsStat: func(name string) (sysFs.FileInfo, error) {
+				return nil, errors.New("some error")
+			},
+			wanterror: errors.New("error when reading file info of [someFile]: some error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			osStat = tc.mockOsStat
+
+			output, err := FileSize("someFile")
+			if err != nil {
+				if tc.wanterror == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				if tc.wanterror.Error() != err.Error() {
+					t.Fatalf(`expected error "%v", got "%v"`, tc.wanterror, err)
+				}
+			} else {
+				if tc.wanterror != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.wanterror)
+				}
+				require.Equal(t, tc.want, output)
+			}
+		})
+	}
+}
+
+func TestDirSize(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mockWalkDir func(root string, fn sysFs.WalkDirFunc) error
+		want        int64
+		wanterror   error
+	}{
+		{
+			name:        "happy path",
+			mockWalkDir: filepath.WalkDir,
+			want:        1536,
+		},
+		{
+			name: "error",
+			mockWalkDir: func(root string, fn sysFs.WalkDirFunc) error {
+				return fn(root, nil, errors.New("some error"))
+			},
+			wanterror: errors.New("error when reading the size of directory [DIR]: some error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filepathWalkDir = tc.mockWalkDir
+			defer func() { filepathWalkDir = filepath.WalkDir }()
+
+			dir := t.TempDir()
+			require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "a"), make([]byte, 1024), 0o644))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "b"), make([]byte, 512), 0o644))
+			require.NoError(t, os.Symlink(filepath.Join(dir, "a"), filepath.Join(dir, "link")))
+
+			output, err := DirSize(dir)
+			if err != nil {
+				if tc.wanterror == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				wanterror := strings.Replace(tc.wanterror.Error(), "DIR", dir, 1)
+				if wanterror != err.Error() {
+					t.Fatalf(`expected error "%v", got "%v"`, wanterror, err)
+				}
+			} else {
+				if tc.wanterror != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.wanterror)
+				}
+				require.Equal(t, tc.want, output)
+			}
+		})
+	}
+}
+
+func TestGlob(t *testing.T) {
+	testCases := []struct {
+		name             string
+		mockFilepathGlob func(pattern string) ([]string, error)
+		want             []string
+		wanterror        error
+	}{
+		{
+			name: "happy path",
+			mockFilepathGlob: func(pattern string) ([]string, error) {
+				return []string{"a.txt", "b.txt"}, nil
+			},
+			want: []string{"a.txt", "b.txt"},
+		},
+		{
+			name: "error",
+			mockFilepathGlob: func(pattern string) ([]string, error) {
+				return nil, errors.New("some error")
+			},
+			wanterror: errors.New("error when matching pattern [*.txt]: some error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filepathGlob = tc.mockFilepathGlob
+
+			output, err := Glob("*.txt")
+			if err != nil {
+				if tc.wanterror == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				if tc.wanterror.Error() != err.Error() {
+					t.Fatalf(`expected error "%v", got "%v"`, tc.wanterror, err)
+				}
+			} else {
+				if tc.wanterror != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.wanterror)
+				}
+				require.Equal(t, tc.want, output)
+			}
+		})
+	}
+}
+
+func TestRealPath(t *testing.T) {
+	testCases := []struct {
+		name                     string
+		mockFilepathEvalSymlinks func(path string) (string, error)
+		want                     string
+		wanterror                error
+	}{
+		{
+			name: "happy path",
+			mockFilepathEvalSymlinks: func(path string) (string, error) {
+				return "/opt/homebrew/Cellar/foo/1.0/lib/libfoo.1.dylib", nil
+			},
+			want: "/opt/homebrew/Cellar/foo/1.0/lib/libfoo.1.dylib",
+		},
+		{
+			name: "error",
+			mockFilepathEvalSymlinks: func(path string) (string, error) {
+				return "", errors.New("some error")
+			},
+			wanterror: errors.New("error when resolving symbolic links of [/opt/homebrew/lib/libfoo.1.dylib]: some error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filepathEvalSymlinks = tc.mockFilepathEvalSymlinks
+
+			output, err := RealPath("/opt/homebrew/lib/libfoo.1.dylib")
+			if err != nil {
+				if tc.wanterror == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				if tc.wanterror.Error() != err.Error() {
+					t.Fatalf(`expected error "%v", got "%v"`, tc.wanterror, err)
+				}
+			} else {
+				if tc.wanterror != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.wanterror)
+				}
+				require.Equal(t, tc.want, output)
+			}
+		})
+	}
+}
+
 func TestDeleteFile(t *testing.T) {
 	testCases := []struct {
 		name                  string
@@ -281,6 +565,49 @@ func TestMkdirAll(t *testing.T) {
 	}
 }
 
+func TestReadFile(t *testing.T) {
+	testCases := []struct {
+		name           string
+		mockOsReadFile func(name string) ([]byte, error)
+		want           []byte
+		wantErr        error
+	}{
+		{
+			name: "happy path",
+			mockOsReadFile: func(name string) ([]byte, error) {
+				return []byte("someData"), nil
+			},
+			want: []byte("someData"),
+		},
+		{
+			name: "error",
+			mockOsReadFile: func(name string) ([]byte, error) {
+				return nil, errors.New("some error")
+			},
+			wantErr: errors.New("error when reading file [someFile]: some error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			osReadFile = tc.mockOsReadFile
+			got, err := ReadFile("someFile")
+			if err != nil {
+				if tc.wantErr == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				if tc.wantErr.Error() != err.Error() {
+					t.Fatalf(`expected error "%v", got "%v"`, tc.wantErr, err)
+				}
+			} else {
+				if tc.wantErr != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.wantErr)
+				}
+				require.Equal(t, tc.want, got)
+			}
+		})
+	}
+}
+
 func TestWriteFile(t *testing.T) {
 	testCases := []struct {
 		name            string
@@ -373,11 +700,13 @@ func (m *mockOsCommandExecutor) ExecCommand(name string, arg ...string) (string,
 
 type mockFileInfo struct {
 	isDir bool
+	size  int64
+	sys   any
 }
 
 func (m *mockFileInfo) Name() string       { return "" }
-func (m *mockFileInfo) Size() int64        { return 0 }
+func (m *mockFileInfo) Size() int64        { return m.size }
 func (m *mockFileInfo) Mode() os.FileMode  { return 0 }
 func (m *mockFileInfo) ModTime() time.Time { return time.Time{} }
 func (m *mockFileInfo) IsDir() bool        { return m.isDir }
-func (m *mockFileInfo) Sys() interface{}   { return nil }
+func (m *mockFileInfo) Sys() interface{}   { return m.sys }
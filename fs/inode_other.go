@@ -0,0 +1,14 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+//go:build !unix
+
+package fs
+
+import "os"
+
+// inode returns the inode number of the file, which only the file systems of Unix systems provide.
+func inode(info os.FileInfo) (uint64, bool) {
+	return 0, false
+}
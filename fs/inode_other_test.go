@@ -0,0 +1,19 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+//go:build !unix
+
+package fs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInode(t *testing.T) {
+	id, ok := inode(&mockFileInfo{sys: struct{}{}})
+	require.False(t, ok)
+	require.Equal(t, uint64(0), id)
+}
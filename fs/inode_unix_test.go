@@ -0,0 +1,24 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+//go:build unix
+
+package fs
+
+import (
+	sysFs "io/fs"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileIDInode(t *testing.T) {
+	osStat = func(name string) (sysFs.FileInfo, error) {
+		return &mockFileInfo{sys: &syscall.Stat_t{Ino: 42}}, nil
+	}
+	output, err := FileID("someFile")
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), output)
+}
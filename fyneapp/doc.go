@@ -0,0 +1,3 @@
+// Package fyneapp provides functionality to read the metadata
+// of Fyne applications declared in FyneApp.toml files.
+package fyneapp
@@ -0,0 +1,60 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package fyneapp
+
+import (
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pkg/errors"
+)
+
+// FileName is the name of the file where Fyne applications declare their metadata.
+const FileName = "FyneApp.toml"
+
+// tomlDecodeFile is a variable that holds the function
+// that decodes a TOML file.
+var tomlDecodeFile = toml.DecodeFile
+
+// Metadata holds the metadata of a Fyne application.
+type Metadata struct {
+	// Website is the website of the application.
+	Website string `toml:"Website"`
+
+	// Details holds the details of the application.
+	Details Details `toml:"Details"`
+}
+
+// Details holds the details of a Fyne application.
+type Details struct {
+	// Icon is the path to the icon of the application.
+	// After loading, it is relative to the current directory.
+	Icon string `toml:"Icon"`
+
+	// Name is the name of the application.
+	Name string `toml:"Name"`
+
+	// ID is the unique identifier of the application, e.g. "com.example.app".
+	ID string `toml:"ID"`
+
+	// Version is the release version of the application, e.g. "1.0.0".
+	Version string `toml:"Version"`
+
+	// Build is the build number of the application.
+	Build int `toml:"Build"`
+}
+
+// Load loads the metadata from the FyneApp.toml file at the given path.
+func Load(path string) (*Metadata, error) {
+	var metadata Metadata
+	if _, err := tomlDecodeFile(path, &metadata); err != nil {
+		return nil, errors.Wrapf(err, "error when decoding [%s]", path)
+	}
+	// the icon is declared relative to the FyneApp.toml file.
+	if metadata.Details.Icon != "" && !filepath.IsAbs(metadata.Details.Icon) {
+		metadata.Details.Icon = filepath.Join(filepath.Dir(path), metadata.Details.Icon)
+	}
+	return &metadata, nil
+}
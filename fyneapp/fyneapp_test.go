@@ -0,0 +1,91 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package fyneapp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	testCases := []struct {
+		name          string
+		contents      string
+		expected      func(dir string) *Metadata
+		expectedError func(path string) string
+	}{
+		{
+			name: "happy path",
+			contents: `Website = "https://example.com"
+
+[Details]
+  Icon = "Icon.png"
+  Name = "Greeter"
+  ID = "info.tiagomelo.greeter"
+  Version = "1.2.3"
+  Build = 42
+`,
+			expected: func(dir string) *Metadata {
+				return &Metadata{
+					Website: "https://example.com",
+					Details: Details{
+						Icon:    filepath.Join(dir, "Icon.png"),
+						Name:    "Greeter",
+						ID:      "info.tiagomelo.greeter",
+						Version: "1.2.3",
+						Build:   42,
+					},
+				}
+			},
+		},
+		{
+			name: "absolute icon path and missing values",
+			contents: `[Details]
+  Icon = "/icons/Icon.png"
+  Name = "Greeter"
+`,
+			expected: func(dir string) *Metadata {
+				return &Metadata{
+					Details: Details{
+						Icon: "/icons/Icon.png",
+						Name: "Greeter",
+					},
+				}
+			},
+		},
+		{
+			name:     "invalid toml",
+			contents: "[Details\nName = ",
+			expectedError: func(path string) string {
+				_, err := tomlDecodeFile(path, &Metadata{})
+				return errors.Wrapf(err, "error when decoding [%s]", path).Error()
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, FileName)
+			require.NoError(t, os.WriteFile(path, []byte(tc.contents), 0o644))
+
+			metadata, err := Load(path)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError(path), err.Error())
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.expectedError(path))
+				}
+				require.Equal(t, tc.expected(dir), metadata)
+			}
+		})
+	}
+}
@@ -0,0 +1,2 @@
+// Package gobuild provides a Go interface to the go build command.
+package gobuild
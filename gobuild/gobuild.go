@@ -0,0 +1,88 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package gobuild
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tiagomelo/macos-dmg-creator/syscall"
+)
+
+// filepathAbs is a variable that holds the function
+// that returns the absolute representation of a path.
+var filepathAbs = filepath.Abs
+
+// osCommandExecutorProvider is a variable that holds the function
+// that executes a command with arguments.
+var osCommandExecutorProvider osCommandExecutor = &defaultOsCommandExecutor{}
+
+// osCommandExecutor defines an interface for executing OS commands.
+type osCommandExecutor interface {
+	ExecCommandWithOptions(opts *syscall.CommandOptions, name string, arg ...string) (string, error)
+}
+
+// defaultOsCommandExecutor is the default implementation of osCommandExecutor.
+type defaultOsCommandExecutor struct{}
+
+// ExecCommandWithOptions executes a command with arguments and options.
+func (d *defaultOsCommandExecutor) ExecCommandWithOptions(opts *syscall.CommandOptions, name string, arg ...string) (string, error) {
+	return syscall.ExecCommandWithOptions(opts, name, arg...)
+}
+
+// Params is the input parameters for the Build function.
+type Params struct {
+	// PackageDir is the directory of the Go main package to build.
+	PackageDir string
+
+	// OutputPath is the path where the binary will be written to.
+	OutputPath string
+
+	// Arch is the target architecture (GOARCH), e.g. arm64 or amd64.
+	Arch string
+
+	// LDFlags are the flags passed to the Go linker.
+	LDFlags string
+
+	// Tags are the build tags.
+	Tags []string
+
+	// CGOEnabled indicates whether cgo is enabled.
+	CGOEnabled bool
+}
+
+// Build builds the Go main package for darwin with the given parameters.
+func Build(params *Params) error {
+	outputPath, err := filepathAbs(params.OutputPath)
+	if err != nil {
+		return errors.Wrapf(err, "error when resolving output path [%s]", params.OutputPath)
+	}
+	args := []string{"build", "-o", outputPath}
+	if params.LDFlags != "" {
+		args = append(args, "-ldflags", params.LDFlags)
+	}
+	if len(params.Tags) > 0 {
+		args = append(args, "-tags", strings.Join(params.Tags, ","))
+	}
+	args = append(args, ".")
+
+	cgoEnabled := "0"
+	if params.CGOEnabled {
+		cgoEnabled = "1"
+	}
+	opts := &syscall.CommandOptions{
+		Dir: params.PackageDir,
+		Env: []string{
+			"GOOS=darwin",
+			"GOARCH=" + params.Arch,
+			"CGO_ENABLED=" + cgoEnabled,
+		},
+	}
+	if _, err := osCommandExecutorProvider.ExecCommandWithOptions(opts, "go", args...); err != nil {
+		return errors.Wrapf(err, "error when building package [%s] for darwin/%s", params.PackageDir, params.Arch)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package gobuild
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/macos-dmg-creator/syscall"
+)
+
+func TestBuild(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		params                *Params
+		mockFilepathAbs       func(path string) (string, error)
+		mockOsCommandExecutor func() *mockOsCommandExecutor
+		expectedArgs          []string
+		expectedOpts          *syscall.CommandOptions
+		expectedError         error
+	}{
+		{
+			name: "happy path",
+			params: &Params{
+				PackageDir: "sampleapp/cmd",
+				OutputPath: "build/arm64/SampleApp",
+				Arch:       "arm64",
+			},
+			mockFilepathAbs: func(path string) (string, error) {
+				return "/abs/" + path, nil
+			},
+			mockOsCommandExecutor: func() *mockOsCommandExecutor {
+				return &mockOsCommandExecutor{}
+			},
+			expectedArgs: []string{"build", "-o", "/abs/build/arm64/SampleApp", "."},
+			expectedOpts: &syscall.CommandOptions{
+				Dir: "sampleapp/cmd",
+				Env: []string{"GOOS=darwin", "GOARCH=arm64", "CGO_ENABLED=0"},
+			},
+		},
+		{
+			name: "happy path with ldflags, tags and cgo",
+			params: &Params{
+				PackageDir: "sampleapp/cmd",
+				OutputPath: "build/amd64/SampleApp",
+				Arch:       "amd64",
+				LDFlags:    "-s -w -X main.version=1.0.0",
+				Tags:       []string{"release", "netgo"},
+				CGOEnabled: true,
+			},
+			mockFilepathAbs: func(path string) (string, error) {
+				return "/abs/" + path, nil
+			},
+			mockOsCommandExecutor: func() *mockOsCommandExecutor {
+				return &mockOsCommandExecutor{}
+			},
+			expectedArgs: []string{"build", "-o", "/abs/build/amd64/SampleApp", "-ldflags", "-s -w -X main.version=1.0.0", "-tags", "release,netgo", "."},
+			expectedOpts: &syscall.CommandOptions{
+				Dir: "sampleapp/cmd",
+				Env: []string{"GOOS=darwin", "GOARCH=amd64", "CGO_ENABLED=1"},
+			},
+		},
+		{
+			name: "error when resolving output path",
+			params: &Params{
+				PackageDir: "sampleapp/cmd",
+				OutputPath: "build/arm64/SampleApp",
+				Arch:       "arm64",
+			},
+			mockFilepathAbs: func(path string) (string, error) {
+				return "", errors.New("some error")
+			},
+			mockOsCommandExecutor: func() *mockOsCommandExecutor {
+				return &mockOsCommandExecutor{}
+			},
+			expectedError: errors.New("error when resolving output path [build/arm64/SampleApp]: some error"),
+		},
+		{
+			name: "error when building",
+			params: &Params{
+				PackageDir: "sampleapp/cmd",
+				OutputPath: "build/arm64/SampleApp",
+				Arch:       "arm64",
+			},
+			mockFilepathAbs: func(path string) (string, error) {
+				return "/abs/" + path, nil
+			},
+			mockOsCommandExecutor: func() *mockOsCommandExecutor {
+				return &mockOsCommandExecutor{
+					err: errors.New("some error"),
+				}
+			},
+			expectedError: errors.New("error when building package [sampleapp/cmd] for darwin/arm64: some error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filepathAbs = tc.mockFilepathAbs
+			mockOsCommandExecutor := tc.mockOsCommandExecutor()
+			osCommandExecutorProvider = mockOsCommandExecutor
+			err := Build(tc.params)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.expectedError)
+				}
+				require.Equal(t, "go", mockOsCommandExecutor.name)
+				require.Equal(t, tc.expectedArgs, mockOsCommandExecutor.args)
+				require.Equal(t, tc.expectedOpts, mockOsCommandExecutor.opts)
+			}
+		})
+	}
+}
+
+type mockOsCommandExecutor struct {
+	name string
+	args []string
+	opts *syscall.CommandOptions
+	err  error
+}
+
+func (m *mockOsCommandExecutor) ExecCommandWithOptions(opts *syscall.CommandOptions, name string, arg ...string) (string, error) {
+	m.opts = opts
+	m.name = name
+	m.args = arg
+	return "", m.err
+}
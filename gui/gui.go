@@ -5,7 +5,10 @@
 package gui
 
 import (
+	"fmt"
 	"image/color"
+	"strconv"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
@@ -70,6 +73,9 @@ func (g *gui) setupContent() fyne.CanvasObject {
 		appBinaryPlaceholder       = "/path/to/dir"
 		appBundleIDLabel           = "application bundle id *"
 		appBundleIDPlaceholder     = "com.example.app"
+		formatLabel                = "DMG format"
+		zlibLevelLabel             = "zlib level"
+		defaultZlibLevel           = "default"
 		chooseLabel                = "choose..."
 		requiredFielsLabel         = "* required fields"
 	)
@@ -168,6 +174,24 @@ func (g *gui) setupContent() fyne.CanvasObject {
 	appBundleIDEntry.SetPlaceHolder(appBundleIDPlaceholder)
 	appBundleIDEntry.Validator = noSpaces
 
+	// ==========================
+	// DMG format
+	// ==========================
+
+	zlibLevelSelect := widget.NewSelect([]string{defaultZlibLevel, "1", "2", "3", "4", "5", "6", "7", "8", "9"}, nil)
+	zlibLevelSelect.SetSelected(defaultZlibLevel)
+
+	// the zlib level only applies to UDZO DMGs.
+	formatSelect := widget.NewSelect([]string{"UDZO", "UDBZ", "ULFO", "ULMO", "UDRO", "UDRW"}, func(format string) {
+		if format == "UDZO" {
+			zlibLevelSelect.Enable()
+			return
+		}
+		zlibLevelSelect.SetSelected(defaultZlibLevel)
+		zlibLevelSelect.Disable()
+	})
+	formatSelect.SetSelected("UDZO")
+
 	// ==========================
 	// Progress bar dialog
 	// ==========================
@@ -197,6 +221,8 @@ func (g *gui) setupContent() fyne.CanvasObject {
 			{Text: dmgOutputLabel, Widget: dmgOutputEntry},
 			{Widget: chooseDMGOutputPathButton},
 			{Text: appBundleIDLabel, Widget: appBundleIDEntry},
+			{Text: formatLabel, Widget: formatSelect},
+			{Text: zlibLevelLabel, Widget: zlibLevelSelect},
 			{Widget: widget.NewLabelWithStyle(requiredFielsLabel, fyne.TextAlignCenter, fyne.TextStyle{Italic: true})},
 		},
 	}
@@ -209,13 +235,18 @@ func (g *gui) setupContent() fyne.CanvasObject {
 		form.Disable()
 		progressBarDialog.Show()
 
+		// the zlib level is left at 0, the default, unless one is selected.
+		zlibLevel, _ := strconv.Atoi(zlibLevelSelect.Selected)
+
 		go func() {
-			_, err := dmg.Create(&dmg.CreateParams{
+			result, err := dmg.Create(&dmg.CreateParams{
 				AppName:          dmgNameEntry.Text,
 				AppBinaryPath:    appBinaryEntry.Text,
 				BundleIdentifier: appBundleIDEntry.Text,
 				IconPath:         dmgIconEntry.Text,
 				OutputDir:        dmgOutputEntry.Text,
+				Format:           formatSelect.Selected,
+				ZlibLevel:        zlibLevel,
 			})
 			if err != nil {
 				progressBarDialog.Hide()
@@ -225,7 +256,14 @@ func (g *gui) setupContent() fyne.CanvasObject {
 			}
 
 			progressBarDialog.Hide()
-			dialog.ShowInformation("Success", "DMG was successfully created!", g.fyneWindow)
+			summary := fmt.Sprintf("%s, %.1f MB", result.Format, float64(result.Size)/(1<<20))
+			if result.Compressed() {
+				summary += fmt.Sprintf(", compression ratio %.2f:1", result.CompressionRatio())
+			}
+			dialog.ShowInformation("Success", fmt.Sprintf(
+				"DMG was successfully created!\n\n%s, converted in %s",
+				summary, result.ConversionDuration.Round(time.Millisecond),
+			), g.fyneWindow)
 			form.Enable()
 		}()
 
@@ -7,6 +7,7 @@ package hdiutil
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/pkg/errors"
@@ -25,6 +26,7 @@ var osCommandExecutorProvider osCommandExecutor = &defaultOsCommandExecutor{}
 // osCommandExecutor defines an interface for executing OS commands.
 type osCommandExecutor interface {
 	ExecCommand(name string, arg ...string) (string, error)
+	ExecCommandWithOptions(opts *syscall.CommandOptions, name string, arg ...string) (string, error)
 }
 
 // defaultOsCommandExecutor is the default implementation of osCommandExecutor.
@@ -35,6 +37,11 @@ func (d *defaultOsCommandExecutor) ExecCommand(name string, arg ...string) (stri
 	return syscall.ExecCommand(name, arg...)
 }
 
+// ExecCommandWithOptions executes a command with arguments and options.
+func (d *defaultOsCommandExecutor) ExecCommandWithOptions(opts *syscall.CommandOptions, name string, arg ...string) (string, error) {
+	return syscall.ExecCommandWithOptions(opts, name, arg...)
+}
+
 // CreateDMG creates a dmg file.
 func CreateDMG(size, fs, volName, layout, output string) error {
 	if _, err := osCommandExecutorProvider.ExecCommand("hdiutil", "create", "-size",
@@ -93,11 +100,87 @@ func UnmountDMG(volName string) error {
 	return nil
 }
 
-// ConvertDMG converts a dmg file.
-// It aims to convert it to a compressed format.
-func ConvertDMG(dmgPath, dmgOutputFileName string) error {
-	if _, err := osCommandExecutorProvider.ExecCommand("hdiutil", "convert", dmgPath, "-format", "UDZO", "-o", dmgOutputFileName); err != nil {
+// AttachDMG attaches a dmg file read-only at the given mount point,
+// without showing it in Finder nor opening it.
+func AttachDMG(dmgPath, mountPoint string) error {
+	if _, err := osCommandExecutorProvider.ExecCommand("hdiutil", "attach", dmgPath, "-readonly",
+		"-nobrowse", "-noautoopen", "-mountpoint", mountPoint); err != nil {
+		return errors.Wrapf(err, "error when attaching dmg %s at %s", dmgPath, mountPoint)
+	}
+	return nil
+}
+
+// DetachDMG detaches the dmg file attached at the given mount point.
+func DetachDMG(mountPoint string) error {
+	if _, err := osCommandExecutorProvider.ExecCommand("hdiutil", "detach", mountPoint); err != nil {
+		return errors.Wrapf(err, "error when detaching dmg at %s", mountPoint)
+	}
+	return nil
+}
+
+// ConvertOptions are the options of ConvertDMG and CreateDMGFromFolder.
+type ConvertOptions struct {
+	// Format is the format of the converted dmg file, e.g. UDZO.
+	Format string
+
+	// ZlibLevel, from 1 to 9, is the compression level of UDZO images;
+	// when 0, the default level of hdiutil is used.
+	ZlibLevel int
+
+	// Encryption is the encryption of the converted dmg file,
+	// AES-128 or AES-256; when empty, the dmg file is not encrypted.
+	Encryption string
+
+	// Passphrase is the passphrase of the encrypted dmg file.
+	// It is fed to hdiutil through its standard input, so that it
+	// never shows up in the arguments of the command.
+	Passphrase []byte
+}
+
+// VerifyDMG verifies the checksums of a dmg file.
+func VerifyDMG(dmgPath string) error {
+	if _, err := osCommandExecutorProvider.ExecCommand("hdiutil", "verify", dmgPath); err != nil {
+		return errors.Wrapf(err, "error when verifying dmg %s", dmgPath)
+	}
+	return nil
+}
+
+// CreateDMGFromFolder creates a dmg file in one step from the contents of srcFolder,
+// in the format given in the options, without mounting it. Its volume is named volName,
+// and it is encrypted when an encryption is given.
+func CreateDMGFromFolder(srcFolder, fs, volName, layout, output string, opts *ConvertOptions) error {
+	args := []string{"create", "-srcfolder", srcFolder, "-fs", fs, "-volname", volName, "-layout", layout, "-format", opts.Format}
+	args, cmdOpts := appendConvertOptions(args, opts)
+	args = append(args, "-o", output)
+	if _, err := osCommandExecutorProvider.ExecCommandWithOptions(cmdOpts, "hdiutil", args...); err != nil {
+		return errors.Wrapf(err, "error when creating dmg with name %s from folder %s", volName, srcFolder)
+	}
+	return nil
+}
+
+// ConvertDMG converts a dmg file to the format given in the options,
+// encrypting it when an encryption is given.
+func ConvertDMG(dmgPath, dmgOutputFileName string, opts *ConvertOptions) error {
+	args, cmdOpts := appendConvertOptions([]string{"convert", dmgPath, "-format", opts.Format}, opts)
+	args = append(args, "-o", dmgOutputFileName)
+	if _, err := osCommandExecutorProvider.ExecCommandWithOptions(cmdOpts, "hdiutil", args...); err != nil {
 		return errors.Wrapf(err, "error when converting dmg file %s", dmgPath)
 	}
 	return nil
 }
+
+// appendConvertOptions appends the arguments of the zlib level and of the encryption
+// to args, and returns them along with the options of the command, which feed the
+// passphrase to hdiutil through its standard input.
+func appendConvertOptions(args []string, opts *ConvertOptions) ([]string, *syscall.CommandOptions) {
+	if opts.ZlibLevel != 0 {
+		args = append(args, "-imagekey", "zlib-level="+strconv.Itoa(opts.ZlibLevel))
+	}
+	cmdOpts := &syscall.CommandOptions{}
+	if opts.Encryption != "" {
+		args = append(args, "-encryption", opts.Encryption, "-stdinpass")
+		// hdiutil reads the passphrase up to a null byte or the end of the input.
+		cmdOpts.Stdin = opts.Passphrase
+	}
+	return args, cmdOpts
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/macos-dmg-creator/syscall"
 )
 
 func TestCreateDmg(t *testing.T) {
@@ -169,17 +170,48 @@ func TestUnmountDmg(t *testing.T) {
 func TestConvertDmg(t *testing.T) {
 	testCases := []struct {
 		name                  string
+		opts                  *ConvertOptions
 		mockOsCommandExecutor func() *mockOsCommandExecutor
+		expectedArgs          []string
+		expectedStdin         []byte
 		expectedError         error
 	}{
 		{
 			name: "happy path",
+			opts: &ConvertOptions{Format: "UDZO"},
 			mockOsCommandExecutor: func() *mockOsCommandExecutor {
 				return &mockOsCommandExecutor{}
 			},
+			expectedArgs: []string{"hdiutil", "convert", "Test.dmg", "-format", "UDZO", "-o", "Test-converted.dmg"},
+		},
+		{
+			name: "zlib level",
+			opts: &ConvertOptions{Format: "UDZO", ZlibLevel: 9},
+			mockOsCommandExecutor: func() *mockOsCommandExecutor {
+				return &mockOsCommandExecutor{}
+			},
+			expectedArgs: []string{"hdiutil", "convert", "Test.dmg", "-format", "UDZO", "-imagekey", "zlib-level=9", "-o", "Test-converted.dmg"},
+		},
+		{
+			name: "other format",
+			opts: &ConvertOptions{Format: "ULFO"},
+			mockOsCommandExecutor: func() *mockOsCommandExecutor {
+				return &mockOsCommandExecutor{}
+			},
+			expectedArgs: []string{"hdiutil", "convert", "Test.dmg", "-format", "ULFO", "-o", "Test-converted.dmg"},
+		},
+		{
+			name: "encryption",
+			opts: &ConvertOptions{Format: "UDZO", Encryption: "AES-256", Passphrase: []byte("secret")},
+			mockOsCommandExecutor: func() *mockOsCommandExecutor {
+				return &mockOsCommandExecutor{}
+			},
+			expectedArgs:  []string{"hdiutil", "convert", "Test.dmg", "-format", "UDZO", "-encryption", "AES-256", "-stdinpass", "-o", "Test-converted.dmg"},
+			expectedStdin: []byte("secret"),
 		},
 		{
 			name: "error",
+			opts: &ConvertOptions{Format: "UDZO"},
 			mockOsCommandExecutor: func() *mockOsCommandExecutor {
 				return &mockOsCommandExecutor{
 					err: errors.New("some error"),
@@ -188,10 +220,200 @@ func TestConvertDmg(t *testing.T) {
 			expectedError: errors.New("error when converting dmg file Test.dmg: some error"),
 		},
 	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockOsCommandExecutor := tc.mockOsCommandExecutor()
+			osCommandExecutorProvider = mockOsCommandExecutor
+			err := ConvertDMG("Test.dmg", "Test-converted.dmg", tc.opts)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.expectedError)
+				}
+				require.Equal(t, tc.expectedArgs, mockOsCommandExecutor.args)
+				require.Equal(t, tc.expectedStdin, mockOsCommandExecutor.stdin)
+			}
+		})
+	}
+}
+
+func TestCreateDMGFromFolder(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		opts                  *ConvertOptions
+		mockOsCommandExecutor func() *mockOsCommandExecutor
+		expectedArgs          []string
+		expectedStdin         []byte
+		expectedError         error
+	}{
+		{
+			name: "happy path",
+			opts: &ConvertOptions{Format: "UDZO"},
+			mockOsCommandExecutor: func() *mockOsCommandExecutor {
+				return &mockOsCommandExecutor{}
+			},
+			expectedArgs: []string{"hdiutil", "create", "-srcfolder", "staging/Test", "-fs", "APFS", "-volname", "Test", "-layout", "GPTSPUD", "-format", "UDZO", "-o", "Test.dmg"},
+		},
+		{
+			name: "zlib level and encryption",
+			opts: &ConvertOptions{Format: "UDZO", ZlibLevel: 9, Encryption: "AES-128", Passphrase: []byte("secret")},
+			mockOsCommandExecutor: func() *mockOsCommandExecutor {
+				return &mockOsCommandExecutor{}
+			},
+			expectedArgs: []string{"hdiutil", "create", "-srcfolder", "staging/Test", "-fs", "APFS", "-volname", "Test", "-layout", "GPTSPUD", "-format", "UDZO",
+				"-imagekey", "zlib-level=9", "-encryption", "AES-128", "-stdinpass", "-o", "Test.dmg"},
+			expectedStdin: []byte("secret"),
+		},
+		{
+			name: "error",
+			opts: &ConvertOptions{Format: "UDZO"},
+			mockOsCommandExecutor: func() *mockOsCommandExecutor {
+				return &mockOsCommandExecutor{
+					err: errors.New("some error"),
+				}
+			},
+			expectedError: errors.New("error when creating dmg with name Test from folder staging/Test: some error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockOsCommandExecutor := tc.mockOsCommandExecutor()
+			osCommandExecutorProvider = mockOsCommandExecutor
+			err := CreateDMGFromFolder("staging/Test", "APFS", "Test", "GPTSPUD", "Test.dmg", tc.opts)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.expectedError)
+				}
+				require.Equal(t, tc.expectedArgs, mockOsCommandExecutor.args)
+				require.Equal(t, tc.expectedStdin, mockOsCommandExecutor.stdin)
+			}
+		})
+	}
+}
+
+func TestAttachDMG(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		mockOsCommandExecutor func() *mockOsCommandExecutor
+		expectedArgs          []string
+		expectedError         error
+	}{
+		{
+			name: "happy path",
+			mockOsCommandExecutor: func() *mockOsCommandExecutor {
+				return &mockOsCommandExecutor{}
+			},
+			expectedArgs: []string{"hdiutil", "attach", "test.dmg", "-readonly", "-nobrowse", "-noautoopen", "-mountpoint", "/tmp/mnt"},
+		},
+		{
+			name: "error",
+			mockOsCommandExecutor: func() *mockOsCommandExecutor {
+				return &mockOsCommandExecutor{
+					err: errors.New("some error"),
+				}
+			},
+			expectedError: errors.New("error when attaching dmg test.dmg at /tmp/mnt: some error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockOsCommandExecutor := tc.mockOsCommandExecutor()
+			osCommandExecutorProvider = mockOsCommandExecutor
+			err := AttachDMG("test.dmg", "/tmp/mnt")
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.expectedError)
+				}
+				require.Equal(t, tc.expectedArgs, mockOsCommandExecutor.args)
+			}
+		})
+	}
+}
+
+func TestVerifyDMG(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		mockOsCommandExecutor func() *mockOsCommandExecutor
+		expectedArgs          []string
+		expectedError         error
+	}{
+		{
+			name: "happy path",
+			mockOsCommandExecutor: func() *mockOsCommandExecutor {
+				return &mockOsCommandExecutor{}
+			},
+			expectedArgs: []string{"hdiutil", "verify", "test.dmg"},
+		},
+		{
+			name: "error",
+			mockOsCommandExecutor: func() *mockOsCommandExecutor {
+				return &mockOsCommandExecutor{
+					err: errors.New("some error"),
+				}
+			},
+			expectedError: errors.New("error when verifying dmg test.dmg: some error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockOsCommandExecutor := tc.mockOsCommandExecutor()
+			osCommandExecutorProvider = mockOsCommandExecutor
+			err := VerifyDMG("test.dmg")
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.expectedError)
+				}
+				require.Equal(t, tc.expectedArgs, mockOsCommandExecutor.args)
+			}
+		})
+	}
+}
+
+func TestDetachDMG(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		mockOsCommandExecutor func() *mockOsCommandExecutor
+		expectedError         error
+	}{
+		{
+			name: "happy path",
+			mockOsCommandExecutor: func() *mockOsCommandExecutor {
+				return &mockOsCommandExecutor{}
+			},
+		},
+		{
+			name: "error",
+			mockOsCommandExecutor: func() *mockOsCommandExecutor {
+				return &mockOsCommandExecutor{
+					err: errors.New("some error"),
+				}
+			},
+			expectedError: errors.New("error when detaching dmg at /tmp/mnt: some error"),
+		},
+	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			osCommandExecutorProvider = tc.mockOsCommandExecutor()
-			err := ConvertDMG("Test.dmg", "Test-converted.dmg")
+			err := DetachDMG("/tmp/mnt")
 			if err != nil {
 				if tc.expectedError == nil {
 					t.Fatalf(`expected no error, got "%v"`, err)
@@ -207,10 +429,19 @@ func TestConvertDmg(t *testing.T) {
 }
 
 type mockOsCommandExecutor struct {
-	err error
+	err   error
+	args  []string
+	stdin []byte
 }
 
 func (m *mockOsCommandExecutor) ExecCommand(name string, arg ...string) (string, error) {
+	m.args = append([]string{name}, arg...)
+	return "", m.err
+}
+
+func (m *mockOsCommandExecutor) ExecCommandWithOptions(opts *syscall.CommandOptions, name string, arg ...string) (string, error) {
+	m.args = append([]string{name}, arg...)
+	m.stdin = opts.Stdin
 	return "", m.err
 }
 
@@ -0,0 +1,4 @@
+// Package hidpi provides functionality to read the size of images and to combine
+// the 1x and 2x versions of an image into a multi-resolution TIFF file, which
+// macOS shows sharp on both standard and Retina displays.
+package hidpi
@@ -0,0 +1,220 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package hidpi
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"image"
+	"image/draw"
+	_ "image/gif"  // register the GIF format.
+	_ "image/jpeg" // register the JPEG format.
+	_ "image/png"  // register the PNG format.
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	_ "golang.org/x/image/tiff" // register the TIFF format.
+)
+
+// for ease of unit testing.
+var (
+	osOpen      = os.Open
+	osWriteFile = os.WriteFile
+)
+
+// baseResolution is the resolution, in dots per inch, of 1x images.
+const baseResolution = 72
+
+// TIFF tags, field types and values written by Encode.
+const (
+	tagImageWidth      = 256
+	tagImageLength     = 257
+	tagBitsPerSample   = 258
+	tagCompression     = 259
+	tagPhotometric     = 262
+	tagStripOffsets    = 273
+	tagSamplesPerPixel = 277
+	tagRowsPerStrip    = 278
+	tagStripByteCounts = 279
+	tagXResolution     = 282
+	tagYResolution     = 283
+	tagPlanarConfig    = 284
+	tagResolutionUnit  = 296
+	tagExtraSamples    = 338
+
+	typeShort    = 3
+	typeLong     = 4
+	typeRational = 5
+
+	compressionDeflate            = 8
+	photometricRGB                = 2
+	resolutionUnitInch            = 2
+	extraSamplesUnassociatedAlpha = 2
+)
+
+// Page is an image of a multi-resolution TIFF file.
+type Page struct {
+	// Image is the image of the page.
+	Image image.Image
+
+	// Resolution is the resolution of the page, in dots per inch:
+	// 72 for 1x images and 144 for 2x images.
+	Resolution int
+}
+
+// Size returns the size, in pixels, of the PNG, JPEG, GIF or TIFF image at the given path.
+func Size(path string) (width, height int, err error) {
+	f, err := osOpen(path)
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "error when opening [%s]", path)
+	}
+	defer f.Close()
+	config, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "error when reading the size of [%s]", path)
+	}
+	return config.Width, config.Height, nil
+}
+
+// CombineTIFF writes to outputPath a TIFF file holding the image at imagePath
+// and its 2x version at image2xPath, which must be exactly twice as large.
+func CombineTIFF(outputPath, imagePath, image2xPath string) error {
+	img, err := decode(imagePath)
+	if err != nil {
+		return err
+	}
+	img2x, err := decode(image2xPath)
+	if err != nil {
+		return err
+	}
+	size, size2x := img.Bounds().Size(), img2x.Bounds().Size()
+	if size2x != size.Mul(2) {
+		return errors.Errorf("[%s] is %dx%d, but it must be twice the size of [%s], %dx%d", image2xPath, size2x.X, size2x.Y, imagePath, 2*size.X, 2*size.Y)
+	}
+	var buf bytes.Buffer
+	if err := Encode(&buf, []Page{
+		{Image: img, Resolution: baseResolution},
+		{Image: img2x, Resolution: 2 * baseResolution},
+	}); err != nil {
+		return errors.Wrapf(err, "error when encoding [%s]", outputPath)
+	}
+	if err := osWriteFile(outputPath, buf.Bytes(), 0o644); err != nil {
+		return errors.Wrapf(err, "error when writing [%s]", outputPath)
+	}
+	return nil
+}
+
+// decode decodes the PNG, JPEG, GIF or TIFF image at the given path.
+func decode(path string) (image.Image, error) {
+	f, err := osOpen(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when opening [%s]", path)
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when decoding [%s]", path)
+	}
+	return img, nil
+}
+
+// Encode writes the pages as a little-endian TIFF file, each one in
+// its own image file directory, as deflate-compressed RGBA pixels.
+func Encode(w io.Writer, pages []Page) error {
+	if len(pages) == 0 {
+		return errors.New("no pages to encode")
+	}
+	buf := []byte("II*\x00\x00\x00\x00\x00")
+	// the offset of the first directory follows the magic number.
+	nextDirOffset := 4
+	for i, page := range pages {
+		if page.Resolution <= 0 {
+			return errors.Errorf("page %d has an invalid resolution of %d dots per inch", i, page.Resolution)
+		}
+		bounds := page.Image.Bounds()
+		pixels, err := compress(page.Image)
+		if err != nil {
+			return errors.Wrapf(err, "error when compressing page %d", i)
+		}
+
+		stripOffset := len(buf)
+		buf = pad(append(buf, pixels...))
+		bitsPerSampleOffset := len(buf)
+		for range 4 {
+			buf = binary.LittleEndian.AppendUint16(buf, 8)
+		}
+		resolutionOffset := len(buf)
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(page.Resolution))
+		buf = binary.LittleEndian.AppendUint32(buf, 1)
+
+		binary.LittleEndian.PutUint32(buf[nextDirOffset:], uint32(len(buf)))
+		entries := []struct {
+			tag, fieldType uint16
+			count, value   uint32
+		}{
+			{tagImageWidth, typeLong, 1, uint32(bounds.Dx())},
+			{tagImageLength, typeLong, 1, uint32(bounds.Dy())},
+			{tagBitsPerSample, typeShort, 4, uint32(bitsPerSampleOffset)},
+			{tagCompression, typeShort, 1, compressionDeflate},
+			{tagPhotometric, typeShort, 1, photometricRGB},
+			{tagStripOffsets, typeLong, 1, uint32(stripOffset)},
+			{tagSamplesPerPixel, typeShort, 1, 4},
+			{tagRowsPerStrip, typeLong, 1, uint32(bounds.Dy())},
+			{tagStripByteCounts, typeLong, 1, uint32(len(pixels))},
+			{tagXResolution, typeRational, 1, uint32(resolutionOffset)},
+			{tagYResolution, typeRational, 1, uint32(resolutionOffset)},
+			{tagPlanarConfig, typeShort, 1, 1},
+			{tagResolutionUnit, typeShort, 1, resolutionUnitInch},
+			{tagExtraSamples, typeShort, 1, extraSamplesUnassociatedAlpha},
+		}
+		buf = binary.LittleEndian.AppendUint16(buf, uint16(len(entries)))
+		for _, entry := range entries {
+			buf = binary.LittleEndian.AppendUint16(buf, entry.tag)
+			buf = binary.LittleEndian.AppendUint16(buf, entry.fieldType)
+			buf = binary.LittleEndian.AppendUint32(buf, entry.count)
+			// values that fit in four bytes are stored in the entry,
+			// left-justified, and the others are stored at an offset.
+			if entry.fieldType == typeShort && entry.count == 1 {
+				buf = binary.LittleEndian.AppendUint16(buf, uint16(entry.value))
+				buf = binary.LittleEndian.AppendUint16(buf, 0)
+			} else {
+				buf = binary.LittleEndian.AppendUint32(buf, entry.value)
+			}
+		}
+		nextDirOffset = len(buf)
+		buf = binary.LittleEndian.AppendUint32(buf, 0)
+	}
+	_, err := w.Write(buf)
+	return err
+}
+
+// compress returns the non-premultiplied RGBA pixels of the image, compressed with zlib.
+func compress(img image.Image) ([]byte, error) {
+	bounds := img.Bounds()
+	nrgba, ok := img.(*image.NRGBA)
+	if !ok || nrgba.Stride != 4*bounds.Dx() {
+		nrgba = image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		draw.Draw(nrgba, nrgba.Bounds(), img, bounds.Min, draw.Src)
+	}
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	if _, err := zw.Write(nrgba.Pix[:4*bounds.Dx()*bounds.Dy()]); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// pad pads the data to an even length, since TIFF offsets are word-aligned.
+func pad(data []byte) []byte {
+	if len(data)%2 != 0 {
+		return append(data, 0)
+	}
+	return data
+}
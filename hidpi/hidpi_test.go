@@ -0,0 +1,171 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package hidpi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/tiff"
+)
+
+func TestSize(t *testing.T) {
+	dir := t.TempDir()
+	testCases := []struct {
+		name           string
+		path           string
+		expectedWidth  int
+		expectedHeight int
+		expectedError  string
+	}{
+		{
+			name:           "png",
+			path:           writePNG(t, dir, "background.png", 660, 400),
+			expectedWidth:  660,
+			expectedHeight: 400,
+		},
+		{
+			name:          "not an image",
+			path:          writeFile(t, dir, "background.txt", "hello"),
+			expectedError: "error when reading the size of [" + filepath.Join(dir, "background.txt") + "]: image: unknown format",
+		},
+		{
+			name:          "missing file",
+			path:          filepath.Join(dir, "missing.png"),
+			expectedError: "error when opening [" + filepath.Join(dir, "missing.png") + "]: open " + filepath.Join(dir, "missing.png") + ": no such file or directory",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			width, height, err := Size(tc.path)
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedWidth, width)
+			require.Equal(t, tc.expectedHeight, height)
+		})
+	}
+}
+
+func TestCombineTIFF(t *testing.T) {
+	dir := t.TempDir()
+	testCases := []struct {
+		name          string
+		imagePath     string
+		image2xPath   string
+		expectedError string
+	}{
+		{
+			name:        "happy path",
+			imagePath:   writePNG(t, dir, "background.png", 3, 2),
+			image2xPath: writePNG(t, dir, "background@2x.png", 6, 4),
+		},
+		{
+			name:          "2x image of the wrong size",
+			imagePath:     writePNG(t, dir, "small.png", 3, 2),
+			image2xPath:   writePNG(t, dir, "small@2x.png", 6, 5),
+			expectedError: "[" + filepath.Join(dir, "small@2x.png") + "] is 6x5, but it must be twice the size of [" + filepath.Join(dir, "small.png") + "], 6x4",
+		},
+		{
+			name:          "not an image",
+			imagePath:     writePNG(t, dir, "valid.png", 3, 2),
+			image2xPath:   writeFile(t, dir, "invalid.png", "hello"),
+			expectedError: "error when decoding [" + filepath.Join(dir, "invalid.png") + "]: image: unknown format",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			outputPath := filepath.Join(dir, "background.tiff")
+			err := CombineTIFF(outputPath, tc.imagePath, tc.image2xPath)
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+
+			data, err := os.ReadFile(outputPath)
+			require.NoError(t, err)
+			// the first page is the 1x image, as read by any TIFF decoder.
+			img, err := tiff.Decode(bytes.NewReader(data))
+			require.NoError(t, err)
+			require.Equal(t, image.Rect(0, 0, 3, 2), img.Bounds())
+			require.Equal(t, color.NRGBA{R: 2, G: 1, B: 200, A: 128}, color.NRGBAModel.Convert(img.At(2, 1)))
+
+			require.Equal(t, []pageInfo{
+				{width: 3, height: 2, resolution: 72},
+				{width: 6, height: 4, resolution: 144},
+			}, readPages(t, data))
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	err := Encode(&bytes.Buffer{}, nil)
+	require.EqualError(t, err, "no pages to encode")
+
+	err = Encode(&bytes.Buffer{}, []Page{{Image: image.NewNRGBA(image.Rect(0, 0, 1, 1))}})
+	require.EqualError(t, err, "page 0 has an invalid resolution of 0 dots per inch")
+}
+
+// pageInfo is the size and resolution of a page of a TIFF file.
+type pageInfo struct {
+	width, height, resolution uint32
+}
+
+// readPages follows the chain of image file directories of the TIFF file.
+func readPages(t *testing.T, data []byte) []pageInfo {
+	t.Helper()
+	require.Equal(t, "II*\x00", string(data[:4]))
+	var pages []pageInfo
+	for offset := binary.LittleEndian.Uint32(data[4:]); offset != 0; {
+		var page pageInfo
+		count := int(binary.LittleEndian.Uint16(data[offset:]))
+		for i := range count {
+			entry := data[int(offset)+2+12*i:]
+			value := binary.LittleEndian.Uint32(entry[8:])
+			switch binary.LittleEndian.Uint16(entry) {
+			case tagImageWidth:
+				page.width = value
+			case tagImageLength:
+				page.height = value
+			case tagXResolution:
+				page.resolution = binary.LittleEndian.Uint32(data[value:]) / binary.LittleEndian.Uint32(data[value+4:])
+			}
+		}
+		pages = append(pages, page)
+		offset = binary.LittleEndian.Uint32(data[int(offset)+2+12*count:])
+	}
+	return pages
+}
+
+// writePNG writes a PNG image of the given size, whose pixels encode their coordinates.
+func writePNG(t *testing.T, dir, name string, width, height int) string {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 200, A: 128})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return writeFile(t, dir, name, buf.String())
+}
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
@@ -0,0 +1,3 @@
+// Package lint provides functionality to check existing macOS application
+// bundles, and the ones inside disk images, for common packaging mistakes.
+package lint
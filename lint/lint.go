@@ -0,0 +1,332 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package lint
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/pkg/errors"
+	"github.com/tiagomelo/macos-dmg-creator/bundle"
+	"github.com/tiagomelo/macos-dmg-creator/hdiutil"
+	"github.com/tiagomelo/macos-dmg-creator/macho"
+	"github.com/tiagomelo/macos-dmg-creator/plist"
+)
+
+// for ease of unit testing.
+var (
+	machoInspect     = macho.Inspect
+	hdiutilAttachDMG = hdiutil.AttachDMG
+	hdiutilDetachDMG = hdiutil.DetachDMG
+	osMkdirTemp      = os.MkdirTemp
+)
+
+const (
+	resourcesDir = "Contents/Resources"
+	dmgExtension = ".dmg"
+	iconFileExt  = ".icns"
+	dsStoreFile  = ".DS_Store"
+)
+
+// bundleIdentifierPattern matches reverse-DNS bundle identifiers, e.g. com.example.myapp.
+var bundleIdentifierPattern = regexp.MustCompile(`^[A-Za-z0-9-]+(\.[A-Za-z0-9-]+)+$`)
+
+// Severity is the severity of a finding.
+type Severity int
+
+const (
+	// Error is a mistake that breaks the application or its distribution.
+	Error Severity = iota
+
+	// Warning is a mistake that does not break the application but should be fixed.
+	Warning
+)
+
+// String returns the name of the severity.
+func (s Severity) String() string {
+	if s == Warning {
+		return "warning"
+	}
+	return "error"
+}
+
+// Finding is a mistake found in an application bundle.
+type Finding struct {
+	// Severity is the severity of the mistake.
+	Severity Severity
+
+	// Path is the path of the bundle or file the mistake was found in.
+	Path string
+
+	// Message describes the mistake.
+	Message string
+
+	// Hint describes how to fix the mistake.
+	Hint string
+}
+
+// String returns the finding as a line of text, followed by its hint.
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: [%s] %s (hint: %s)", f.Severity, f.Path, f.Message, f.Hint)
+}
+
+// Path checks the application bundle (.app) or the disk image (.dmg) at the given path.
+func Path(path string) ([]Finding, error) {
+	switch filepath.Ext(path) {
+	case bundle.Extension:
+		return Bundle(path)
+	case dmgExtension:
+		return DMG(path)
+	}
+	return nil, errors.Errorf("[%s] is neither an application bundle (%s) nor a disk image (%s)", path, bundle.Extension, dmgExtension)
+}
+
+// Bundle checks the application bundle at the given path. It returns an error
+// only when the bundle cannot be checked, e.g. because it does not exist.
+func Bundle(path string) ([]Finding, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when checking [%s]", path)
+	}
+	if !stat.IsDir() {
+		return nil, errors.Errorf("[%s] is not an application bundle: it is not a directory", path)
+	}
+
+	var findings []Finding
+	infoPlistPath := filepath.Join(path, bundle.InfoPlistPath)
+	if info, err := readInfoPlist(infoPlistPath); err != nil {
+		findings = append(findings, Finding{
+			Severity: Error,
+			Path:     infoPlistPath,
+			Message:  err.Error(),
+			Hint:     "write a property list dictionary declaring at least CFBundleExecutable and CFBundleIdentifier",
+		})
+	} else {
+		findings = append(findings, checkExecutable(path, info)...)
+		findings = append(findings, checkIcon(path, info)...)
+		findings = append(findings, checkIdentifier(infoPlistPath, info)...)
+		findings = append(findings, checkMinimumSystemVersion(path, info)...)
+	}
+	fileFindings, err := checkFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	return append(findings, fileFindings...), nil
+}
+
+// DMG attaches the disk image at the given path read-only and checks
+// the application bundles at the top level of its volume.
+func DMG(path string) (findings []Finding, err error) {
+	mountPoint, err := osMkdirTemp("", "createdmg-lint-")
+	if err != nil {
+		return nil, errors.Wrap(err, "error when creating mount point")
+	}
+	defer os.Remove(mountPoint)
+	if err := hdiutilAttachDMG(path, mountPoint); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if detachErr := hdiutilDetachDMG(mountPoint); detachErr != nil && err == nil {
+			findings, err = nil, detachErr
+		}
+	}()
+
+	entries, err := os.ReadDir(mountPoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when listing the volume of [%s]", path)
+	}
+	var appBundlePaths []string
+	for _, entry := range entries {
+		if entry.IsDir() && filepath.Ext(entry.Name()) == bundle.Extension {
+			appBundlePaths = append(appBundlePaths, filepath.Join(mountPoint, entry.Name()))
+		}
+	}
+	if len(appBundlePaths) == 0 {
+		return []Finding{{
+			Severity: Warning,
+			Path:     path,
+			Message:  "the disk image holds no application bundle at its top level",
+			Hint:     "nothing was checked; lint the application bundle before creating the disk image",
+		}}, nil
+	}
+	for _, appBundlePath := range appBundlePaths {
+		bundleFindings, err := Bundle(appBundlePath)
+		if err != nil {
+			return nil, err
+		}
+		// report the paths inside the disk image rather than inside the temporary mount point.
+		for _, finding := range bundleFindings {
+			if rel, err := filepath.Rel(mountPoint, finding.Path); err == nil {
+				finding.Path = path + ":" + rel
+			}
+			findings = append(findings, finding)
+		}
+	}
+	return findings, nil
+}
+
+// readInfoPlist decodes the Info.plist file at the given path.
+func readInfoPlist(infoPlistPath string) (map[string]any, error) {
+	value, err := plist.DecodeFile(infoPlistPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, errors.New("Info.plist does not exist")
+		}
+		return nil, err
+	}
+	info, ok := value.(map[string]any)
+	if !ok {
+		return nil, errors.New("Info.plist is not a dictionary")
+	}
+	return info, nil
+}
+
+// checkExecutable checks that CFBundleExecutable names an executable file in Contents/MacOS.
+func checkExecutable(path string, info map[string]any) []Finding {
+	executable, _ := info["CFBundleExecutable"].(string)
+	if executable == "" {
+		return []Finding{{
+			Severity: Error,
+			Path:     filepath.Join(path, bundle.InfoPlistPath),
+			Message:  "CFBundleExecutable is not declared",
+			Hint:     "set CFBundleExecutable to the name of the executable in " + bundle.ExecutableDir,
+		}}
+	}
+	executablePath := filepath.Join(path, bundle.ExecutableDir, executable)
+	stat, err := os.Stat(executablePath)
+	switch {
+	case err != nil:
+		return []Finding{{
+			Severity: Error,
+			Path:     executablePath,
+			Message:  fmt.Sprintf("the executable %s named by CFBundleExecutable does not exist", executable),
+			Hint:     "copy the executable to " + bundle.ExecutableDir + " or fix CFBundleExecutable",
+		}}
+	case !stat.Mode().IsRegular():
+		return []Finding{{
+			Severity: Error,
+			Path:     executablePath,
+			Message:  "the executable is not a file",
+			Hint:     "replace it with the executable of the application",
+		}}
+	case stat.Mode().Perm()&0o111 == 0:
+		return []Finding{{
+			Severity: Error,
+			Path:     executablePath,
+			Message:  "the executable is not executable",
+			Hint:     "chmod +x " + executablePath,
+		}}
+	}
+	return nil
+}
+
+// checkIcon checks that the icon file named by CFBundleIconFile is in Contents/Resources.
+func checkIcon(path string, info map[string]any) []Finding {
+	iconFile, _ := info["CFBundleIconFile"].(string)
+	if iconFile == "" {
+		return nil
+	}
+	if filepath.Ext(iconFile) == "" {
+		iconFile += iconFileExt
+	}
+	iconPath := filepath.Join(path, resourcesDir, iconFile)
+	if _, err := os.Stat(iconPath); err != nil {
+		return []Finding{{
+			Severity: Error,
+			Path:     iconPath,
+			Message:  fmt.Sprintf("the icon file %s named by CFBundleIconFile does not exist", iconFile),
+			Hint:     "copy the icon to " + resourcesDir + " or fix CFBundleIconFile",
+		}}
+	}
+	return nil
+}
+
+// checkIdentifier checks that CFBundleIdentifier is a reverse-DNS identifier.
+func checkIdentifier(infoPlistPath string, info map[string]any) []Finding {
+	identifier, _ := info["CFBundleIdentifier"].(string)
+	if identifier == "" {
+		return []Finding{{
+			Severity: Error,
+			Path:     infoPlistPath,
+			Message:  "CFBundleIdentifier is not declared",
+			Hint:     "set CFBundleIdentifier to a reverse-DNS identifier, e.g. com.example.myapp",
+		}}
+	}
+	if !bundleIdentifierPattern.MatchString(identifier) {
+		return []Finding{{
+			Severity: Error,
+			Path:     infoPlistPath,
+			Message:  fmt.Sprintf("the bundle identifier %q is not a reverse-DNS identifier", identifier),
+			Hint:     "use only letters, digits, hyphens and dots, e.g. com.example.myapp",
+		}}
+	}
+	return nil
+}
+
+// checkMinimumSystemVersion checks that LSMinimumSystemVersion is not lower than the
+// minimum macOS version the executable is built for, found in its LC_BUILD_VERSION.
+// Executables that are not Mach-O binaries, such as scripts, are not checked.
+func checkMinimumSystemVersion(path string, info map[string]any) []Finding {
+	minimumSystemVersion, _ := info["LSMinimumSystemVersion"].(string)
+	executable, _ := info["CFBundleExecutable"].(string)
+	if minimumSystemVersion == "" || executable == "" {
+		return nil
+	}
+	binaryInfo, err := machoInspect(filepath.Join(path, bundle.ExecutableDir, executable))
+	if err != nil || binaryInfo.MinimumOS == "" {
+		return nil
+	}
+	if macho.CompareVersions(minimumSystemVersion, binaryInfo.MinimumOS) < 0 {
+		return []Finding{{
+			Severity: Error,
+			Path:     filepath.Join(path, bundle.InfoPlistPath),
+			Message:  fmt.Sprintf("LSMinimumSystemVersion %s is lower than %s, the minimum macOS version of the executable", minimumSystemVersion, binaryInfo.MinimumOS),
+			Hint:     "set LSMinimumSystemVersion to " + binaryInfo.MinimumOS + ", or build the executable for an older macOS",
+		}}
+	}
+	return nil
+}
+
+// checkFiles checks that no file of the bundle is world-writable
+// and that the bundle holds no .DS_Store file.
+func checkFiles(path string) ([]Finding, error) {
+	var findings []Finding
+	err := filepath.WalkDir(path, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if info.Mode().Perm()&0o002 != 0 {
+			findings = append(findings, Finding{
+				Severity: Error,
+				Path:     filePath,
+				Message:  "the file is world-writable",
+				Hint:     "chmod o-w " + filePath,
+			})
+		}
+		if entry.Name() == dsStoreFile {
+			findings = append(findings, Finding{
+				Severity: Warning,
+				Path:     filePath,
+				Message:  "stray .DS_Store file",
+				Hint:     "delete it; Finder creates it when browsing the bundle",
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when walking [%s]", path)
+	}
+	return findings, nil
+}
@@ -0,0 +1,316 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/macos-dmg-creator/macho"
+	"github.com/tiagomelo/macos-dmg-creator/plist"
+)
+
+func TestBundle(t *testing.T) {
+	testCases := []struct {
+		name             string
+		info             map[string]any
+		setup            func(t *testing.T, appPath string)
+		mockMachoInspect func(path string) (*macho.Info, error)
+		expected         func(appPath string) []Finding
+	}{
+		{
+			name: "no problems",
+			info: map[string]any{"LSMinimumSystemVersion": "12.0"},
+			mockMachoInspect: func(path string) (*macho.Info, error) {
+				return &macho.Info{MinimumOS: "11.0"}, nil
+			},
+			expected: func(appPath string) []Finding {
+				return nil
+			},
+		},
+		{
+			name: "missing executable",
+			info: map[string]any{"CFBundleExecutable": "Other"},
+			expected: func(appPath string) []Finding {
+				return []Finding{{
+					Severity: Error,
+					Path:     filepath.Join(appPath, "Contents/MacOS/Other"),
+					Message:  "the executable Other named by CFBundleExecutable does not exist",
+					Hint:     "copy the executable to Contents/MacOS or fix CFBundleExecutable",
+				}}
+			},
+		},
+		{
+			name: "executable not executable",
+			setup: func(t *testing.T, appPath string) {
+				require.NoError(t, os.Chmod(filepath.Join(appPath, "Contents/MacOS/MyApp"), 0o644))
+			},
+			expected: func(appPath string) []Finding {
+				executablePath := filepath.Join(appPath, "Contents/MacOS/MyApp")
+				return []Finding{{
+					Severity: Error,
+					Path:     executablePath,
+					Message:  "the executable is not executable",
+					Hint:     "chmod +x " + executablePath,
+				}}
+			},
+		},
+		{
+			name: "missing icon",
+			info: map[string]any{"CFBundleIconFile": "AppIcon"},
+			expected: func(appPath string) []Finding {
+				return []Finding{{
+					Severity: Error,
+					Path:     filepath.Join(appPath, "Contents/Resources/AppIcon.icns"),
+					Message:  "the icon file AppIcon.icns named by CFBundleIconFile does not exist",
+					Hint:     "copy the icon to Contents/Resources or fix CFBundleIconFile",
+				}}
+			},
+		},
+		{
+			name: "bundle identifier not reverse-DNS",
+			info: map[string]any{"CFBundleIdentifier": "My App"},
+			expected: func(appPath string) []Finding {
+				return []Finding{{
+					Severity: Error,
+					Path:     filepath.Join(appPath, "Contents/Info.plist"),
+					Message:  `the bundle identifier "My App" is not a reverse-DNS identifier`,
+					Hint:     "use only letters, digits, hyphens and dots, e.g. com.example.myapp",
+				}}
+			},
+		},
+		{
+			name: "minimum system version lower than the executable's",
+			info: map[string]any{"LSMinimumSystemVersion": "10.13"},
+			mockMachoInspect: func(path string) (*macho.Info, error) {
+				return &macho.Info{MinimumOS: "11.0"}, nil
+			},
+			expected: func(appPath string) []Finding {
+				return []Finding{{
+					Severity: Error,
+					Path:     filepath.Join(appPath, "Contents/Info.plist"),
+					Message:  "LSMinimumSystemVersion 10.13 is lower than 11.0, the minimum macOS version of the executable",
+					Hint:     "set LSMinimumSystemVersion to 11.0, or build the executable for an older macOS",
+				}}
+			},
+		},
+		{
+			name: "executable not a Mach-O binary",
+			info: map[string]any{"LSMinimumSystemVersion": "10.13"},
+			mockMachoInspect: func(path string) (*macho.Info, error) {
+				return nil, errors.Errorf("[%s] is not a Mach-O binary", path)
+			},
+			expected: func(appPath string) []Finding {
+				return nil
+			},
+		},
+		{
+			name: "world-writable file and stray .DS_Store",
+			setup: func(t *testing.T, appPath string) {
+				resourcePath := filepath.Join(appPath, "Contents/Resources/data.txt")
+				require.NoError(t, os.WriteFile(resourcePath, nil, 0o644))
+				require.NoError(t, os.Chmod(resourcePath, 0o666))
+				require.NoError(t, os.WriteFile(filepath.Join(appPath, "Contents/.DS_Store"), nil, 0o644))
+				require.NoError(t, os.Symlink("/tmp", filepath.Join(appPath, "Contents/Resources/link")))
+			},
+			expected: func(appPath string) []Finding {
+				resourcePath := filepath.Join(appPath, "Contents/Resources/data.txt")
+				return []Finding{
+					{
+						Severity: Warning,
+						Path:     filepath.Join(appPath, "Contents/.DS_Store"),
+						Message:  "stray .DS_Store file",
+						Hint:     "delete it; Finder creates it when browsing the bundle",
+					},
+					{
+						Severity: Error,
+						Path:     resourcePath,
+						Message:  "the file is world-writable",
+						Hint:     "chmod o-w " + resourcePath,
+					},
+				}
+			},
+		},
+		{
+			name: "missing Info.plist keys",
+			info: map[string]any{"CFBundleExecutable": nil, "CFBundleIdentifier": nil},
+			expected: func(appPath string) []Finding {
+				return []Finding{
+					{
+						Severity: Error,
+						Path:     filepath.Join(appPath, "Contents/Info.plist"),
+						Message:  "CFBundleExecutable is not declared",
+						Hint:     "set CFBundleExecutable to the name of the executable in Contents/MacOS",
+					},
+					{
+						Severity: Error,
+						Path:     filepath.Join(appPath, "Contents/Info.plist"),
+						Message:  "CFBundleIdentifier is not declared",
+						Hint:     "set CFBundleIdentifier to a reverse-DNS identifier, e.g. com.example.myapp",
+					},
+				}
+			},
+		},
+		{
+			name: "missing Info.plist",
+			setup: func(t *testing.T, appPath string) {
+				require.NoError(t, os.Remove(filepath.Join(appPath, "Contents/Info.plist")))
+			},
+			expected: func(appPath string) []Finding {
+				return []Finding{{
+					Severity: Error,
+					Path:     filepath.Join(appPath, "Contents/Info.plist"),
+					Message:  "Info.plist does not exist",
+					Hint:     "write a property list dictionary declaring at least CFBundleExecutable and CFBundleIdentifier",
+				}}
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			machoInspect = func(path string) (*macho.Info, error) {
+				return nil, errors.New("not expected to be called")
+			}
+			if tc.mockMachoInspect != nil {
+				machoInspect = tc.mockMachoInspect
+			}
+			appPath := createBundle(t, t.TempDir(), tc.info)
+			if tc.setup != nil {
+				tc.setup(t, appPath)
+			}
+
+			findings, err := Bundle(appPath)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected(appPath), findings)
+		})
+	}
+}
+
+func TestDMG(t *testing.T) {
+	testCases := []struct {
+		name          string
+		populate      func(t *testing.T, mountPoint string)
+		attachErr     error
+		detachErr     error
+		expected      []Finding
+		expectedError string
+	}{
+		{
+			name: "happy path",
+			populate: func(t *testing.T, mountPoint string) {
+				appPath := createBundle(t, mountPoint, map[string]any{"CFBundleIconFile": "icon.icns"})
+				require.NoError(t, os.Symlink("/Applications", filepath.Join(mountPoint, "Applications")))
+				require.NoError(t, os.WriteFile(filepath.Join(mountPoint, ".DS_Store"), nil, 0o644))
+				require.NoError(t, os.WriteFile(filepath.Join(appPath, "Contents/Resources/icon.icns"), nil, 0o644))
+			},
+		},
+		{
+			name: "findings",
+			populate: func(t *testing.T, mountPoint string) {
+				createBundle(t, mountPoint, map[string]any{"CFBundleIconFile": "icon.icns"})
+			},
+			expected: []Finding{{
+				Severity: Error,
+				Path:     "MyApp.dmg:MyApp.app/Contents/Resources/icon.icns",
+				Message:  "the icon file icon.icns named by CFBundleIconFile does not exist",
+				Hint:     "copy the icon to Contents/Resources or fix CFBundleIconFile",
+			}},
+		},
+		{
+			name: "no application bundle",
+			populate: func(t *testing.T, mountPoint string) {
+				require.NoError(t, os.WriteFile(filepath.Join(mountPoint, "Install.command"), nil, 0o755))
+			},
+			expected: []Finding{{
+				Severity: Warning,
+				Path:     "MyApp.dmg",
+				Message:  "the disk image holds no application bundle at its top level",
+				Hint:     "nothing was checked; lint the application bundle before creating the disk image",
+			}},
+		},
+		{
+			name:          "error when attaching",
+			attachErr:     errors.New("error when attaching dmg MyApp.dmg: some error"),
+			expectedError: "error when attaching dmg MyApp.dmg: some error",
+		},
+		{
+			name: "error when detaching",
+			populate: func(t *testing.T, mountPoint string) {
+				createBundle(t, mountPoint, nil)
+			},
+			detachErr:     errors.New("error when detaching dmg: some error"),
+			expectedError: "error when detaching dmg: some error",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			machoInspect = macho.Inspect
+			tmpDir := t.TempDir()
+			osMkdirTemp = func(dir, pattern string) (string, error) {
+				return os.MkdirTemp(tmpDir, pattern)
+			}
+			var attachedAt, detachedAt string
+			hdiutilAttachDMG = func(dmgPath, mountPoint string) error {
+				require.Equal(t, "MyApp.dmg", dmgPath)
+				if tc.attachErr != nil {
+					return tc.attachErr
+				}
+				attachedAt = mountPoint
+				tc.populate(t, mountPoint)
+				return nil
+			}
+			hdiutilDetachDMG = func(mountPoint string) error {
+				detachedAt = mountPoint
+				return tc.detachErr
+			}
+
+			findings, err := DMG("MyApp.dmg")
+			require.Equal(t, attachedAt, detachedAt)
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, findings)
+		})
+	}
+}
+
+func TestPath(t *testing.T) {
+	_, err := Path("MyApp.zip")
+	require.EqualError(t, err, "[MyApp.zip] is neither an application bundle (.app) nor a disk image (.dmg)")
+
+	_, err = Path(filepath.Join(t.TempDir(), "Missing.app"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestFindingString(t *testing.T) {
+	finding := Finding{Severity: Warning, Path: "MyApp.app/.DS_Store", Message: "stray .DS_Store file", Hint: "delete it"}
+	require.Equal(t, "warning: [MyApp.app/.DS_Store] stray .DS_Store file (hint: delete it)", finding.String())
+}
+
+// createBundle creates the bundle MyApp.app in the given directory, with an executable
+// and an Info.plist file whose entries are overridden by the given ones; nil removes them.
+func createBundle(t *testing.T, dir string, info map[string]any) string {
+	t.Helper()
+	appPath := filepath.Join(dir, "MyApp.app")
+	require.NoError(t, os.MkdirAll(filepath.Join(appPath, "Contents/MacOS"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(appPath, "Contents/Resources"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(appPath, "Contents/MacOS/MyApp"), []byte("#!/bin/sh\n"), 0o755))
+	entries := map[string]any{
+		"CFBundleExecutable": "MyApp",
+		"CFBundleIdentifier": "com.example.myapp",
+	}
+	for key, value := range info {
+		entries[key] = value
+	}
+	data, err := plist.Encode(entries)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(appPath, "Contents/Info.plist"), data, 0o644))
+	return appPath
+}
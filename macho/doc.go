@@ -0,0 +1,3 @@
+// Package macho provides functionality to inspect Mach-O binaries and
+// to assemble universal (fat) binaries.
+package macho
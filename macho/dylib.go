@@ -0,0 +1,359 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package macho
+
+import (
+	"bytes"
+	sysMacho "debug/macho"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/pkg/errors"
+)
+
+// load commands related to dynamic libraries.
+const (
+	lcSegment         = 0x1
+	lcLoadDylib       = 0xc
+	lcIDDylib         = 0xd
+	lcSegment64       = 0x19
+	lcLazyLoadDylib   = 0x20
+	lcLoadWeakDylib   = 0x80000018
+	lcRPath           = 0x8000001c
+	lcReexportDylib   = 0x8000001f
+	lcLoadUpwardDylib = 0x80000023
+)
+
+// dependencyCommands are the load commands that declare a dependency on a dynamic library.
+var dependencyCommands = []uint32{lcLoadDylib, lcLazyLoadDylib, lcLoadWeakDylib, lcReexportDylib, lcLoadUpwardDylib}
+
+const (
+	// dylibCommandSize is the size of a dylib_command without its name.
+	dylibCommandSize = 24
+
+	// rpathCommandSize is the size of a rpath_command without its path.
+	rpathCommandSize = 12
+)
+
+// Dylibs holds the dynamic library information of a Mach-O binary.
+type Dylibs struct {
+	// InstallName is the install name of a dynamic library (LC_ID_DYLIB).
+	// It is empty for executables.
+	InstallName string
+
+	// Dependencies are the install names of the dynamic libraries the binary loads.
+	Dependencies []string
+
+	// RPaths are the run path search paths of the binary (LC_RPATH).
+	RPaths []string
+}
+
+// DylibChanges describes changes to the dynamic library load commands of a Mach-O binary.
+type DylibChanges struct {
+	// InstallName replaces the install name of a dynamic library, when not empty.
+	InstallName string
+
+	// Dependencies maps install names of dependencies to their replacements.
+	Dependencies map[string]string
+
+	// AddRPaths are the run path search paths added to the binary, unless already present.
+	AddRPaths []string
+}
+
+// ReadDylibs reads the dynamic library information of the Mach-O binary at the given path.
+// For fat binaries, the information of all architecture slices is merged.
+func ReadDylibs(path string) (*Dylibs, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when reading [%s]", path)
+	}
+	images, err := parseImages(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when parsing [%s]", path)
+	}
+	dylibs := &Dylibs{}
+	for _, img := range images {
+		for _, cmd := range img.cmds {
+			switch id := img.byteOrder.Uint32(cmd); {
+			case id == lcIDDylib && dylibs.InstallName == "":
+				dylibs.InstallName = img.cmdString(cmd)
+			case slices.Contains(dependencyCommands, id):
+				dylibs.Dependencies = appendUnique(dylibs.Dependencies, img.cmdString(cmd))
+			case id == lcRPath:
+				dylibs.RPaths = appendUnique(dylibs.RPaths, img.cmdString(cmd))
+			}
+		}
+	}
+	return dylibs, nil
+}
+
+// RewriteDylibs applies the given changes to the load commands of the Mach-O binary
+// at the given path, the way install_name_tool does. The load commands must fit in the
+// space before the first section, so binaries may need to be linked with
+// -headerpad_max_install_names. Code signatures are invalidated by the changes.
+func RewriteDylibs(path string, changes DylibChanges) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "error when reading [%s]", path)
+	}
+	images, err := parseImages(data)
+	if err != nil {
+		return errors.Wrapf(err, "error when parsing [%s]", path)
+	}
+	for _, img := range images {
+		// the images share the file data, so it is rewritten in place.
+		if err := img.rewrite(changes); err != nil {
+			return errors.Wrapf(err, "error when rewriting load commands of [%s] (%s)", path, archName(img.cpu))
+		}
+	}
+	return writeFileKeepingMode(path, data)
+}
+
+// image is an architecture slice of a Mach-O binary.
+type image struct {
+	// data is the slice, which shares the data of the whole file.
+	data       []byte
+	byteOrder  binary.ByteOrder
+	cpu        sysMacho.Cpu
+	headerSize int
+
+	// cmds are the load commands of the slice.
+	cmds [][]byte
+
+	// cmdsEnd is the offset where the load commands end.
+	cmdsEnd int
+
+	// contentStart is the offset where the contents of the first section
+	// or segment start, which limits the space for the load commands.
+	contentStart int
+}
+
+// parseImages parses the architecture slices of the Mach-O binary.
+func parseImages(data []byte) ([]*image, error) {
+	fatSlices, err := readSlices(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, errors.Wrap(err, "not a valid fat Mach-O binary")
+	}
+	if fatSlices == nil {
+		img, err := parseImage(data)
+		if err != nil {
+			return nil, err
+		}
+		return []*image{img}, nil
+	}
+	var images []*image
+	for _, slice := range fatSlices {
+		if slice.offset+slice.size > int64(len(data)) {
+			return nil, errors.Errorf("%s slice is out of bounds", archName(slice.cpu))
+		}
+		img, err := parseImage(data[slice.offset : slice.offset+slice.size])
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, img)
+	}
+	return images, nil
+}
+
+// parseImage parses the header and load commands of a thin Mach-O binary.
+func parseImage(data []byte) (*image, error) {
+	if len(data) < 28 {
+		return nil, errors.New("not a Mach-O binary")
+	}
+	img := &image{data: data, byteOrder: binary.LittleEndian}
+	switch binary.LittleEndian.Uint32(data) {
+	case sysMacho.Magic32:
+		img.headerSize = 28
+	case sysMacho.Magic64:
+		img.headerSize = 32
+	default:
+		return nil, errors.New("not a Mach-O binary")
+	}
+	img.cpu = sysMacho.Cpu(img.byteOrder.Uint32(data[4:]))
+	ncmds := int(img.byteOrder.Uint32(data[16:]))
+	img.cmdsEnd = img.headerSize + int(img.byteOrder.Uint32(data[20:]))
+	if img.cmdsEnd > len(data) {
+		return nil, errors.New("load commands are out of bounds")
+	}
+	img.contentStart = len(data)
+	for offset, i := img.headerSize, 0; i < ncmds; i++ {
+		if offset+8 > img.cmdsEnd {
+			return nil, errors.New("load commands are out of bounds")
+		}
+		size := int(img.byteOrder.Uint32(data[offset+4:]))
+		if size < 8 || offset+size > img.cmdsEnd {
+			return nil, errors.Errorf("load command %d has an invalid size", i)
+		}
+		cmd := data[offset : offset+size]
+		if err := img.checkCmd(cmd); err != nil {
+			return nil, errors.Wrapf(err, "load command %d is invalid", i)
+		}
+		img.cmds = append(img.cmds, cmd)
+		offset += size
+	}
+	if img.contentStart < img.cmdsEnd {
+		return nil, errors.New("load commands overlap the contents")
+	}
+	return img, nil
+}
+
+// checkCmd checks the bounds of the load commands that are
+// read or rewritten, and gathers where the contents start.
+func (img *image) checkCmd(cmd []byte) error {
+	id := img.byteOrder.Uint32(cmd)
+	switch {
+	case id == lcIDDylib || slices.Contains(dependencyCommands, id):
+		if len(cmd) < dylibCommandSize || int(img.byteOrder.Uint32(cmd[8:])) >= len(cmd) {
+			return errors.New("invalid dylib command")
+		}
+	case id == lcRPath:
+		if len(cmd) < rpathCommandSize || int(img.byteOrder.Uint32(cmd[8:])) >= len(cmd) {
+			return errors.New("invalid rpath command")
+		}
+	case id == lcSegment64:
+		return img.checkSegment(cmd, 72, 80, 48, func(b []byte) (uint64, uint64) {
+			return img.byteOrder.Uint64(b[40:]), img.byteOrder.Uint64(b[48:])
+		})
+	case id == lcSegment:
+		return img.checkSegment(cmd, 56, 68, 40, func(b []byte) (uint64, uint64) {
+			return uint64(img.byteOrder.Uint32(b[32:])), uint64(img.byteOrder.Uint32(b[36:]))
+		})
+	}
+	return nil
+}
+
+// checkSegment gathers the lowest file offset of the contents of
+// the segment and its sections, given the layout of the segment.
+func (img *image) checkSegment(cmd []byte, segmentSize, sectionSize, sectionOffsetField int, fileRange func([]byte) (uint64, uint64)) error {
+	if len(cmd) < segmentSize {
+		return errors.New("invalid segment command")
+	}
+	nsects := int(img.byteOrder.Uint32(cmd[segmentSize-8:]))
+	if segmentSize+nsects*sectionSize > len(cmd) {
+		return errors.New("invalid segment command")
+	}
+	// the __TEXT segment starts at offset 0 since it maps the header,
+	// so only segments that start after the header count.
+	if fileOffset, fileSize := fileRange(cmd); fileOffset > 0 && fileSize > 0 {
+		img.contentStart = min(img.contentStart, int(min(fileOffset, uint64(len(img.data)))))
+	}
+	for i := 0; i < nsects; i++ {
+		section := cmd[segmentSize+i*sectionSize:]
+		// zero-fill sections have no contents in the file.
+		if offset := int(img.byteOrder.Uint32(section[sectionOffsetField:])); offset > 0 {
+			img.contentStart = min(img.contentStart, offset)
+		}
+	}
+	return nil
+}
+
+// cmdString returns the string of a dylib or rpath load command.
+func (img *image) cmdString(cmd []byte) string {
+	s := cmd[img.byteOrder.Uint32(cmd[8:]):]
+	if i := bytes.IndexByte(s, 0); i >= 0 {
+		s = s[:i]
+	}
+	return string(s)
+}
+
+// rewrite applies the changes to the load commands of the image.
+func (img *image) rewrite(changes DylibChanges) error {
+	var rpaths []string
+	var cmds [][]byte
+	for _, cmd := range img.cmds {
+		switch id := img.byteOrder.Uint32(cmd); {
+		case id == lcIDDylib && changes.InstallName != "":
+			cmd = img.stringCmd(cmd[:dylibCommandSize], changes.InstallName)
+		case slices.Contains(dependencyCommands, id):
+			if replacement, ok := changes.Dependencies[img.cmdString(cmd)]; ok {
+				cmd = img.stringCmd(cmd[:dylibCommandSize], replacement)
+			}
+		case id == lcRPath:
+			rpaths = append(rpaths, img.cmdString(cmd))
+		}
+		cmds = append(cmds, cmd)
+	}
+	for _, rpath := range changes.AddRPaths {
+		if slices.Contains(rpaths, rpath) {
+			continue
+		}
+		header := make([]byte, rpathCommandSize)
+		img.byteOrder.PutUint32(header, lcRPath)
+		cmds = append(cmds, img.stringCmd(header, rpath))
+		rpaths = append(rpaths, rpath)
+	}
+
+	var buf bytes.Buffer
+	for _, cmd := range cmds {
+		buf.Write(cmd)
+	}
+	if available := img.contentStart - img.headerSize; buf.Len() > available {
+		return errors.Errorf("the load commands need %d bytes but only %d are available; link the binary with -headerpad_max_install_names", buf.Len(), available)
+	}
+	// the new load commands are built before writing, since the
+	// old ones point to the data that is about to be overwritten.
+	end := img.headerSize + buf.Len()
+	copy(img.data[img.headerSize:], buf.Bytes())
+	clear(img.data[end:max(end, img.cmdsEnd)])
+	img.byteOrder.PutUint32(img.data[16:], uint32(len(cmds)))
+	img.byteOrder.PutUint32(img.data[20:], uint32(buf.Len()))
+	return nil
+}
+
+// stringCmd returns a load command made of the given fixed part followed
+// by the given string, padded to the pointer size of the image.
+func (img *image) stringCmd(fixed []byte, s string) []byte {
+	align := 8
+	if img.headerSize == 28 {
+		align = 4
+	}
+	size := len(fixed) + len(s) + 1
+	size = (size + align - 1) / align * align
+	cmd := make([]byte, size)
+	copy(cmd, fixed)
+	copy(cmd[len(fixed):], s)
+	img.byteOrder.PutUint32(cmd[4:], uint32(size))
+	img.byteOrder.PutUint32(cmd[8:], uint32(len(fixed)))
+	return cmd
+}
+
+// writeFileKeepingMode replaces the file at the given path with the given
+// data, keeping its mode. The file is replaced through a temporary file so
+// that read-only files, such as the libraries installed by Homebrew, can be rewritten.
+func writeFileKeepingMode(path string, data []byte) error {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return errors.Wrapf(err, "error when reading file info of [%s]", path)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return errors.Wrapf(err, "error when creating temporary file for [%s]", path)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return errors.Wrapf(err, "error when writing [%s]", tmp.Name())
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrapf(err, "error when writing [%s]", tmp.Name())
+	}
+	if err := os.Chmod(tmp.Name(), stat.Mode().Perm()); err != nil {
+		return errors.Wrapf(err, "error when changing mode of [%s]", tmp.Name())
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return errors.Wrapf(err, "error when replacing [%s]", path)
+	}
+	return nil
+}
+
+// appendUnique appends the value to the slice unless it is already present.
+func appendUnique(values []string, value string) []string {
+	if slices.Contains(values, value) {
+		return values
+	}
+	return append(values, value)
+}
@@ -0,0 +1,287 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package macho
+
+import (
+	"bytes"
+	sysMacho "debug/macho"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// testContents stands for the contents of the sections of the test binaries.
+var testContents = []byte("section contents")
+
+func TestReadDylibs(t *testing.T) {
+	testCases := []struct {
+		name     string
+		contents func() []byte
+		want     *Dylibs
+		wantErr  func(path string) error
+	}{
+		{
+			name: "executable",
+			contents: func() []byte {
+				return paddedMachO(sysMacho.TypeExec, 512,
+					dylibCmd(lcLoadDylib, "/usr/lib/libSystem.B.dylib"),
+					dylibCmd(lcLoadDylib, "/opt/homebrew/lib/libfoo.1.dylib"),
+					dylibCmd(lcLoadWeakDylib, "@rpath/libbar.dylib"),
+					rpathCmd("/opt/homebrew/lib"),
+				)
+			},
+			want: &Dylibs{
+				Dependencies: []string{"/usr/lib/libSystem.B.dylib", "/opt/homebrew/lib/libfoo.1.dylib", "@rpath/libbar.dylib"},
+				RPaths:       []string{"/opt/homebrew/lib"},
+			},
+		},
+		{
+			name: "dynamic library",
+			contents: func() []byte {
+				return paddedMachO(sysMacho.TypeDylib, 512,
+					dylibCmd(lcIDDylib, "/opt/homebrew/opt/foo/lib/libfoo.1.dylib"),
+					dylibCmd(lcReexportDylib, "/opt/homebrew/lib/libbaz.dylib"),
+				)
+			},
+			want: &Dylibs{
+				InstallName:  "/opt/homebrew/opt/foo/lib/libfoo.1.dylib",
+				Dependencies: []string{"/opt/homebrew/lib/libbaz.dylib"},
+			},
+		},
+		{
+			name: "fat binary",
+			contents: func() []byte {
+				return fatMachO(
+					paddedMachO(sysMacho.TypeExec, 512, dylibCmd(lcLoadDylib, "/usr/lib/libSystem.B.dylib"), dylibCmd(lcLoadDylib, "/usr/local/lib/libfoo.dylib")),
+					paddedMachO(sysMacho.TypeExec, 512, dylibCmd(lcLoadDylib, "/usr/lib/libSystem.B.dylib"), dylibCmd(lcLoadDylib, "/opt/homebrew/lib/libfoo.dylib")),
+				)
+			},
+			want: &Dylibs{
+				Dependencies: []string{"/usr/lib/libSystem.B.dylib", "/usr/local/lib/libfoo.dylib", "/opt/homebrew/lib/libfoo.dylib"},
+			},
+		},
+		{
+			name: "not a Mach-O binary",
+			contents: func() []byte {
+				return []byte("#!/bin/sh\necho hello\n")
+			},
+			wantErr: func(path string) error {
+				return errors.Errorf("error when parsing [%s]: not a Mach-O binary", path)
+			},
+		},
+		{
+			name: "invalid dylib command",
+			contents: func() []byte {
+				cmd := dylibCmd(lcLoadDylib, "libfoo.dylib")
+				binary.LittleEndian.PutUint32(cmd[8:], uint32(len(cmd)))
+				return paddedMachO(sysMacho.TypeExec, 512, cmd)
+			},
+			wantErr: func(path string) error {
+				return errors.Errorf("error when parsing [%s]: load command 1 is invalid: invalid dylib command", path)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "binary")
+			require.NoError(t, os.WriteFile(path, tc.contents(), 0o755))
+			got, err := ReadDylibs(path)
+			if err != nil {
+				if tc.wantErr == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.wantErr(path).Error(), err.Error())
+				return
+			}
+			if tc.wantErr != nil {
+				t.Fatalf(`expected error "%v", got nil`, tc.wantErr(path))
+			}
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestRewriteDylibs(t *testing.T) {
+	testCases := []struct {
+		name     string
+		contents func() []byte
+		mode     os.FileMode
+		changes  DylibChanges
+		want     *Dylibs
+		wantErr  func(path string) error
+	}{
+		{
+			name: "executable",
+			contents: func() []byte {
+				return paddedMachO(sysMacho.TypeExec, 512,
+					dylibCmd(lcLoadDylib, "/usr/lib/libSystem.B.dylib"),
+					dylibCmd(lcLoadDylib, "/opt/homebrew/lib/libfoo.1.dylib"),
+					dylibCmd(lcLoadWeakDylib, "@rpath/libbar.dylib"),
+					rpathCmd("/opt/homebrew/lib"),
+				)
+			},
+			mode: 0o755,
+			changes: DylibChanges{
+				Dependencies: map[string]string{
+					"/opt/homebrew/lib/libfoo.1.dylib": "@executable_path/../Frameworks/libfoo.1.dylib",
+					"@rpath/libbar.dylib":              "@executable_path/../Frameworks/libbar.dylib",
+				},
+				AddRPaths: []string{"/opt/homebrew/lib", "@executable_path/../Frameworks"},
+			},
+			want: &Dylibs{
+				Dependencies: []string{"/usr/lib/libSystem.B.dylib", "@executable_path/../Frameworks/libfoo.1.dylib", "@executable_path/../Frameworks/libbar.dylib"},
+				RPaths:       []string{"/opt/homebrew/lib", "@executable_path/../Frameworks"},
+			},
+		},
+		{
+			name: "read-only dynamic library with shorter names",
+			contents: func() []byte {
+				return paddedMachO(sysMacho.TypeDylib, 512,
+					dylibCmd(lcIDDylib, "/opt/homebrew/opt/foo/lib/libfoo.1.dylib"),
+					dylibCmd(lcLoadDylib, "/opt/homebrew/opt/baz/lib/libbaz.dylib"),
+				)
+			},
+			mode: 0o444,
+			changes: DylibChanges{
+				InstallName:  "@rpath/libfoo.1.dylib",
+				Dependencies: map[string]string{"/opt/homebrew/opt/baz/lib/libbaz.dylib": "@rpath/libbaz.dylib"},
+			},
+			want: &Dylibs{
+				InstallName:  "@rpath/libfoo.1.dylib",
+				Dependencies: []string{"@rpath/libbaz.dylib"},
+			},
+		},
+		{
+			name: "fat binary",
+			contents: func() []byte {
+				return fatMachO(
+					paddedMachO(sysMacho.TypeExec, 512, dylibCmd(lcLoadDylib, "/usr/local/lib/libfoo.dylib")),
+					paddedMachO(sysMacho.TypeExec, 512, dylibCmd(lcLoadDylib, "/usr/local/lib/libfoo.dylib")),
+				)
+			},
+			mode: 0o755,
+			changes: DylibChanges{
+				Dependencies: map[string]string{"/usr/local/lib/libfoo.dylib": "@rpath/libfoo.dylib"},
+			},
+			want: &Dylibs{
+				Dependencies: []string{"@rpath/libfoo.dylib"},
+			},
+		},
+		{
+			name: "not enough space for the load commands",
+			contents: func() []byte {
+				return paddedMachO(sysMacho.TypeExec, 0, dylibCmd(lcLoadDylib, "/usr/local/lib/libfoo.dylib"))
+			},
+			mode: 0o755,
+			changes: DylibChanges{
+				Dependencies: map[string]string{"/usr/local/lib/libfoo.dylib": "@executable_path/../Frameworks/libfoo.dylib"},
+			},
+			wantErr: func(path string) error {
+				return errors.Errorf("error when rewriting load commands of [%s] (arm64): the load commands need 224 bytes but only 208 are available; link the binary with -headerpad_max_install_names", path)
+			},
+		},
+		{
+			name: "not a Mach-O binary",
+			contents: func() []byte {
+				return []byte("#!/bin/sh\necho hello\n")
+			},
+			mode: 0o755,
+			wantErr: func(path string) error {
+				return errors.Errorf("error when parsing [%s]: not a Mach-O binary", path)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "binary")
+			require.NoError(t, os.WriteFile(path, tc.contents(), tc.mode))
+			err := RewriteDylibs(path, tc.changes)
+			if err != nil {
+				if tc.wantErr == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.wantErr(path).Error(), err.Error())
+				return
+			}
+			if tc.wantErr != nil {
+				t.Fatalf(`expected error "%v", got nil`, tc.wantErr(path))
+			}
+			got, err := ReadDylibs(path)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+
+			// the rewritten binary is still valid, with its mode and contents intact.
+			stat, err := os.Stat(path)
+			require.NoError(t, err)
+			require.Equal(t, tc.mode, stat.Mode().Perm())
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			images, err := parseImages(data)
+			require.NoError(t, err)
+			for _, img := range images {
+				_, err := sysMacho.NewFile(bytes.NewReader(img.data))
+				require.NoError(t, err)
+				require.True(t, bytes.HasSuffix(img.data, testContents))
+			}
+		})
+	}
+}
+
+// paddedMachO returns an arm64 binary whose load commands, including a __TEXT
+// segment with a single section, are followed by the given number of padding
+// bytes and by the contents of the section.
+func paddedMachO(typ sysMacho.Type, padding int, loads ...[]byte) []byte {
+	segment := segment64Cmd(0)
+	size := 32 + len(segment)
+	for _, load := range loads {
+		size += len(load)
+	}
+	binary.LittleEndian.PutUint32(segment[72+48:], uint32(size+padding))
+	data := thinMachO(sysMacho.CpuArm64, typ, append([][]byte{segment}, loads...)...)
+	data = append(data, make([]byte, padding)...)
+	return append(data, testContents...)
+}
+
+// segment64Cmd returns a __TEXT LC_SEGMENT_64 command with a
+// __text section whose contents start at the given offset.
+func segment64Cmd(sectionOffset uint32) []byte {
+	cmd := make([]byte, 72+80)
+	binary.LittleEndian.PutUint32(cmd[0:], lcSegment64)
+	binary.LittleEndian.PutUint32(cmd[4:], uint32(len(cmd)))
+	copy(cmd[8:], "__TEXT")
+	binary.LittleEndian.PutUint32(cmd[64:], 1)
+	section := cmd[72:]
+	copy(section[0:], "__text")
+	copy(section[16:], "__TEXT")
+	binary.LittleEndian.PutUint64(section[40:], uint64(len(testContents)))
+	binary.LittleEndian.PutUint32(section[48:], sectionOffset)
+	return cmd
+}
+
+// dylibCmd returns a dylib load command of the given type.
+func dylibCmd(id uint32, name string) []byte {
+	size := (dylibCommandSize + len(name) + 1 + 7) / 8 * 8
+	cmd := make([]byte, size)
+	binary.LittleEndian.PutUint32(cmd[0:], id)
+	binary.LittleEndian.PutUint32(cmd[4:], uint32(size))
+	binary.LittleEndian.PutUint32(cmd[8:], dylibCommandSize)
+	copy(cmd[dylibCommandSize:], name)
+	return cmd
+}
+
+// rpathCmd returns a LC_RPATH load command.
+func rpathCmd(path string) []byte {
+	size := (rpathCommandSize + len(path) + 1 + 7) / 8 * 8
+	cmd := make([]byte, size)
+	binary.LittleEndian.PutUint32(cmd[0:], lcRPath)
+	binary.LittleEndian.PutUint32(cmd[4:], uint32(size))
+	binary.LittleEndian.PutUint32(cmd[8:], rpathCommandSize)
+	copy(cmd[rpathCommandSize:], path)
+	return cmd
+}
@@ -0,0 +1,229 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package macho
+
+import (
+	sysMacho "debug/macho"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// load commands that are not exposed by debug/macho.
+const (
+	lcVersionMinMacOSX = 0x24
+	lcBuildVersion     = 0x32
+)
+
+// platformMacOS is the LC_BUILD_VERSION platform identifier for macOS.
+const platformMacOS = 1
+
+// maxFatArchitectures is the number of architectures from which a file starting
+// with the fat magic is taken for a Java class file rather than a fat binary,
+// like file(1) does.
+const maxFatArchitectures = 20
+
+// cpuNames maps the Mach-O CPU types to the architecture names
+// used by Apple tools such as lipo and file.
+var cpuNames = map[sysMacho.Cpu]string{
+	sysMacho.Cpu386:   "i386",
+	sysMacho.CpuAmd64: "x86_64",
+	sysMacho.CpuArm:   "arm",
+	sysMacho.CpuArm64: "arm64",
+	sysMacho.CpuPpc:   "ppc",
+	sysMacho.CpuPpc64: "ppc64",
+}
+
+// Info holds the information gathered from a Mach-O binary.
+type Info struct {
+	// Architectures are the architectures contained in the binary.
+	Architectures []string
+
+	// Fat indicates whether the binary is a fat (universal) binary.
+	Fat bool
+
+	// MinimumOS is the minimum macOS version declared by the binary,
+	// e.g. "11.0". It is empty when the binary does not declare one.
+	MinimumOS string
+}
+
+// Inspect inspects the Mach-O binary at the given path.
+// It returns an error if the file is not a Mach-O executable.
+func Inspect(path string) (*Info, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when opening [%s]", path)
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when reading file info of [%s]", path)
+	}
+	slices, err := readSlices(f, stat.Size())
+	if err != nil {
+		return nil, errors.Wrapf(err, "[%s] is not a valid fat Mach-O binary", path)
+	}
+	fat := slices != nil
+	if !fat {
+		// not a fat binary, so the whole file is a single slice.
+		slices = []fatSlice{{offset: 0, size: stat.Size()}}
+	}
+
+	info := &Info{Fat: fat}
+	for _, slice := range slices {
+		file, err := sysMacho.NewFile(io.NewSectionReader(f, slice.offset, slice.size))
+		if err != nil {
+			if isFormatError(err) {
+				return nil, errors.Errorf("[%s] is not a Mach-O binary", path)
+			}
+			return nil, errors.Wrapf(err, "error when reading [%s]", path)
+		}
+		if file.Type != sysMacho.TypeExec {
+			return nil, errors.Errorf("[%s] is not a Mach-O executable (%s file type: %v)", path, archName(file.Cpu), file.Type)
+		}
+		info.Architectures = append(info.Architectures, archName(file.Cpu))
+		// the lowest version among the slices is the one that
+		// determines on which systems the binary can be launched.
+		if minOS := minimumOS(file); minOS != "" {
+			if info.MinimumOS == "" || CompareVersions(minOS, info.MinimumOS) < 0 {
+				info.MinimumOS = minOS
+			}
+		}
+	}
+	return info, nil
+}
+
+// fatSlice is the location of an architecture slice within a fat binary.
+type fatSlice struct {
+	cpu    sysMacho.Cpu
+	subCpu uint32
+	offset int64
+	size   int64
+	align  uint32
+}
+
+// readSlices reads the architecture slices of a fat binary, which is size bytes long.
+// It returns nil slices if the file is not a fat binary.
+func readSlices(r io.ReaderAt, size int64) ([]fatSlice, error) {
+	var header [8]byte
+	if _, err := r.ReadAt(header[:], 0); err != nil {
+		return nil, nil
+	}
+	if binary.BigEndian.Uint32(header[0:]) != sysMacho.MagicFat {
+		return nil, nil
+	}
+	count := binary.BigEndian.Uint32(header[4:])
+	if count == 0 {
+		return nil, errors.New("no architectures found")
+	}
+	// Java class files start with the same magic, followed by their version,
+	// which is at least 45, while fat binaries hold only a few architectures.
+	if count >= maxFatArchitectures {
+		return nil, nil
+	}
+	if int64(count) > (size-fatHeaderSize)/fatArchSize {
+		return nil, errors.Errorf("the fat header of %d architectures is truncated", count)
+	}
+	entries := make([]byte, fatArchSize*count)
+	if _, err := r.ReadAt(entries, fatHeaderSize); err != nil {
+		return nil, errors.Wrap(err, "error when reading fat header")
+	}
+	slices := make([]fatSlice, count)
+	for i := range slices {
+		entry := entries[fatArchSize*i:]
+		slices[i] = fatSlice{
+			cpu:    sysMacho.Cpu(binary.BigEndian.Uint32(entry[0:])),
+			subCpu: binary.BigEndian.Uint32(entry[4:]),
+			offset: int64(binary.BigEndian.Uint32(entry[8:])),
+			size:   int64(binary.BigEndian.Uint32(entry[12:])),
+			align:  binary.BigEndian.Uint32(entry[16:]),
+		}
+	}
+	return slices, nil
+}
+
+// isFormatError checks whether the error returned by debug/macho
+// means that the data is not a Mach-O file at all.
+func isFormatError(err error) bool {
+	if _, ok := err.(*sysMacho.FormatError); ok {
+		return true
+	}
+	return err == io.EOF || err == io.ErrUnexpectedEOF
+}
+
+// archName returns the name of the given CPU architecture.
+func archName(cpu sysMacho.Cpu) string {
+	if name, ok := cpuNames[cpu]; ok {
+		return name
+	}
+	return fmt.Sprintf("cpu%d", uint32(cpu))
+}
+
+// minimumOS returns the minimum macOS version declared by the
+// LC_BUILD_VERSION or LC_VERSION_MIN_MACOSX load commands.
+func minimumOS(file *sysMacho.File) string {
+	for _, load := range file.Loads {
+		raw := load.Raw()
+		if len(raw) < 16 {
+			continue
+		}
+		switch file.ByteOrder.Uint32(raw[0:4]) {
+		case lcBuildVersion:
+			if file.ByteOrder.Uint32(raw[8:12]) == platformMacOS {
+				return formatVersion(file.ByteOrder.Uint32(raw[12:16]))
+			}
+		case lcVersionMinMacOSX:
+			return formatVersion(file.ByteOrder.Uint32(raw[8:12]))
+		}
+	}
+	return ""
+}
+
+// formatVersion formats a version encoded as xxxx.yy.zz nibbles.
+func formatVersion(v uint32) string {
+	major, minor, patch := v>>16, (v>>8)&0xff, v&0xff
+	if patch != 0 {
+		return fmt.Sprintf("%d.%d.%d", major, minor, patch)
+	}
+	return fmt.Sprintf("%d.%d", major, minor)
+}
+
+// CompareVersions compares two dotted version strings such as "10.13"
+// and "11.0.1". It returns -1, 0 or 1 when a is respectively lower than,
+// equal to or greater than b. Missing components are treated as zero.
+func CompareVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for len(pa) < len(pb) {
+		pa = append(pa, 0)
+	}
+	for len(pb) < len(pa) {
+		pb = append(pb, 0)
+	}
+	for i := range pa {
+		switch {
+		case pa[i] < pb[i]:
+			return -1
+		case pa[i] > pb[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// versionParts splits a dotted version string into its numeric parts.
+func versionParts(v string) []int {
+	var parts []int
+	for _, field := range strings.Split(v, ".") {
+		n, _ := strconv.Atoi(strings.TrimSpace(field))
+		parts = append(parts, n)
+	}
+	return parts
+}
@@ -0,0 +1,235 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package macho
+
+import (
+	sysMacho "debug/macho"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspect(t *testing.T) {
+	testCases := []struct {
+		name     string
+		contents func() []byte
+		want     *Info
+		wantErr  func(path string) error
+	}{
+		{
+			name: "thin binary with LC_BUILD_VERSION",
+			contents: func() []byte {
+				return thinMachO(sysMacho.CpuArm64, sysMacho.TypeExec, buildVersionCmd(platformMacOS, 0x000b0000))
+			},
+			want: &Info{Architectures: []string{"arm64"}, MinimumOS: "11.0"},
+		},
+		{
+			name: "thin binary with LC_VERSION_MIN_MACOSX",
+			contents: func() []byte {
+				return thinMachO(sysMacho.CpuAmd64, sysMacho.TypeExec, versionMinCmd(0x000a0d01))
+			},
+			want: &Info{Architectures: []string{"x86_64"}, MinimumOS: "10.13.1"},
+		},
+		{
+			name: "thin binary without minimum OS",
+			contents: func() []byte {
+				return thinMachO(sysMacho.CpuAmd64, sysMacho.TypeExec)
+			},
+			want: &Info{Architectures: []string{"x86_64"}},
+		},
+		{
+			name: "thin binary built for another platform",
+			contents: func() []byte {
+				return thinMachO(sysMacho.CpuArm64, sysMacho.TypeExec, buildVersionCmd(2, 0x000e0000))
+			},
+			want: &Info{Architectures: []string{"arm64"}},
+		},
+		{
+			name: "fat binary",
+			contents: func() []byte {
+				return fatMachO(
+					thinMachO(sysMacho.CpuAmd64, sysMacho.TypeExec, buildVersionCmd(platformMacOS, 0x000a0f00)),
+					thinMachO(sysMacho.CpuArm64, sysMacho.TypeExec, buildVersionCmd(platformMacOS, 0x000b0000)),
+				)
+			},
+			want: &Info{Architectures: []string{"x86_64", "arm64"}, Fat: true, MinimumOS: "10.15"},
+		},
+		{
+			name: "fat binary with a non executable slice",
+			contents: func() []byte {
+				return fatMachO(
+					thinMachO(sysMacho.CpuAmd64, sysMacho.TypeExec),
+					thinMachO(sysMacho.CpuArm64, sysMacho.TypeDylib),
+				)
+			},
+			wantErr: func(path string) error {
+				return errors.Errorf("[%s] is not a Mach-O executable (arm64 file type: Dylib)", path)
+			},
+		},
+		{
+			name: "truncated fat header",
+			contents: func() []byte {
+				return []byte("\xca\xfe\xba\xbe\x00\x00\x00\x03\x00\x00\x00\x07")
+			},
+			wantErr: func(path string) error {
+				return errors.Errorf("[%s] is not a valid fat Mach-O binary: the fat header of 3 architectures is truncated", path)
+			},
+		},
+		{
+			name: "fat header with a huge number of architectures",
+			contents: func() []byte {
+				return []byte("\xca\xfe\xba\xbe\x0c\xcc\xcc\xcd\x00\x00\x00\x07")
+			},
+			wantErr: func(path string) error {
+				return errors.Errorf("[%s] is not a Mach-O binary", path)
+			},
+		},
+		{
+			name: "Java class file",
+			contents: func() []byte {
+				return []byte("\xca\xfe\xba\xbe\x00\x00\x00\x41\x00\x1d\x0a\x00\x02\x00\x03\x07")
+			},
+			wantErr: func(path string) error {
+				return errors.Errorf("[%s] is not a Mach-O binary", path)
+			},
+		},
+		{
+			name: "dynamic library",
+			contents: func() []byte {
+				return thinMachO(sysMacho.CpuArm64, sysMacho.TypeDylib)
+			},
+			wantErr: func(path string) error {
+				return errors.Errorf("[%s] is not a Mach-O executable (arm64 file type: Dylib)", path)
+			},
+		},
+		{
+			name: "ELF binary",
+			contents: func() []byte {
+				return []byte("\x7fELF\x02\x01\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00")
+			},
+			wantErr: func(path string) error {
+				return errors.Errorf("[%s] is not a Mach-O binary", path)
+			},
+		},
+		{
+			name: "empty file",
+			contents: func() []byte {
+				return nil
+			},
+			wantErr: func(path string) error {
+				return errors.Errorf("[%s] is not a Mach-O binary", path)
+			},
+		},
+		{
+			name: "Windows executable",
+			contents: func() []byte {
+				return []byte("MZ\x90\x00\x03\x00\x00\x00\x04\x00\x00\x00\xff\xff\x00\x00")
+			},
+			wantErr: func(path string) error {
+				return errors.Errorf("[%s] is not a Mach-O binary", path)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "binary")
+			require.NoError(t, os.WriteFile(path, tc.contents(), 0o755))
+
+			got, err := Inspect(path)
+			if err != nil {
+				if tc.wantErr == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.wantErr(path).Error(), err.Error())
+			} else {
+				if tc.wantErr != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.wantErr(path))
+				}
+				require.Equal(t, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	testCases := []struct {
+		a, b string
+		want int
+	}{
+		{a: "10.13", b: "10.13.0", want: 0},
+		{a: "10.9", b: "10.13", want: -1},
+		{a: "11.0", b: "10.15.7", want: 1},
+		{a: "12", b: "12.0.1", want: -1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.a+" vs "+tc.b, func(t *testing.T) {
+			require.Equal(t, tc.want, CompareVersions(tc.a, tc.b))
+		})
+	}
+}
+
+// thinMachO returns a minimal 64-bit little-endian Mach-O file
+// with the given CPU type, file type and load commands.
+func thinMachO(cpu sysMacho.Cpu, typ sysMacho.Type, loads ...[]byte) []byte {
+	var cmds []byte
+	for _, load := range loads {
+		cmds = append(cmds, load...)
+	}
+	header := make([]byte, 32)
+	binary.LittleEndian.PutUint32(header[0:], sysMacho.Magic64)
+	binary.LittleEndian.PutUint32(header[4:], uint32(cpu))
+	binary.LittleEndian.PutUint32(header[8:], 0)
+	binary.LittleEndian.PutUint32(header[12:], uint32(typ))
+	binary.LittleEndian.PutUint32(header[16:], uint32(len(loads)))
+	binary.LittleEndian.PutUint32(header[20:], uint32(len(cmds)))
+	return append(header, cmds...)
+}
+
+// buildVersionCmd returns a LC_BUILD_VERSION load command.
+func buildVersionCmd(platform, minOS uint32) []byte {
+	cmd := make([]byte, 24)
+	binary.LittleEndian.PutUint32(cmd[0:], lcBuildVersion)
+	binary.LittleEndian.PutUint32(cmd[4:], 24)
+	binary.LittleEndian.PutUint32(cmd[8:], platform)
+	binary.LittleEndian.PutUint32(cmd[12:], minOS)
+	binary.LittleEndian.PutUint32(cmd[16:], minOS)
+	return cmd
+}
+
+// versionMinCmd returns a LC_VERSION_MIN_MACOSX load command.
+func versionMinCmd(minOS uint32) []byte {
+	cmd := make([]byte, 16)
+	binary.LittleEndian.PutUint32(cmd[0:], lcVersionMinMacOSX)
+	binary.LittleEndian.PutUint32(cmd[4:], 16)
+	binary.LittleEndian.PutUint32(cmd[8:], minOS)
+	binary.LittleEndian.PutUint32(cmd[12:], minOS)
+	return cmd
+}
+
+// fatMachO wraps the given thin Mach-O files into a fat file.
+func fatMachO(slices ...[]byte) []byte {
+	const align = 12
+	header := make([]byte, 8+20*len(slices))
+	binary.BigEndian.PutUint32(header[0:], sysMacho.MagicFat)
+	binary.BigEndian.PutUint32(header[4:], uint32(len(slices)))
+	data := header
+	for i, slice := range slices {
+		for len(data)%(1<<align) != 0 {
+			data = append(data, 0)
+		}
+		entry := data[8+20*i:]
+		binary.BigEndian.PutUint32(entry[0:], binary.LittleEndian.Uint32(slice[4:]))
+		binary.BigEndian.PutUint32(entry[4:], binary.LittleEndian.Uint32(slice[8:]))
+		binary.BigEndian.PutUint32(entry[8:], uint32(len(data)))
+		binary.BigEndian.PutUint32(entry[12:], uint32(len(slice)))
+		binary.BigEndian.PutUint32(entry[16:], align)
+		data = append(data, slice...)
+	}
+	return data
+}
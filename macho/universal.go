@@ -0,0 +1,204 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package macho
+
+import (
+	"bytes"
+	sysMacho "debug/macho"
+	"encoding/binary"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// fatHeaderSize is the size of the fat_header structure.
+	fatHeaderSize = 8
+
+	// fatArchSize is the size of the fat_arch structure.
+	fatArchSize = 20
+
+	// cpuSubtypeMask masks out the capability bits of a CPU subtype.
+	cpuSubtypeMask = 0x00ffffff
+)
+
+// CreateUniversal merges the given Mach-O executables, each one built for a
+// different architecture, into a universal (fat) binary written to outputPath.
+// Inputs that are already fat binaries contribute all of their slices.
+// It returns an error if two inputs share an architecture or if they do not
+// match each other, e.g. when mixing executables built for different platforms.
+func CreateUniversal(outputPath string, binaryPaths ...string) error {
+	if len(binaryPaths) == 0 {
+		return errors.New("no binaries to merge")
+	}
+	var slices []universalSlice
+	for _, binaryPath := range binaryPaths {
+		binarySlices, err := readUniversalSlices(binaryPath)
+		if err != nil {
+			return err
+		}
+		slices = append(slices, binarySlices...)
+	}
+	if err := checkUniversalSlices(slices); err != nil {
+		return err
+	}
+	// lipo places the slices with the smallest alignment first.
+	sort.SliceStable(slices, func(i, j int) bool {
+		if slices[i].align != slices[j].align {
+			return slices[i].align < slices[j].align
+		}
+		return slices[i].cpu < slices[j].cpu
+	})
+	if err := writeUniversal(outputPath, slices); err != nil {
+		return errors.Wrapf(err, "error when writing universal binary [%s]", outputPath)
+	}
+	return nil
+}
+
+// universalSlice is an architecture slice to be written into a universal binary.
+type universalSlice struct {
+	path     string
+	cpu      sysMacho.Cpu
+	subCpu   uint32
+	fileType sysMacho.Type
+	platform uint32
+	align    uint32
+	data     []byte
+}
+
+// readUniversalSlices reads the architecture slices of the Mach-O binary at the given path.
+func readUniversalSlices(path string) ([]universalSlice, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when reading [%s]", path)
+	}
+	reader := bytes.NewReader(data)
+	fatSlices, err := readSlices(reader, int64(len(data)))
+	if err != nil {
+		return nil, errors.Wrapf(err, "[%s] is not a valid fat Mach-O binary", path)
+	}
+	if fatSlices == nil {
+		fatSlices = []fatSlice{{offset: 0, size: int64(len(data))}}
+	}
+	var slices []universalSlice
+	for _, fatSlice := range fatSlices {
+		if fatSlice.offset+fatSlice.size > int64(len(data)) {
+			return nil, errors.Errorf("[%s] is not a valid fat Mach-O binary: slice exceeds file size", path)
+		}
+		sliceData := data[fatSlice.offset : fatSlice.offset+fatSlice.size]
+		file, err := sysMacho.NewFile(io.NewSectionReader(reader, fatSlice.offset, fatSlice.size))
+		if err != nil {
+			if isFormatError(err) {
+				return nil, errors.Errorf("[%s] is not a Mach-O binary", path)
+			}
+			return nil, errors.Wrapf(err, "error when reading [%s]", path)
+		}
+		slices = append(slices, universalSlice{
+			path:     path,
+			cpu:      file.Cpu,
+			subCpu:   file.SubCpu,
+			fileType: file.Type,
+			platform: platform(file),
+			align:    sliceAlignment(file.Cpu),
+			data:     sliceData,
+		})
+	}
+	return slices, nil
+}
+
+// checkUniversalSlices checks that the slices can be merged together.
+func checkUniversalSlices(slices []universalSlice) error {
+	first := slices[0]
+	seen := make(map[[2]uint32]string)
+	var withPlatform *universalSlice
+	for _, slice := range slices {
+		arch := archName(slice.cpu)
+		key := [2]uint32{uint32(slice.cpu), slice.subCpu & cpuSubtypeMask}
+		if path, ok := seen[key]; ok {
+			return errors.Errorf("duplicate architecture %s in [%s] and [%s]", arch, path, slice.path)
+		}
+		seen[key] = slice.path
+		if slice.fileType != first.fileType {
+			return errors.Errorf("mismatched architectures: [%s] is of file type %v (%s) but [%s] is of file type %v (%s)",
+				first.path, first.fileType, archName(first.cpu), slice.path, slice.fileType, arch)
+		}
+		// binaries that do not declare a platform are assumed to be compatible.
+		if slice.platform == 0 {
+			continue
+		}
+		if withPlatform == nil {
+			withPlatform = &slice
+			continue
+		}
+		if slice.platform != withPlatform.platform {
+			return errors.Errorf("mismatched architectures: [%s] is built for platform %d (%s) but [%s] is built for platform %d (%s)",
+				withPlatform.path, withPlatform.platform, archName(withPlatform.cpu), slice.path, slice.platform, arch)
+		}
+	}
+	return nil
+}
+
+// writeUniversal writes the fat header followed by the aligned slices.
+func writeUniversal(outputPath string, slices []universalSlice) error {
+	header := make([]byte, fatHeaderSize+fatArchSize*len(slices))
+	binary.BigEndian.PutUint32(header[0:], sysMacho.MagicFat)
+	binary.BigEndian.PutUint32(header[4:], uint32(len(slices)))
+	offset := uint64(len(header))
+	offsets := make([]uint64, len(slices))
+	for i, slice := range slices {
+		offset = alignUp(offset, 1<<slice.align)
+		offsets[i] = offset
+		entry := header[fatHeaderSize+fatArchSize*i:]
+		binary.BigEndian.PutUint32(entry[0:], uint32(slice.cpu))
+		binary.BigEndian.PutUint32(entry[4:], slice.subCpu)
+		binary.BigEndian.PutUint32(entry[8:], uint32(offset))
+		binary.BigEndian.PutUint32(entry[12:], uint32(len(slice.data)))
+		binary.BigEndian.PutUint32(entry[16:], slice.align)
+		offset += uint64(len(slice.data))
+	}
+	if offset > 1<<32-1 {
+		return errors.New("universal binary exceeds 4GB")
+	}
+	data := make([]byte, offset)
+	copy(data, header)
+	for i, slice := range slices {
+		copy(data[offsets[i]:], slice.data)
+	}
+	return os.WriteFile(outputPath, data, 0o755)
+}
+
+// sliceAlignment returns the alignment, as a power of two,
+// used by lipo for slices of the given CPU type.
+func sliceAlignment(cpu sysMacho.Cpu) uint32 {
+	if cpu == sysMacho.CpuArm64 {
+		return 14
+	}
+	return 12
+}
+
+// alignUp rounds the offset up to the given alignment.
+func alignUp(offset, align uint64) uint64 {
+	return (offset + align - 1) &^ (align - 1)
+}
+
+// platform returns the platform declared by the LC_BUILD_VERSION
+// load command, or zero if the binary does not declare one.
+func platform(file *sysMacho.File) uint32 {
+	for _, load := range file.Loads {
+		raw := load.Raw()
+		if len(raw) < 12 {
+			continue
+		}
+		switch file.ByteOrder.Uint32(raw[0:4]) {
+		case lcBuildVersion:
+			return file.ByteOrder.Uint32(raw[8:12])
+		case lcVersionMinMacOSX:
+			return platformMacOS
+		}
+	}
+	return 0
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package macho
+
+import (
+	"bytes"
+	sysMacho "debug/macho"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateUniversal(t *testing.T) {
+	amd64 := thinMachO(sysMacho.CpuAmd64, sysMacho.TypeExec, buildVersionCmd(platformMacOS, 0x000a0f00))
+	arm64 := thinMachO(sysMacho.CpuArm64, sysMacho.TypeExec, buildVersionCmd(platformMacOS, 0x000b0000))
+	testCases := []struct {
+		name      string
+		binaries  [][]byte
+		wantCpus  []sysMacho.Cpu
+		wantDatas [][]byte
+		wantErr   func(paths []string) error
+	}{
+		{
+			name:      "happy path",
+			binaries:  [][]byte{arm64, amd64},
+			wantCpus:  []sysMacho.Cpu{sysMacho.CpuAmd64, sysMacho.CpuArm64},
+			wantDatas: [][]byte{amd64, arm64},
+		},
+		{
+			name:      "fat input",
+			binaries:  [][]byte{fatMachO(amd64), arm64},
+			wantCpus:  []sysMacho.Cpu{sysMacho.CpuAmd64, sysMacho.CpuArm64},
+			wantDatas: [][]byte{amd64, arm64},
+		},
+		{
+			name:     "duplicate architecture",
+			binaries: [][]byte{arm64, amd64, arm64},
+			wantErr: func(paths []string) error {
+				return errors.Errorf("duplicate architecture arm64 in [%s] and [%s]", paths[0], paths[2])
+			},
+		},
+		{
+			name:     "mismatched file types",
+			binaries: [][]byte{arm64, thinMachO(sysMacho.CpuAmd64, sysMacho.TypeDylib)},
+			wantErr: func(paths []string) error {
+				return errors.Errorf("mismatched architectures: [%s] is of file type Exec (arm64) but [%s] is of file type Dylib (x86_64)", paths[0], paths[1])
+			},
+		},
+		{
+			name:     "mismatched platforms",
+			binaries: [][]byte{arm64, thinMachO(sysMacho.CpuAmd64, sysMacho.TypeExec, buildVersionCmd(2, 0x000e0000))},
+			wantErr: func(paths []string) error {
+				return errors.Errorf("mismatched architectures: [%s] is built for platform 1 (arm64) but [%s] is built for platform 2 (x86_64)", paths[0], paths[1])
+			},
+		},
+		{
+			name:     "not a Mach-O binary",
+			binaries: [][]byte{arm64, []byte("#!/bin/sh\necho hello\n")},
+			wantErr: func(paths []string) error {
+				return errors.Errorf("[%s] is not a Mach-O binary", paths[1])
+			},
+		},
+		{
+			name: "no binaries",
+			wantErr: func(paths []string) error {
+				return errors.New("no binaries to merge")
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			var paths []string
+			for i, data := range tc.binaries {
+				path := filepath.Join(dir, "binary-"+string(rune('a'+i)))
+				require.NoError(t, os.WriteFile(path, data, 0o755))
+				paths = append(paths, path)
+			}
+			outputPath := filepath.Join(dir, "universal")
+
+			err := CreateUniversal(outputPath, paths...)
+			if err != nil {
+				if tc.wantErr == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.wantErr(paths).Error(), err.Error())
+				return
+			}
+			if tc.wantErr != nil {
+				t.Fatalf(`expected error "%v", got nil`, tc.wantErr(paths))
+			}
+
+			output, err := os.ReadFile(outputPath)
+			require.NoError(t, err)
+			fatFile, err := sysMacho.NewFatFile(bytes.NewReader(output))
+			require.NoError(t, err)
+			require.Len(t, fatFile.Arches, len(tc.wantCpus))
+			for i, arch := range fatFile.Arches {
+				require.Equal(t, tc.wantCpus[i], arch.Cpu)
+				require.Zero(t, arch.Offset%(1<<arch.Align))
+				require.Equal(t, tc.wantDatas[i], output[arch.Offset:arch.Offset+arch.Size])
+			}
+
+			info, err := Inspect(outputPath)
+			require.NoError(t, err)
+			require.True(t, info.Fat)
+			require.Equal(t, "10.15", info.MinimumOS)
+		})
+	}
+}
@@ -0,0 +1,3 @@
+// Package osascript provides a Go interface to the macOS osascript command-line tool,
+// used to lay out the Finder window of a mounted disk image with AppleScript.
+package osascript
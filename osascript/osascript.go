@@ -0,0 +1,135 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package osascript
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"github.com/tiagomelo/macos-dmg-creator/syscall"
+)
+
+// osCommandExecutorProvider is a variable that holds the function
+// that executes a command with arguments.
+var osCommandExecutorProvider osCommandExecutor = &defaultOsCommandExecutor{}
+
+// osCommandExecutor defines an interface for executing OS commands.
+type osCommandExecutor interface {
+	ExecCommand(name string, arg ...string) (string, error)
+}
+
+// defaultOsCommandExecutor is the default implementation of osCommandExecutor.
+type defaultOsCommandExecutor struct{}
+
+// ExecCommand executes a command with arguments.
+func (d *defaultOsCommandExecutor) ExecCommand(name string, arg ...string) (string, error) {
+	return syscall.ExecCommand(name, arg...)
+}
+
+// Icon is the position of the center of an icon in the window.
+type Icon struct {
+	// Name is the name of the item, e.g. "MyApp.app" or "Applications".
+	Name string
+
+	// X and Y are the position of the center of the icon.
+	X, Y int
+}
+
+// Window is the layout of the Finder window of a mounted disk.
+type Window struct {
+	// VolumeName is the name of the mounted disk, as shown in Finder.
+	VolumeName string
+
+	// X and Y are the position of the window on the screen.
+	X, Y int
+
+	// Width and Height are the size of the window.
+	Width, Height int
+
+	// IconSize and TextSize are the sizes of the icons and of their labels, in points.
+	IconSize, TextSize int
+
+	// Icons are the positions of the icons.
+	Icons []Icon
+
+	// BackgroundPath is the path of the background image, relative to the root
+	// of the disk, e.g. ".background/background.png". Empty for no background.
+	BackgroundPath string
+}
+
+// layoutScriptTpl is the template of the AppleScript that lays out the window.
+// The window is closed and opened again so Finder writes the .DS_Store file.
+var layoutScriptTpl = template.Must(template.New("layout").Funcs(template.FuncMap{
+	"quote": quote,
+	"add": func(a, b int) int {
+		return a + b
+	},
+	"hfs": func(path string) string {
+		return strings.ReplaceAll(path, "/", ":")
+	},
+}).Parse(`tell application "Finder"
+	tell disk {{quote .VolumeName}}
+		open
+		set current view of container window to icon view
+		set toolbar visible of container window to false
+		set statusbar visible of container window to false
+		set the bounds of container window to { {{- .X}}, {{.Y}}, {{add .X .Width}}, {{add .Y .Height -}} }
+		set viewOptions to the icon view options of container window
+		set arrangement of viewOptions to not arranged
+		set icon size of viewOptions to {{.IconSize}}
+		set text size of viewOptions to {{.TextSize}}
+{{- if .BackgroundPath}}
+		set background picture of viewOptions to file {{quote (hfs .BackgroundPath)}}
+{{- end}}
+{{- range .Icons}}
+		set position of item {{quote .Name}} of container window to { {{- .X}}, {{.Y -}} }
+{{- end}}
+		close
+		open
+		update without registering applications
+		delay 1
+		close
+	end tell
+end tell
+`))
+
+// Script returns the AppleScript that lays out the Finder window,
+// with the icons sorted by name.
+func Script(w *Window) (string, error) {
+	if w.VolumeName == "" {
+		return "", errors.New("the volume name is empty")
+	}
+	sorted := *w
+	sorted.Icons = append([]Icon(nil), w.Icons...)
+	sort.Slice(sorted.Icons, func(i, j int) bool {
+		return sorted.Icons[i].Name < sorted.Icons[j].Name
+	})
+	var script bytes.Buffer
+	if err := layoutScriptTpl.Execute(&script, &sorted); err != nil {
+		return "", errors.Wrap(err, "error when rendering layout script")
+	}
+	return script.String(), nil
+}
+
+// ApplyLayout lays out the Finder window of the mounted disk by running
+// the AppleScript returned by Script with osascript.
+func ApplyLayout(w *Window) error {
+	script, err := Script(w)
+	if err != nil {
+		return err
+	}
+	if _, err := osCommandExecutorProvider.ExecCommand("osascript", "-e", script); err != nil {
+		return errors.Wrapf(err, "error when laying out the window of disk %s", w.VolumeName)
+	}
+	return nil
+}
+
+// quote returns the string as an AppleScript string literal.
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
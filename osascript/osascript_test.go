@@ -0,0 +1,149 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package osascript
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// update rewrites the snapshots under testdata with the generated scripts.
+var update = flag.Bool("update", false, "update the AppleScript snapshots under testdata")
+
+func TestScript(t *testing.T) {
+	testCases := []struct {
+		name          string
+		window        *Window
+		snapshot      string
+		expectedError error
+	}{
+		{
+			name: "window",
+			window: &Window{
+				VolumeName: "MyApp",
+				X:          100, Y: 120, Width: 640, Height: 400,
+				IconSize: 128, TextSize: 12,
+				Icons: []Icon{
+					{Name: "MyApp.app", X: 160, Y: 200},
+					{Name: "Applications", X: 480, Y: 200},
+				},
+			},
+			snapshot: "window.applescript",
+		},
+		{
+			name: "window with background image",
+			window: &Window{
+				VolumeName: `My "Quoted" App`,
+				Width:      660, Height: 420,
+				IconSize: 96, TextSize: 14,
+				Icons: []Icon{
+					{Name: "Install.command", X: 110, Y: 210},
+					{Name: "Uninstall.command", X: 330, Y: 210},
+					{Name: "payload", X: 550, Y: 210},
+				},
+				BackgroundPath: ".background/background.tiff",
+			},
+			snapshot: "background.applescript",
+		},
+		{
+			name:          "empty volume name",
+			window:        &Window{},
+			expectedError: errors.New("the volume name is empty"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			script, err := Script(tc.window)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+				return
+			}
+			if tc.expectedError != nil {
+				t.Fatalf(`expected error "%v", got nil`, tc.expectedError)
+			}
+			snapshotPath := filepath.Join("testdata", tc.snapshot)
+			if *update {
+				require.NoError(t, os.WriteFile(snapshotPath, []byte(script), 0o644))
+			}
+			snapshot, err := os.ReadFile(snapshotPath)
+			require.NoError(t, err)
+			require.Equal(t, string(snapshot), script)
+		})
+	}
+}
+
+func TestApplyLayout(t *testing.T) {
+	window := &Window{VolumeName: "MyApp", Width: 640, Height: 400, IconSize: 128, TextSize: 12}
+	testCases := []struct {
+		name                  string
+		window                *Window
+		mockOsCommandExecutor func() *mockOsCommandExecutor
+		expectedError         error
+	}{
+		{
+			name:   "happy path",
+			window: window,
+			mockOsCommandExecutor: func() *mockOsCommandExecutor {
+				return &mockOsCommandExecutor{}
+			},
+		},
+		{
+			name:   "invalid window",
+			window: &Window{},
+			mockOsCommandExecutor: func() *mockOsCommandExecutor {
+				return &mockOsCommandExecutor{}
+			},
+			expectedError: errors.New("the volume name is empty"),
+		},
+		{
+			name:   "error",
+			window: window,
+			mockOsCommandExecutor: func() *mockOsCommandExecutor {
+				return &mockOsCommandExecutor{
+					err: errors.New("some error"),
+				}
+			},
+			expectedError: errors.New("error when laying out the window of disk MyApp: some error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockOsCommandExecutor := tc.mockOsCommandExecutor()
+			osCommandExecutorProvider = mockOsCommandExecutor
+			err := ApplyLayout(tc.window)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+				return
+			}
+			if tc.expectedError != nil {
+				t.Fatalf(`expected error "%v", got nil`, tc.expectedError)
+			}
+			script, err := Script(tc.window)
+			require.NoError(t, err)
+			require.Equal(t, []string{"osascript", "-e", script}, mockOsCommandExecutor.args)
+		})
+	}
+}
+
+type mockOsCommandExecutor struct {
+	err  error
+	args []string
+}
+
+func (m *mockOsCommandExecutor) ExecCommand(name string, arg ...string) (string, error) {
+	m.args = append([]string{name}, arg...)
+	return "", m.err
+}
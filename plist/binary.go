@@ -0,0 +1,386 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package plist
+
+import (
+	"encoding/binary"
+	"math"
+	"math/bits"
+	"time"
+	"unicode/utf16"
+
+	"github.com/pkg/errors"
+)
+
+// binaryTrailerSize is the size of the trailer of binary property lists.
+const binaryTrailerSize = 32
+
+// binaryDecoder decodes the objects of a binary property list.
+type binaryDecoder struct {
+	data          []byte
+	offsets       []uint64
+	objectRefSize int
+	decoding      map[uint64]bool
+}
+
+// decodeBinary decodes a property list in the binary format.
+func decodeBinary(data []byte) (any, error) {
+	if len(data) < len(binaryMagic)+binaryTrailerSize {
+		return nil, errors.New("binary property list is too short")
+	}
+	trailer := data[len(data)-binaryTrailerSize:]
+	offsetIntSize := int(trailer[6])
+	objectRefSize := int(trailer[7])
+	numObjects := binary.BigEndian.Uint64(trailer[8:16])
+	topObject := binary.BigEndian.Uint64(trailer[16:24])
+	offsetTableOffset := binary.BigEndian.Uint64(trailer[24:32])
+	if offsetIntSize < 1 || offsetIntSize > 8 || objectRefSize < 1 || objectRefSize > 8 {
+		return nil, errors.New("invalid binary property list trailer")
+	}
+	tableEnd := uint64(len(data) - binaryTrailerSize)
+	if numObjects == 0 || topObject >= numObjects || offsetTableOffset > tableEnd ||
+		numObjects > (tableEnd-offsetTableOffset)/uint64(offsetIntSize) {
+		return nil, errors.New("invalid binary property list trailer")
+	}
+	d := &binaryDecoder{
+		data:          data,
+		offsets:       make([]uint64, numObjects),
+		objectRefSize: objectRefSize,
+		decoding:      map[uint64]bool{},
+	}
+	for i := range d.offsets {
+		start := offsetTableOffset + uint64(i*offsetIntSize)
+		d.offsets[i] = readUint(data[start : start+uint64(offsetIntSize)])
+		if d.offsets[i] < uint64(len(binaryMagic)) || d.offsets[i] >= offsetTableOffset {
+			return nil, errors.Errorf("invalid offset for object %d", i)
+		}
+	}
+	return d.object(topObject)
+}
+
+// object decodes the object with the given reference.
+func (d *binaryDecoder) object(ref uint64) (any, error) {
+	if ref >= uint64(len(d.offsets)) {
+		return nil, errors.Errorf("invalid object reference %d", ref)
+	}
+	if d.decoding[ref] {
+		return nil, errors.Errorf("object %d references itself", ref)
+	}
+	d.decoding[ref] = true
+	defer delete(d.decoding, ref)
+
+	offset := d.offsets[ref]
+	marker := d.data[offset]
+	kind, info := marker>>4, marker&0x0f
+	switch kind {
+	case 0x0:
+		switch info {
+		case 0x8:
+			return false, nil
+		case 0x9:
+			return true, nil
+		}
+	case 0x1:
+		raw, err := d.bytes(offset+1, 1<<info)
+		if err != nil {
+			return nil, err
+		}
+		// integers of 16 bytes hold the value in their lower 8 bytes.
+		return int64(readUint(raw[len(raw)-min(len(raw), 8):])), nil
+	case 0x2:
+		raw, err := d.bytes(offset+1, 1<<info)
+		if err != nil {
+			return nil, err
+		}
+		switch len(raw) {
+		case 4:
+			return float64(math.Float32frombits(binary.BigEndian.Uint32(raw))), nil
+		case 8:
+			return math.Float64frombits(binary.BigEndian.Uint64(raw)), nil
+		}
+	case 0x3:
+		if info == 0x3 {
+			raw, err := d.bytes(offset+1, 8)
+			if err != nil {
+				return nil, err
+			}
+			seconds := math.Float64frombits(binary.BigEndian.Uint64(raw))
+			return referenceDate.Add(time.Duration(seconds * float64(time.Second))), nil
+		}
+	case 0x4:
+		start, count, err := d.count(offset, info)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := d.bytes(start, count)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(nil), raw...), nil
+	case 0x5:
+		start, count, err := d.count(offset, info)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := d.bytes(start, count)
+		if err != nil {
+			return nil, err
+		}
+		return string(raw), nil
+	case 0x6:
+		start, count, err := d.count(offset, info)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := d.bytes(start, count*2)
+		if err != nil {
+			return nil, err
+		}
+		units := make([]uint16, count)
+		for i := range units {
+			units[i] = binary.BigEndian.Uint16(raw[i*2:])
+		}
+		return string(utf16.Decode(units)), nil
+	case 0xa:
+		start, count, err := d.count(offset, info)
+		if err != nil {
+			return nil, err
+		}
+		refs, err := d.refs(start, count)
+		if err != nil {
+			return nil, err
+		}
+		array := make([]any, 0, count)
+		for _, itemRef := range refs {
+			item, err := d.object(itemRef)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, item)
+		}
+		return array, nil
+	case 0xd:
+		start, count, err := d.count(offset, info)
+		if err != nil {
+			return nil, err
+		}
+		refs, err := d.refs(start, count*2)
+		if err != nil {
+			return nil, err
+		}
+		dict := make(map[string]any, count)
+		for i := 0; i < count; i++ {
+			key, err := d.object(refs[i])
+			if err != nil {
+				return nil, err
+			}
+			name, ok := key.(string)
+			if !ok {
+				return nil, errors.Errorf("dictionary key of object %d is not a string", ref)
+			}
+			value, err := d.object(refs[count+i])
+			if err != nil {
+				return nil, errors.Wrapf(err, "error when decoding the value of key %q", name)
+			}
+			dict[name] = value
+		}
+		return dict, nil
+	}
+	return nil, errors.Errorf("unsupported object marker 0x%02x", marker)
+}
+
+// count returns where the contents of a sized object start and their count,
+// which is either encoded in the marker or in the integer object that follows it.
+func (d *binaryDecoder) count(offset uint64, info byte) (uint64, int, error) {
+	if info != 0x0f {
+		return offset + 1, int(info), nil
+	}
+	marker, err := d.bytes(offset+1, 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	if marker[0]>>4 != 0x1 || marker[0]&0x0f > 3 {
+		return 0, 0, errors.Errorf("invalid count of object at offset %d", offset)
+	}
+	size := 1 << (marker[0] & 0x0f)
+	raw, err := d.bytes(offset+2, size)
+	if err != nil {
+		return 0, 0, err
+	}
+	count := readUint(raw)
+	if count > uint64(len(d.data)) {
+		return 0, 0, errors.Errorf("invalid count of object at offset %d", offset)
+	}
+	return offset + 2 + uint64(size), int(count), nil
+}
+
+// refs returns the object references that start at the given offset.
+func (d *binaryDecoder) refs(offset uint64, count int) ([]uint64, error) {
+	raw, err := d.bytes(offset, count*d.objectRefSize)
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]uint64, count)
+	for i := range refs {
+		refs[i] = readUint(raw[i*d.objectRefSize : (i+1)*d.objectRefSize])
+	}
+	return refs, nil
+}
+
+// bytes returns the given number of bytes that start at the given offset.
+func (d *binaryDecoder) bytes(offset uint64, size int) ([]byte, error) {
+	end := offset + uint64(size)
+	if size < 0 || end < offset || end > uint64(len(d.data)-binaryTrailerSize) {
+		return nil, errors.Errorf("object at offset %d is out of bounds", offset)
+	}
+	return d.data[offset:end], nil
+}
+
+// readUint reads a big-endian unsigned integer of up to 8 bytes.
+func readUint(raw []byte) uint64 {
+	var value uint64
+	for _, b := range raw {
+		value = value<<8 | uint64(b)
+	}
+	return value
+}
+
+// binaryObject is an object of a binary property list being encoded,
+// with the references to the objects it holds.
+type binaryObject struct {
+	value any
+	refs  []int
+}
+
+// encodeBinary encodes the property list value in the binary format.
+func encodeBinary(value any) []byte {
+	var objects []binaryObject
+	var add func(value any) int
+	add = func(value any) int {
+		ref := len(objects)
+		objects = append(objects, binaryObject{value: value})
+		var refs []int
+		switch v := value.(type) {
+		case []any:
+			for _, item := range v {
+				refs = append(refs, add(item))
+			}
+		case *dict:
+			for _, key := range v.keys {
+				refs = append(refs, add(key))
+			}
+			for _, item := range v.values {
+				refs = append(refs, add(item))
+			}
+		}
+		objects[ref].refs = refs
+		return ref
+	}
+	add(value)
+
+	objectRefSize := uintSize(uint64(len(objects) - 1))
+	data := []byte(binaryMagic)
+	offsets := make([]uint64, len(objects))
+	for i, object := range objects {
+		offsets[i] = uint64(len(data))
+		data = appendBinaryObject(data, object, objectRefSize)
+	}
+	offsetTableOffset := uint64(len(data))
+	offsetIntSize := uintSize(offsetTableOffset)
+	for _, offset := range offsets {
+		data = appendUint(data, offset, offsetIntSize)
+	}
+	trailer := make([]byte, binaryTrailerSize)
+	trailer[6] = byte(offsetIntSize)
+	trailer[7] = byte(objectRefSize)
+	binary.BigEndian.PutUint64(trailer[8:16], uint64(len(objects)))
+	binary.BigEndian.PutUint64(trailer[24:32], offsetTableOffset)
+	return append(data, trailer...)
+}
+
+// appendBinaryObject appends the marker and the contents of the object.
+func appendBinaryObject(data []byte, object binaryObject, objectRefSize int) []byte {
+	switch v := object.value.(type) {
+	case bool:
+		if v {
+			return append(data, 0x09)
+		}
+		return append(data, 0x08)
+	case int64:
+		return appendBinaryInt(data, v)
+	case float64:
+		return binary.BigEndian.AppendUint64(append(data, 0x23), math.Float64bits(v))
+	case time.Time:
+		seconds := v.Sub(referenceDate).Seconds()
+		return binary.BigEndian.AppendUint64(append(data, 0x33), math.Float64bits(seconds))
+	case []byte:
+		return append(appendBinaryMarker(data, 0x4, len(v)), v...)
+	case string:
+		if isASCII(v) {
+			return append(appendBinaryMarker(data, 0x5, len(v)), v...)
+		}
+		units := utf16.Encode([]rune(v))
+		data = appendBinaryMarker(data, 0x6, len(units))
+		for _, unit := range units {
+			data = binary.BigEndian.AppendUint16(data, unit)
+		}
+		return data
+	case []any:
+		data = appendBinaryMarker(data, 0xa, len(object.refs))
+	case *dict:
+		data = appendBinaryMarker(data, 0xd, len(object.refs)/2)
+	}
+	for _, ref := range object.refs {
+		data = appendUint(data, uint64(ref), objectRefSize)
+	}
+	return data
+}
+
+// appendBinaryMarker appends the marker of a sized object, with the
+// count encoded in it or, when too large, in the integer object that follows it.
+func appendBinaryMarker(data []byte, kind byte, count int) []byte {
+	if count < 0x0f {
+		return append(data, kind<<4|byte(count))
+	}
+	return appendBinaryInt(append(data, kind<<4|0x0f), int64(count))
+}
+
+// appendBinaryInt appends an integer object in the smallest size that holds it.
+// Negative integers are always 8 bytes long.
+func appendBinaryInt(data []byte, value int64) []byte {
+	size := 8
+	if value >= 0 {
+		size = uintSize(uint64(value))
+		if size == 3 {
+			size = 4
+		} else if size > 4 {
+			size = 8
+		}
+	}
+	return appendUint(append(data, 0x10|byte(bits.TrailingZeros(uint(size)))), uint64(value), size)
+}
+
+// appendUint appends a big-endian unsigned integer of the given size.
+func appendUint(data []byte, value uint64, size int) []byte {
+	for i := size - 1; i >= 0; i-- {
+		data = append(data, byte(value>>(8*i)))
+	}
+	return data
+}
+
+// uintSize returns the number of bytes needed to hold the unsigned integer, at least one.
+func uintSize(value uint64) int {
+	return max(1, (bits.Len64(value)+7)/8)
+}
+
+// isASCII reports whether the string only holds ASCII characters.
+func isASCII(s string) bool {
+	for i := range len(s) {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
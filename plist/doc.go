@@ -0,0 +1,3 @@
+// Package plist provides functionality to decode and encode property
+// lists in the XML and binary formats used by macOS.
+package plist
@@ -0,0 +1,306 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package plist
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"maps"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// xmlHeader is written before the root value of XML property lists.
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+`
+
+var (
+	timeType  = reflect.TypeFor[time.Time]()
+	bytesType = reflect.TypeFor[[]byte]()
+)
+
+// Encode encodes the value as a property list in the XML format.
+//
+// Structs and maps with string keys are encoded as dictionaries, slices and
+// arrays as arrays, strings as strings, integers as integers, floats as reals,
+// booleans as booleans, time.Time as dates and []byte as data. Pointers and
+// interfaces are encoded as the value they hold; nil ones are left out of
+// dictionaries and arrays. Map entries are sorted by key.
+//
+// Struct fields are encoded in the order they are declared, under the key
+// given by their plist tag, e.g. `plist:"CFBundleName"`, or under their name
+// when untagged. Unexported fields and fields tagged "-" are skipped. The
+// "omitempty" option leaves out fields with a zero value or no elements, and
+// the "inline" option merges the entries of a map field into the dictionary.
+func Encode(v any) ([]byte, error) {
+	value, err := toValue(v)
+	if err != nil {
+		return nil, err
+	}
+	e := &xmlEncoder{}
+	e.buf.WriteString(xmlHeader)
+	e.encode(value, 0)
+	e.buf.WriteString("</plist>\n")
+	return e.buf.Bytes(), nil
+}
+
+// EncodeBinary encodes the value as a property list in the binary format,
+// following the same rules as Encode.
+func EncodeBinary(v any) ([]byte, error) {
+	value, err := toValue(v)
+	if err != nil {
+		return nil, err
+	}
+	return encodeBinary(value), nil
+}
+
+// dict is a dictionary whose entries keep the order they are encoded in.
+type dict struct {
+	keys   []string
+	values []any
+}
+
+// toValue converts the value to the property list values both formats are
+// encoded from: string, bool, int64, float64, time.Time, []byte, []any and *dict.
+func toValue(v any) (any, error) {
+	value := reflect.ValueOf(v)
+	if isNil(value) {
+		return nil, errors.New("cannot encode nil")
+	}
+	return convert(value)
+}
+
+// convert converts the reflected value to a property list value.
+func convert(value reflect.Value) (any, error) {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+	switch {
+	case value.Type() == timeType:
+		return value.Interface().(time.Time), nil
+	case value.Type() == bytesType:
+		return append([]byte(nil), value.Bytes()...), nil
+	}
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Bool:
+		return value.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if value.Uint() > math.MaxInt64 {
+			return nil, errors.Errorf("integer %d is too large", value.Uint())
+		}
+		return int64(value.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), nil
+	case reflect.Slice, reflect.Array:
+		array := []any{}
+		for i := range value.Len() {
+			item := value.Index(i)
+			if isNil(item) {
+				continue
+			}
+			converted, err := convert(item)
+			if err != nil {
+				return nil, errors.Wrapf(err, "error when encoding item %d", i)
+			}
+			array = append(array, converted)
+		}
+		return array, nil
+	case reflect.Map:
+		entries, err := mapEntries(value)
+		if err != nil {
+			return nil, err
+		}
+		return convertEntries(entries)
+	case reflect.Struct:
+		entries, err := structEntries(value)
+		if err != nil {
+			return nil, err
+		}
+		return convertEntries(entries)
+	}
+	return nil, errors.Errorf("cannot encode a value of type %s", value.Type())
+}
+
+// dictEntry is an entry of a dictionary being encoded.
+type dictEntry struct {
+	key   string
+	value reflect.Value
+}
+
+// convertEntries converts the entries to a dictionary.
+func convertEntries(entries []dictEntry) (*dict, error) {
+	d := &dict{}
+	for _, entry := range entries {
+		value, err := convert(entry.value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error when encoding the value of key %q", entry.key)
+		}
+		d.keys = append(d.keys, entry.key)
+		d.values = append(d.values, value)
+	}
+	return d, nil
+}
+
+// xmlEncoder writes the elements of an XML property list.
+type xmlEncoder struct {
+	buf bytes.Buffer
+}
+
+// encode writes the element of the value, indented by the given depth.
+func (e *xmlEncoder) encode(value any, depth int) {
+	switch v := value.(type) {
+	case string:
+		e.element(depth, "string", v)
+	case bool:
+		if v {
+			e.line(depth, "<true/>")
+		} else {
+			e.line(depth, "<false/>")
+		}
+	case int64:
+		e.element(depth, "integer", strconv.FormatInt(v, 10))
+	case float64:
+		e.element(depth, "real", strconv.FormatFloat(v, 'g', -1, 64))
+	case time.Time:
+		e.element(depth, "date", v.UTC().Format(time.RFC3339))
+	case []byte:
+		e.element(depth, "data", base64.StdEncoding.EncodeToString(v))
+	case []any:
+		if len(v) == 0 {
+			e.line(depth, "<array/>")
+			return
+		}
+		e.line(depth, "<array>")
+		for _, item := range v {
+			e.encode(item, depth+1)
+		}
+		e.line(depth, "</array>")
+	case *dict:
+		if len(v.keys) == 0 {
+			e.line(depth, "<dict/>")
+			return
+		}
+		e.line(depth, "<dict>")
+		for i, key := range v.keys {
+			e.element(depth+1, "key", key)
+			e.encode(v.values[i], depth+1)
+		}
+		e.line(depth, "</dict>")
+	}
+}
+
+// element writes an element holding the given text.
+func (e *xmlEncoder) element(depth int, name, text string) {
+	var escaped bytes.Buffer
+	_ = xml.EscapeText(&escaped, []byte(text))
+	e.line(depth, "<"+name+">"+escaped.String()+"</"+name+">")
+}
+
+// line writes a line indented by the given depth.
+func (e *xmlEncoder) line(depth int, s string) {
+	e.buf.WriteString(strings.Repeat("\t", depth))
+	e.buf.WriteString(s)
+	e.buf.WriteByte('\n')
+}
+
+// mapEntries returns the entries of the map, sorted by key, skipping nil values.
+func mapEntries(value reflect.Value) ([]dictEntry, error) {
+	if value.Type().Key().Kind() != reflect.String {
+		return nil, errors.Errorf("cannot encode a map with keys of type %s", value.Type().Key())
+	}
+	values := map[string]reflect.Value{}
+	for iter := value.MapRange(); iter.Next(); {
+		if !isNil(iter.Value()) {
+			values[iter.Key().String()] = iter.Value()
+		}
+	}
+	var entries []dictEntry
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		entries = append(entries, dictEntry{key: key, value: values[key]})
+	}
+	return entries, nil
+}
+
+// structEntries returns the entries of the struct, in the order its fields are declared.
+func structEntries(value reflect.Value) ([]dictEntry, error) {
+	var entries []dictEntry
+	keys := map[string]bool{}
+	add := func(entry dictEntry) error {
+		if keys[entry.key] {
+			return errors.Errorf("key %q is declared more than once in %s", entry.key, value.Type())
+		}
+		keys[entry.key] = true
+		entries = append(entries, entry)
+		return nil
+	}
+	for i := range value.NumField() {
+		field := value.Type().Field(i)
+		tag := field.Tag.Get("plist")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+		key, options, _ := strings.Cut(tag, ",")
+		if key == "" {
+			key = field.Name
+		}
+		fieldValue := value.Field(i)
+		if isNil(fieldValue) || slices.Contains(strings.Split(options, ","), "omitempty") && isEmpty(fieldValue) {
+			continue
+		}
+		if !slices.Contains(strings.Split(options, ","), "inline") {
+			if err := add(dictEntry{key: key, value: fieldValue}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if fieldValue.Kind() != reflect.Map {
+			return nil, errors.Errorf("field %s of %s is inline but not a map", field.Name, value.Type())
+		}
+		inlined, err := mapEntries(fieldValue)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range inlined {
+			if err := add(entry); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return entries, nil
+}
+
+// isNil reports whether the value is invalid or a nil pointer or interface.
+func isNil(value reflect.Value) bool {
+	if !value.IsValid() {
+		return true
+	}
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return value.IsNil()
+	}
+	return false
+}
+
+// isEmpty reports whether the value is zero or has no elements.
+func isEmpty(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return value.Len() == 0
+	}
+	return value.IsZero()
+}
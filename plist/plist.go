@@ -0,0 +1,46 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package plist
+
+import (
+	"bytes"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// for ease of unit testing.
+var osReadFile = os.ReadFile
+
+// binaryMagic is the header of binary property lists.
+const binaryMagic = "bplist00"
+
+// referenceDate is the date property list dates are relative to.
+var referenceDate = time.Date(2001, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Decode decodes a property list in the XML or binary format.
+// Dictionaries are decoded as map[string]any, arrays as []any, strings as string,
+// integers as int64, reals as float64, booleans as bool, dates as time.Time
+// and data as []byte.
+func Decode(data []byte) (any, error) {
+	if bytes.HasPrefix(data, []byte(binaryMagic)) {
+		return decodeBinary(data)
+	}
+	return decodeXML(data)
+}
+
+// DecodeFile decodes the property list file at the given path.
+func DecodeFile(path string) (any, error) {
+	data, err := osReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when reading [%s]", path)
+	}
+	value, err := Decode(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when decoding [%s]", path)
+	}
+	return value, nil
+}
@@ -0,0 +1,375 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package plist
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// testdataValue is the value stored in the property lists under testdata.
+var testdataValue = map[string]any{
+	"CFBundleExecutable":      "MyApp",
+	"CFBundleIdentifier":      "com.example.myapp",
+	"CFBundleName":            "Café ☕",
+	"NSHighResolutionCapable": true,
+	"LSUIElement":             false,
+	"Count":                   int64(42),
+	"Negative":                int64(-7),
+	"Big":                     int64(1 << 40),
+	"Ratio":                   0.5,
+	"Created":                 time.Date(2025, time.June, 1, 12, 30, 0, 0, time.UTC),
+	"Blob":                    []byte("\x00\x01\x02hello"),
+	"Architectures":           []any{"arm64", "x86_64"},
+	"Nested": map[string]any{
+		"Empty": []any{},
+		"Inner": map[string]any{"Key": "Value"},
+	},
+}
+
+func TestDecode(t *testing.T) {
+	testCases := []struct {
+		name          string
+		data          func() []byte
+		expected      any
+		expectedError string
+	}{
+		{
+			name: "xml",
+			data: func() []byte {
+				return readTestdata(t, "Info.xml.plist")
+			},
+			expected: testdataValue,
+		},
+		{
+			name: "binary",
+			data: func() []byte {
+				return readTestdata(t, "Info.binary.plist")
+			},
+			expected: testdataValue,
+		},
+		{
+			name: "xml without declarations",
+			data: func() []byte {
+				return []byte(`<plist><array><string>a</string><integer>0x10</integer></array></plist>`)
+			},
+			expected: []any{"a", int64(16)},
+		},
+		{
+			name: "empty",
+			data: func() []byte {
+				return nil
+			},
+			expectedError: "not a property list",
+		},
+		{
+			name: "not a property list",
+			data: func() []byte {
+				return []byte(`<html></html>`)
+			},
+			expectedError: "not a property list: unexpected root element <html>",
+		},
+		{
+			name: "property list without value",
+			data: func() []byte {
+				return []byte(`<plist version="1.0"></plist>`)
+			},
+			expectedError: "unexpected </plist>",
+		},
+		{
+			name: "key without value",
+			data: func() []byte {
+				return []byte(`<plist><dict><key>a</key></dict></plist>`)
+			},
+			expectedError: `error when reading the value of key "a": unexpected </dict>`,
+		},
+		{
+			name: "value without key",
+			data: func() []byte {
+				return []byte(`<plist><dict><string>a</string></dict></plist>`)
+			},
+			expectedError: "expected <key> in <dict>, found <string>",
+		},
+		{
+			name: "invalid integer",
+			data: func() []byte {
+				return []byte(`<plist><dict><key>a</key><integer>one</integer></dict></plist>`)
+			},
+			expectedError: `error when decoding the value of key "a": invalid integer "one"`,
+		},
+		{
+			name: "unsupported element",
+			data: func() []byte {
+				return []byte(`<plist><set/></plist>`)
+			},
+			expectedError: "unsupported element <set>",
+		},
+		{
+			name: "truncated xml",
+			data: func() []byte {
+				return []byte(`<plist><array><string>a</string>`)
+			},
+			expectedError: "XML syntax error on line 1: unexpected EOF",
+		},
+		{
+			name: "truncated binary",
+			data: func() []byte {
+				return []byte(binaryMagic)
+			},
+			expectedError: "binary property list is too short",
+		},
+		{
+			name: "invalid binary trailer",
+			data: func() []byte {
+				data := readTestdata(t, "Info.binary.plist")
+				// point the top object past the number of objects.
+				data[len(data)-9] = 0xff
+				return data
+			},
+			expectedError: "invalid binary property list trailer",
+		},
+		{
+			name: "self-referencing binary array",
+			data: func() []byte {
+				// a single array object whose only item is itself.
+				data := []byte(binaryMagic)
+				data = append(data, 0xa1, 0x00)
+				data = append(data, byte(len(binaryMagic)))
+				trailer := make([]byte, binaryTrailerSize)
+				trailer[6], trailer[7] = 1, 1
+				trailer[15] = 1
+				trailer[31] = byte(len(binaryMagic) + 2)
+				return append(data, trailer...)
+			},
+			expectedError: "object 0 references itself",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			value, err := Decode(tc.data())
+			if err != nil {
+				if tc.expectedError == "" {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError, err.Error())
+				return
+			}
+			if tc.expectedError != "" {
+				t.Fatalf(`expected error "%v", got nil`, tc.expectedError)
+			}
+			require.Equal(t, tc.expected, value)
+		})
+	}
+}
+
+func TestDecodeFile(t *testing.T) {
+	testCases := []struct {
+		name          string
+		mockReadFile  func(name string) ([]byte, error)
+		expected      any
+		expectedError string
+	}{
+		{
+			name: "happy path",
+			mockReadFile: func(name string) ([]byte, error) {
+				return []byte(`<plist><string>a</string></plist>`), nil
+			},
+			expected: "a",
+		},
+		{
+			name: "error when reading",
+			mockReadFile: func(name string) ([]byte, error) {
+				return nil, errors.New("read error")
+			},
+			expectedError: "error when reading [Info.plist]: read error",
+		},
+		{
+			name: "error when decoding",
+			mockReadFile: func(name string) ([]byte, error) {
+				return []byte(`{}`), nil
+			},
+			expectedError: "error when decoding [Info.plist]: not a property list",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			osReadFile = tc.mockReadFile
+			defer func() { osReadFile = os.ReadFile }()
+			value, err := DecodeFile("Info.plist")
+			if err != nil {
+				if tc.expectedError == "" {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError, err.Error())
+				return
+			}
+			if tc.expectedError != "" {
+				t.Fatalf(`expected error "%v", got nil`, tc.expectedError)
+			}
+			require.Equal(t, tc.expected, value)
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	type agent struct {
+		Label       string            `plist:"Label"`
+		Arguments   []string          `plist:"ProgramArguments,omitempty"`
+		RunAtLoad   bool              `plist:"RunAtLoad,omitempty"`
+		KeepAlive   *bool             `plist:"KeepAlive"`
+		Nice        int               `plist:",omitempty"`
+		Environment map[string]string `plist:",inline"`
+		Comment     string            `plist:"-"`
+		internal    string
+	}
+	keepAlive := false
+	testCases := []struct {
+		name          string
+		value         any
+		expected      string
+		expectedError string
+	}{
+		{
+			name: "struct",
+			value: &agent{
+				Label:       "com.example.<agent>",
+				Arguments:   []string{"--serve", "&"},
+				KeepAlive:   &keepAlive,
+				Nice:        -5,
+				Environment: map[string]string{"B": "2", "A": "1"},
+				Comment:     "skipped",
+				internal:    "skipped",
+			},
+			expected: `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>com.example.&lt;agent&gt;</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>--serve</string>
+		<string>&amp;</string>
+	</array>
+	<key>KeepAlive</key>
+	<false/>
+	<key>Nice</key>
+	<integer>-5</integer>
+	<key>A</key>
+	<string>1</string>
+	<key>B</key>
+	<string>2</string>
+</dict>
+</plist>
+`,
+		},
+		{
+			name:  "empty containers",
+			value: map[string]any{"Array": []string{}, "Dict": map[string]int{}, "Nil": nil},
+			expected: `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Array</key>
+	<array/>
+	<key>Dict</key>
+	<dict/>
+</dict>
+</plist>
+`,
+		},
+		{
+			name:          "nil",
+			value:         nil,
+			expectedError: "cannot encode nil",
+		},
+		{
+			name:          "unsupported type",
+			value:         map[string]any{"Func": func() {}},
+			expectedError: `error when encoding the value of key "Func": cannot encode a value of type func()`,
+		},
+		{
+			name:          "map with non-string keys",
+			value:         map[int]string{1: "a"},
+			expectedError: "cannot encode a map with keys of type int",
+		},
+		{
+			name: "inline key declared twice",
+			value: agent{
+				Label:       "com.example.agent",
+				Environment: map[string]string{"Label": "other"},
+			},
+			expectedError: `key "Label" is declared more than once in plist.agent`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := Encode(tc.value)
+			if err != nil {
+				if tc.expectedError == "" {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError, err.Error())
+				return
+			}
+			if tc.expectedError != "" {
+				t.Fatalf(`expected error "%v", got nil`, tc.expectedError)
+			}
+			require.Equal(t, tc.expected, string(data))
+		})
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	many := make([]any, 300)
+	for i := range many {
+		many[i] = int64(i * 1000)
+	}
+	values := map[string]any{
+		"testdata":  testdataValue,
+		"large":     map[string]any{"Many": many, "Long": strings.Repeat("ação ", 20), "Negative": int64(-1 << 40)},
+		"top array": []any{"a", true, 1.25},
+	}
+	for name, value := range values {
+		for format, encode := range map[string]func(v any) ([]byte, error){
+			"xml":    Encode,
+			"binary": EncodeBinary,
+		} {
+			t.Run(name+" "+format, func(t *testing.T) {
+				data, err := encode(value)
+				require.NoError(t, err)
+				decoded, err := Decode(data)
+				require.NoError(t, err)
+				require.Equal(t, value, decoded)
+			})
+		}
+	}
+}
+
+func TestEncodeBinary(t *testing.T) {
+	data, err := EncodeBinary(map[string]any{"a": true})
+	require.NoError(t, err)
+	require.Equal(t, append([]byte(binaryMagic+"\xd1\x01\x02\x51a\x09\x08\x0b\x0d"),
+		0, 0, 0, 0, 0, 0, 1, 1,
+		0, 0, 0, 0, 0, 0, 0, 3,
+		0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0x0e,
+	), data)
+
+	_, err = EncodeBinary(map[string]uint64{"a": 1 << 63})
+	require.EqualError(t, err, `error when encoding the value of key "a": integer 9223372036854775808 is too large`)
+}
+
+func readTestdata(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + name)
+	require.NoError(t, err)
+	return data
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package plist
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// decodeXML decodes a property list in the XML format.
+func decodeXML(data []byte) (any, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	root, err := nextElement(decoder)
+	if err != nil {
+		if err == io.EOF {
+			return nil, errors.New("not a property list")
+		}
+		return nil, err
+	}
+	if root.Name.Local != "plist" {
+		return nil, errors.Errorf("not a property list: unexpected root element <%s>", root.Name.Local)
+	}
+	start, err := nextElement(decoder)
+	if err != nil {
+		return nil, err
+	}
+	return decodeXMLValue(decoder, start)
+}
+
+// nextElement returns the next start element, skipping character data,
+// comments, processing instructions and directives. It fails when
+// an end element is found instead.
+func nextElement(decoder *xml.Decoder) (xml.StartElement, error) {
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return xml.StartElement{}, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			return t, nil
+		case xml.EndElement:
+			return xml.StartElement{}, errors.Errorf("unexpected </%s>", t.Name.Local)
+		}
+	}
+}
+
+// decodeXMLValue decodes the value that starts with the given element.
+func decodeXMLValue(decoder *xml.Decoder, start xml.StartElement) (any, error) {
+	switch start.Name.Local {
+	case "dict":
+		return decodeXMLDict(decoder)
+	case "array":
+		return decodeXMLArray(decoder)
+	case "true", "false":
+		if err := decoder.Skip(); err != nil {
+			return nil, err
+		}
+		return start.Name.Local == "true", nil
+	}
+	text, err := elementText(decoder, start)
+	if err != nil {
+		return nil, err
+	}
+	switch start.Name.Local {
+	case "string":
+		return text, nil
+	case "integer":
+		value, err := strconv.ParseInt(strings.TrimSpace(text), 0, 64)
+		if err != nil {
+			return nil, errors.Errorf("invalid integer %q", text)
+		}
+		return value, nil
+	case "real":
+		value, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil {
+			return nil, errors.Errorf("invalid real %q", text)
+		}
+		return value, nil
+	case "date":
+		value, err := time.Parse(time.RFC3339, strings.TrimSpace(text))
+		if err != nil {
+			return nil, errors.Errorf("invalid date %q", text)
+		}
+		return value, nil
+	case "data":
+		value, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(text), ""))
+		if err != nil {
+			return nil, errors.Errorf("invalid data %q", text)
+		}
+		return value, nil
+	}
+	return nil, errors.Errorf("unsupported element <%s>", start.Name.Local)
+}
+
+// decodeXMLDict decodes the entries of a dictionary up to its end element.
+func decodeXMLDict(decoder *xml.Decoder) (map[string]any, error) {
+	dict := map[string]any{}
+	for {
+		key, done, err := nextChild(decoder)
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			return dict, nil
+		}
+		if key.Name.Local != "key" {
+			return nil, errors.Errorf("expected <key> in <dict>, found <%s>", key.Name.Local)
+		}
+		name, err := elementText(decoder, key)
+		if err != nil {
+			return nil, err
+		}
+		start, err := nextElement(decoder)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error when reading the value of key %q", name)
+		}
+		value, err := decodeXMLValue(decoder, start)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error when decoding the value of key %q", name)
+		}
+		dict[name] = value
+	}
+}
+
+// decodeXMLArray decodes the items of an array up to its end element.
+func decodeXMLArray(decoder *xml.Decoder) ([]any, error) {
+	array := []any{}
+	for {
+		start, done, err := nextChild(decoder)
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			return array, nil
+		}
+		value, err := decodeXMLValue(decoder, start)
+		if err != nil {
+			return nil, err
+		}
+		array = append(array, value)
+	}
+}
+
+// nextChild returns the next child element of a container,
+// or done when the end element of the container is found.
+func nextChild(decoder *xml.Decoder) (start xml.StartElement, done bool, err error) {
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return xml.StartElement{}, false, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			return t, false, nil
+		case xml.EndElement:
+			return xml.StartElement{}, true, nil
+		}
+	}
+}
+
+// elementText returns the character data of a text-only element.
+func elementText(decoder *xml.Decoder, start xml.StartElement) (string, error) {
+	var text strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := token.(type) {
+		case xml.CharData:
+			text.Write(t)
+		case xml.StartElement:
+			return "", errors.Errorf("unexpected <%s> in <%s>", t.Name.Local, start.Name.Local)
+		case xml.EndElement:
+			return text.String(), nil
+		}
+	}
+}
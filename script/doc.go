@@ -0,0 +1,3 @@
+// Package script provides functionality to inspect the
+// scripts used as entry points of application bundles.
+package script
@@ -0,0 +1,128 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package script
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// shebang is the prefix of the first line of a script that names its interpreter.
+	shebang = "#!"
+
+	// maxShebangLength is the length of the longest shebang line macOS reads.
+	maxShebangLength = 512
+
+	// envPath is the path of the env command, used to find the interpreter in the PATH.
+	envPath = "/usr/bin/env"
+)
+
+// Info holds the information of an inspected script.
+type Info struct {
+	// Interpreter is the absolute path of the interpreter named in the shebang line,
+	// e.g. "/bin/sh" or "/usr/bin/env".
+	Interpreter string
+
+	// Command is the command /usr/bin/env runs, e.g. "python3",
+	// when the interpreter is /usr/bin/env.
+	Command string
+}
+
+// String returns the interpreter followed by the command, if any.
+func (i *Info) String() string {
+	if i.Command == "" {
+		return i.Interpreter
+	}
+	return i.Interpreter + " " + i.Command
+}
+
+// Inspect checks that the file at the given path is a script macOS can run:
+// an executable file whose shebang line names the absolute path of its interpreter.
+func Inspect(path string) (*Info, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.Errorf("script [%s] does not exist", path)
+		}
+		return nil, errors.Wrapf(err, "error when checking if [%s] exists", path)
+	}
+	if !stat.Mode().IsRegular() {
+		return nil, errors.Errorf("script [%s] is not a file", path)
+	}
+	if stat.Mode().Perm()&0o111 == 0 {
+		return nil, errors.Errorf("script [%s] is not executable; make it executable with chmod +x", path)
+	}
+
+	line, err := readShebangLine(path)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, shebang) {
+		return nil, errors.Errorf("script [%s] does not start with a shebang line, e.g. #!/bin/sh", path)
+	}
+	if strings.HasSuffix(line, "\r") {
+		return nil, errors.Errorf("script [%s] has Windows line endings, which break its shebang line", path)
+	}
+	fields := strings.Fields(strings.TrimPrefix(line, shebang))
+	if len(fields) == 0 {
+		return nil, errors.Errorf("script [%s] has a shebang line without an interpreter", path)
+	}
+	info := &Info{Interpreter: fields[0]}
+	if !filepath.IsAbs(info.Interpreter) {
+		return nil, errors.Errorf("script [%s] names the interpreter [%s] by a relative path", path, info.Interpreter)
+	}
+	if info.Interpreter == envPath {
+		info.Command = envCommand(fields[1:])
+		if info.Command == "" {
+			return nil, errors.Errorf("script [%s] runs %s without a command", path, envPath)
+		}
+	}
+	return info, nil
+}
+
+// envCommand returns the command env runs with the given arguments, skipping its options,
+// e.g. -S, which splits the rest of the shebang line into arguments, and the variables it sets.
+func envCommand(args []string) string {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			if i+1 < len(args) {
+				return args[i+1]
+			}
+			return ""
+		case arg == "-P" || arg == "-u":
+			// options followed by their value.
+			i++
+		case strings.HasPrefix(arg, "-S") && len(arg) > 2:
+			// the string to split directly follows -S.
+			return envCommand(append([]string{arg[2:]}, args[i+1:]...))
+		case strings.HasPrefix(arg, "-"), strings.Contains(arg, "="):
+		default:
+			return arg
+		}
+	}
+	return ""
+}
+
+// readShebangLine returns the first line of the script, without its line feed.
+func readShebangLine(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "error when opening script [%s]", path)
+	}
+	defer f.Close()
+	line, err := bufio.NewReader(io.LimitReader(f, maxShebangLength)).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", errors.Wrapf(err, "error when reading script [%s]", path)
+	}
+	return strings.TrimSuffix(line, "\n"), nil
+}
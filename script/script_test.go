@@ -0,0 +1,168 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package script
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspect(t *testing.T) {
+	testCases := []struct {
+		name          string
+		setup         func(t *testing.T, path string)
+		expected      *Info
+		expectedError func(path string) string
+	}{
+		{
+			name: "happy path",
+			setup: func(t *testing.T, path string) {
+				require.NoError(t, os.WriteFile(path, []byte("#!/bin/bash -e\necho hello\n"), 0o755))
+			},
+			expected: &Info{Interpreter: "/bin/bash"},
+		},
+		{
+			name: "interpreter found with env",
+			setup: func(t *testing.T, path string) {
+				require.NoError(t, os.WriteFile(path, []byte("#! /usr/bin/env python3\nprint('hello')\n"), 0o700))
+			},
+			expected: &Info{Interpreter: "/usr/bin/env", Command: "python3"},
+		},
+		{
+			name: "env splitting its arguments",
+			setup: func(t *testing.T, path string) {
+				require.NoError(t, os.WriteFile(path, []byte("#!/usr/bin/env -S python3 -u\nprint('hello')\n"), 0o755))
+			},
+			expected: &Info{Interpreter: "/usr/bin/env", Command: "python3"},
+		},
+		{
+			name: "env splitting its arguments without a space",
+			setup: func(t *testing.T, path string) {
+				require.NoError(t, os.WriteFile(path, []byte("#!/usr/bin/env -Sruby -w\nputs 'hello'\n"), 0o755))
+			},
+			expected: &Info{Interpreter: "/usr/bin/env", Command: "ruby"},
+		},
+		{
+			name: "env with options and variables",
+			setup: func(t *testing.T, path string) {
+				require.NoError(t, os.WriteFile(path, []byte("#!/usr/bin/env -i -u HOME LANG=C node\nconsole.log('hello')\n"), 0o755))
+			},
+			expected: &Info{Interpreter: "/usr/bin/env", Command: "node"},
+		},
+		{
+			name: "shebang line only",
+			setup: func(t *testing.T, path string) {
+				require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh"), 0o755))
+			},
+			expected: &Info{Interpreter: "/bin/sh"},
+		},
+		{
+			name:  "does not exist",
+			setup: func(t *testing.T, path string) {},
+			expectedError: func(path string) string {
+				return fmt.Sprintf("script [%s] does not exist", path)
+			},
+		},
+		{
+			name: "not a file",
+			setup: func(t *testing.T, path string) {
+				require.NoError(t, os.Mkdir(path, 0o755))
+			},
+			expectedError: func(path string) string {
+				return fmt.Sprintf("script [%s] is not a file", path)
+			},
+		},
+		{
+			name: "not executable",
+			setup: func(t *testing.T, path string) {
+				require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"), 0o644))
+			},
+			expectedError: func(path string) string {
+				return fmt.Sprintf("script [%s] is not executable; make it executable with chmod +x", path)
+			},
+		},
+		{
+			name: "no shebang line",
+			setup: func(t *testing.T, path string) {
+				require.NoError(t, os.WriteFile(path, []byte("echo hello\n"), 0o755))
+			},
+			expectedError: func(path string) string {
+				return fmt.Sprintf("script [%s] does not start with a shebang line, e.g. #!/bin/sh", path)
+			},
+		},
+		{
+			name: "windows line endings",
+			setup: func(t *testing.T, path string) {
+				require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\r\necho hello\r\n"), 0o755))
+			},
+			expectedError: func(path string) string {
+				return fmt.Sprintf("script [%s] has Windows line endings, which break its shebang line", path)
+			},
+		},
+		{
+			name: "no interpreter",
+			setup: func(t *testing.T, path string) {
+				require.NoError(t, os.WriteFile(path, []byte("#!\n"), 0o755))
+			},
+			expectedError: func(path string) string {
+				return fmt.Sprintf("script [%s] has a shebang line without an interpreter", path)
+			},
+		},
+		{
+			name: "relative interpreter",
+			setup: func(t *testing.T, path string) {
+				require.NoError(t, os.WriteFile(path, []byte("#!python3\n"), 0o755))
+			},
+			expectedError: func(path string) string {
+				return fmt.Sprintf("script [%s] names the interpreter [python3] by a relative path", path)
+			},
+		},
+		{
+			name: "env with options but without command",
+			setup: func(t *testing.T, path string) {
+				require.NoError(t, os.WriteFile(path, []byte("#!/usr/bin/env -S\n"), 0o755))
+			},
+			expectedError: func(path string) string {
+				return fmt.Sprintf("script [%s] runs /usr/bin/env without a command", path)
+			},
+		},
+		{
+			name: "env without command",
+			setup: func(t *testing.T, path string) {
+				require.NoError(t, os.WriteFile(path, []byte("#!/usr/bin/env\n"), 0o755))
+			},
+			expectedError: func(path string) string {
+				return fmt.Sprintf("script [%s] runs /usr/bin/env without a command", path)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "run.sh")
+			tc.setup(t, path)
+			got, err := Inspect(path)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError(path), err.Error())
+				return
+			}
+			if tc.expectedError != nil {
+				t.Fatalf(`expected error "%v", got nil`, tc.expectedError(path))
+			}
+			require.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestInfoString(t *testing.T) {
+	require.Equal(t, "/bin/sh", (&Info{Interpreter: "/bin/sh"}).String())
+	require.Equal(t, "/usr/bin/env python3", (&Info{Interpreter: "/usr/bin/env", Command: "python3"}).String())
+}
@@ -5,17 +5,53 @@
 package syscall
 
 import (
+	"bytes"
+	"os"
 	"os/exec"
 
 	"github.com/pkg/errors"
 )
 
+// CommandOptions holds optional settings for executing a command.
+type CommandOptions struct {
+	// Dir is the working directory of the command.
+	// When empty, the command runs in the current directory.
+	Dir string
+
+	// Env holds environment variables, in the form "key=value",
+	// that are set in addition to the ones of the current process.
+	Env []string
+
+	// Stdin is written to the standard input of the command, e.g. a passphrase.
+	// Unlike the arguments, it is not visible to other processes, and it is never
+	// included in error messages.
+	Stdin []byte
+}
+
 // execCommand is a variable that holds the function that executes a command with arguments.
 // It is a variable so that it can be mocked in tests.
 var execCommand = func(name string, arg ...string) ([]byte, error) {
 	return exec.Command(name, arg...).CombinedOutput()
 }
 
+// execCommandWithOptions is a variable that holds the function that executes
+// a command with arguments and options.
+// It is a variable so that it can be mocked in tests.
+var execCommandWithOptions = defaultExecCommandWithOptions
+
+// defaultExecCommandWithOptions executes a command with arguments and options.
+func defaultExecCommandWithOptions(opts *CommandOptions, name string, arg ...string) ([]byte, error) {
+	cmd := exec.Command(name, arg...)
+	cmd.Dir = opts.Dir
+	if len(opts.Env) > 0 {
+		cmd.Env = append(os.Environ(), opts.Env...)
+	}
+	if opts.Stdin != nil {
+		cmd.Stdin = bytes.NewReader(opts.Stdin)
+	}
+	return cmd.CombinedOutput()
+}
+
 // ExecCommand executes a command with arguments.
 func ExecCommand(cmd string, args ...string) (string, error) {
 	output, err := execCommand(cmd, args...)
@@ -24,3 +60,12 @@ func ExecCommand(cmd string, args ...string) (string, error) {
 	}
 	return string(output), nil
 }
+
+// ExecCommandWithOptions executes a command with arguments and options.
+func ExecCommandWithOptions(opts *CommandOptions, cmd string, args ...string) (string, error) {
+	output, err := execCommandWithOptions(opts, cmd, args...)
+	if err != nil {
+		return "", errors.Wrapf(err, "error when executing command [%s] with args %v and env %v: output: [%v]", cmd, args, opts.Env, string(output))
+	}
+	return string(output), nil
+}
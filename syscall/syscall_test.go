@@ -51,3 +51,77 @@ func TestExecCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestExecCommandWithOptions(t *testing.T) {
+	testCases := []struct {
+		name                       string
+		mockExecCommandWithOptions func(opts *CommandOptions, name string, arg ...string) ([]byte, error)
+		expectedOutput             []byte
+		expectedError              error
+	}{
+		{
+			name: "happy path",
+			mockExecCommandWithOptions: func(opts *CommandOptions, name string, arg ...string) ([]byte, error) {
+				return []byte("output"), nil
+			},
+			expectedOutput: []byte("output"),
+		},
+		{
+			name: "error",
+			mockExecCommandWithOptions: func(opts *CommandOptions, name string, arg ...string) ([]byte, error) {
+				return []byte("output"), errors.New("some error")
+			},
+			expectedError: errors.New("error when executing command [go] with args [build .] and env [GOOS=darwin]: output: [output]: some error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			execCommandWithOptions = tc.mockExecCommandWithOptions
+			output, err := ExecCommandWithOptions(&CommandOptions{Dir: "dir", Env: []string{"GOOS=darwin"}}, "go", "build", ".")
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.expectedError)
+				}
+				require.Equal(t, string(tc.expectedOutput), string(output))
+			}
+		})
+	}
+}
+
+func TestExecCommandWithOptionsStdin(t *testing.T) {
+	testCases := []struct {
+		name           string
+		args           []string
+		expectedOutput string
+		expectedError  bool
+	}{
+		{
+			name:           "happy path",
+			args:           []string{"-c", "cat"},
+			expectedOutput: "secret",
+		},
+		{
+			name:          "error",
+			args:          []string{"-c", "cat >/dev/null; exit 1"},
+			expectedError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			execCommandWithOptions = defaultExecCommandWithOptions
+			output, err := ExecCommandWithOptions(&CommandOptions{Stdin: []byte("secret")}, "sh", tc.args...)
+			if tc.expectedError {
+				require.Error(t, err)
+				require.NotContains(t, err.Error(), "secret")
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedOutput, output)
+		})
+	}
+}
@@ -0,0 +1,9 @@
+// Package udif reads and rewrites the resources of UDIF disk images (.dmg), the
+// property list referenced by the koly trailer at the end of the file, in pure Go.
+// It is used to embed software license agreements shown before the disk image mounts.
+//
+// It also reads disk images themselves, on any platform: Open parses the blkx block
+// tables of the property list and returns the uncompressed raw disk image as an
+// io.ReaderAt, decompressing its zlib and bzip2 chunks on demand. Chunks compressed
+// with ADC, LZFSE or LZMA are not supported.
+package udif
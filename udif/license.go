@@ -0,0 +1,193 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package udif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+)
+
+// licenseResourceID is the identifier of the LPic resource, and of the resources
+// of the first language; the following languages take the following identifiers.
+const licenseResourceID = 5000
+
+// rtfPrefix is the prefix of RTF documents.
+const rtfPrefix = `{\rtf`
+
+// resource types of license agreements.
+const (
+	typeLPic = "LPic"
+	typeSTR  = "STR#"
+	typeTEXT = "TEXT"
+	typeRTF  = "RTF "
+)
+
+// Buttons are the texts of the license agreement window, in the language of the license.
+type Buttons struct {
+	// LanguageName is the name of the language, shown in the language menu.
+	LanguageName string
+
+	// Agree, Disagree, Print and Save are the labels of the buttons.
+	Agree, Disagree, Print, Save string
+
+	// Message is the message shown when asking to agree to the license.
+	Message string
+}
+
+// englishButtons are the texts used for languages without buttons of their own.
+var englishButtons = Buttons{
+	LanguageName: "English",
+	Agree:        "Agree",
+	Disagree:     "Disagree",
+	Print:        "Print",
+	Save:         "Save...",
+	Message:      `If you agree with the terms of this license, press "Agree" to install the software. If you do not agree, press "Disagree".`,
+}
+
+// language is a language license agreements can be written in.
+type language struct {
+	// region is the classic Mac OS region code of the language.
+	region int16
+
+	// name is the name of the language, in the language.
+	name string
+
+	// encoding is the classic Mac OS text encoding of the language.
+	encoding encoding.Encoding
+
+	// doubleByte indicates whether the encoding uses two bytes per character.
+	doubleByte bool
+}
+
+// languages are the languages license agreements can be written in, by language code.
+var languages = map[string]language{
+	"en":      {region: 0, name: "English", encoding: charmap.Macintosh},
+	"fr":      {region: 1, name: "Français", encoding: charmap.Macintosh},
+	"de":      {region: 3, name: "Deutsch", encoding: charmap.Macintosh},
+	"it":      {region: 4, name: "Italiano", encoding: charmap.Macintosh},
+	"nl":      {region: 5, name: "Nederlands", encoding: charmap.Macintosh},
+	"sv":      {region: 7, name: "Svenska", encoding: charmap.Macintosh},
+	"es":      {region: 8, name: "Español", encoding: charmap.Macintosh},
+	"da":      {region: 9, name: "Dansk", encoding: charmap.Macintosh},
+	"pt":      {region: 10, name: "Português", encoding: charmap.Macintosh},
+	"nb":      {region: 12, name: "Norsk", encoding: charmap.Macintosh},
+	"ja":      {region: 14, name: "日本語", encoding: japanese.ShiftJIS, doubleByte: true},
+	"fi":      {region: 17, name: "Suomi", encoding: charmap.Macintosh},
+	"ru":      {region: 49, name: "Русский", encoding: charmap.MacintoshCyrillic},
+	"ko":      {region: 51, name: "한국어", encoding: korean.EUCKR, doubleByte: true},
+	"zh-Hans": {region: 52, name: "简体中文", encoding: simplifiedchinese.GBK, doubleByte: true},
+	"zh-Hant": {region: 53, name: "繁體中文", encoding: traditionalchinese.Big5, doubleByte: true},
+}
+
+// License is a license agreement in one language.
+type License struct {
+	// Language is the code of the language of the license, e.g. "en", "fr" or "zh-Hans".
+	Language string
+
+	// Text is the license, either plain UTF-8 text or an RTF document.
+	Text []byte
+
+	// Buttons are the texts of the license agreement window. When nil, the English
+	// texts are used, along with the name of the language.
+	Buttons *Buttons
+}
+
+// LicenseResources returns the resources of the license agreement shown before the
+// disk image mounts, in one or more languages; the first language is the default one.
+// The LPic resource lists the languages, and each language has its STR# resource
+// with the texts of the window and its TEXT or RTF resource with the license.
+func LicenseResources(licenses []License) (Resources, error) {
+	if len(licenses) == 0 {
+		return nil, errors.New("no license to encode")
+	}
+	resources := Resources{}
+	var entries []byte
+	seen := map[string]bool{}
+	for i, license := range licenses {
+		lang, ok := languages[license.Language]
+		if !ok {
+			return nil, errors.Errorf("unsupported license language [%s]", license.Language)
+		}
+		if seen[license.Language] {
+			return nil, errors.Errorf("license language [%s] is given more than once", license.Language)
+		}
+		seen[license.Language] = true
+		id := int16(licenseResourceID + i)
+
+		buttons := englishButtons
+		buttons.LanguageName = lang.name
+		if license.Buttons != nil {
+			buttons = *license.Buttons
+		}
+		strs, err := encodeStringList(lang.encoding, []string{
+			buttons.LanguageName, buttons.Agree, buttons.Disagree, buttons.Print, buttons.Save, buttons.Message,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "error when encoding the buttons of license [%s]", license.Language)
+		}
+		resources[typeSTR] = append(resources[typeSTR], Resource{ID: id, Name: buttons.LanguageName, Data: strs})
+
+		if bytes.HasPrefix(license.Text, []byte(rtfPrefix)) {
+			resources[typeRTF] = append(resources[typeRTF], Resource{ID: id, Name: buttons.LanguageName + " SLA", Data: license.Text})
+		} else {
+			text, err := encodeText(lang.encoding, string(license.Text))
+			if err != nil {
+				return nil, errors.Wrapf(err, "error when encoding license [%s]", license.Language)
+			}
+			resources[typeTEXT] = append(resources[typeTEXT], Resource{ID: id, Name: buttons.LanguageName + " SLA", Data: text})
+		}
+
+		entries = binary.BigEndian.AppendUint16(entries, uint16(lang.region))
+		entries = binary.BigEndian.AppendUint16(entries, uint16(i))
+		entries = binary.BigEndian.AppendUint16(entries, boolToUint16(lang.doubleByte))
+	}
+	lpic := binary.BigEndian.AppendUint16(nil, uint16(languages[licenses[0].Language].region))
+	lpic = binary.BigEndian.AppendUint16(lpic, uint16(len(licenses)))
+	resources[typeLPic] = []Resource{{ID: licenseResourceID, Data: append(lpic, entries...)}}
+	return resources, nil
+}
+
+// encodeStringList encodes the strings as a STR# resource: their count
+// followed by the strings, each prefixed by its length.
+func encodeStringList(enc encoding.Encoding, strs []string) ([]byte, error) {
+	data := binary.BigEndian.AppendUint16(nil, uint16(len(strs)))
+	for _, s := range strs {
+		encoded, err := enc.NewEncoder().String(s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error when encoding [%s]", s)
+		}
+		if len(encoded) > 255 {
+			return nil, errors.Errorf("[%s] is %d bytes long, more than 255", s, len(encoded))
+		}
+		data = append(append(data, byte(len(encoded))), encoded...)
+	}
+	return data, nil
+}
+
+// encodeText encodes the text as a TEXT resource, with carriage returns as line breaks.
+func encodeText(enc encoding.Encoding, text string) ([]byte, error) {
+	text = strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\r"), "\n", "\r")
+	encoded, err := enc.NewEncoder().String(text)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(encoded), nil
+}
+
+func boolToUint16(b bool) uint16 {
+	if b {
+		return 1
+	}
+	return 0
+}
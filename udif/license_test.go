@@ -0,0 +1,139 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package udif
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// englishSTR is the STR# resource with the English texts of the license agreement window.
+var englishSTR = concat(
+	[]byte{0x00, 0x06},
+	[]byte{0x07}, []byte("English"),
+	[]byte{0x05}, []byte("Agree"),
+	[]byte{0x08}, []byte("Disagree"),
+	[]byte{0x05}, []byte("Print"),
+	[]byte{0x07}, []byte("Save..."),
+	[]byte{0x7a}, []byte(`If you agree with the terms of this license, press "Agree" to install the software. If you do not agree, press "Disagree".`),
+)
+
+func TestLicenseResources(t *testing.T) {
+	testCases := []struct {
+		name          string
+		licenses      []License
+		expected      Resources
+		expectedError error
+	}{
+		{
+			name:     "plain text",
+			licenses: []License{{Language: "en", Text: []byte("Line 1\nLine 2\r\n")}},
+			expected: Resources{
+				"LPic": {{ID: 5000, Data: []byte{
+					0x00, 0x00, 0x00, 0x01,
+					0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				}}},
+				"STR#": {{ID: 5000, Name: "English", Data: englishSTR}},
+				"TEXT": {{ID: 5000, Name: "English SLA", Data: []byte("Line 1\rLine 2\r")}},
+			},
+		},
+		{
+			name: "several languages, plain text and RTF",
+			licenses: []License{
+				{Language: "fr", Text: []byte("Licence : à lire.")},
+				{Language: "en", Text: []byte(`{\rtf1\ansi License}`)},
+				{
+					Language: "ja",
+					Text:     []byte("使用許諾"),
+					Buttons:  &Buttons{LanguageName: "日本語", Agree: "同意", Disagree: "同意しない", Print: "印刷", Save: "保存...", Message: "同意"},
+				},
+			},
+			expected: Resources{
+				"LPic": {{ID: 5000, Data: []byte{
+					0x00, 0x01, 0x00, 0x03,
+					0x00, 0x01, 0x00, 0x00, 0x00, 0x00,
+					0x00, 0x00, 0x00, 0x01, 0x00, 0x00,
+					0x00, 0x0e, 0x00, 0x02, 0x00, 0x01,
+				}}},
+				"STR#": {
+					{ID: 5000, Name: "Français", Data: concat(
+						[]byte{0x00, 0x06},
+						[]byte{0x08}, []byte("Fran\x8dais"),
+						englishSTR[10:],
+					)},
+					{ID: 5001, Name: "English", Data: englishSTR},
+					{ID: 5002, Name: "日本語", Data: concat(
+						[]byte{0x00, 0x06},
+						[]byte{0x06, 0x93, 0xfa, 0x96, 0x7b, 0x8c, 0xea},
+						[]byte{0x04, 0x93, 0xaf, 0x88, 0xd3},
+						[]byte{0x0a, 0x93, 0xaf, 0x88, 0xd3, 0x82, 0xb5, 0x82, 0xc8, 0x82, 0xa2},
+						[]byte{0x04, 0x88, 0xf3, 0x8d, 0xfc},
+						[]byte{0x07, 0x95, 0xdb, 0x91, 0xb6, '.', '.', '.'},
+						[]byte{0x04, 0x93, 0xaf, 0x88, 0xd3},
+					)},
+				},
+				"TEXT": {
+					{ID: 5000, Name: "Français SLA", Data: []byte("Licence : \x88 lire.")},
+					{ID: 5002, Name: "日本語 SLA", Data: []byte{0x8e, 0x67, 0x97, 0x70, 0x8b, 0x96, 0x91, 0xf8}},
+				},
+				"RTF ": {{ID: 5001, Name: "English SLA", Data: []byte(`{\rtf1\ansi License}`)}},
+			},
+		},
+		{
+			name:          "no license",
+			expectedError: errors.New("no license to encode"),
+		},
+		{
+			name:          "unsupported language",
+			licenses:      []License{{Language: "xx", Text: []byte("License")}},
+			expectedError: errors.New("unsupported license language [xx]"),
+		},
+		{
+			name:          "language given more than once",
+			licenses:      []License{{Language: "en", Text: []byte("License")}, {Language: "en", Text: []byte("License")}},
+			expectedError: errors.New("license language [en] is given more than once"),
+		},
+		{
+			name:          "text not representable in the language encoding",
+			licenses:      []License{{Language: "en", Text: []byte("License ✓")}},
+			expectedError: errors.New("error when encoding license [en]: encoding: rune not supported by encoding."),
+		},
+		{
+			name: "button text too long",
+			licenses: []License{{Language: "en", Text: []byte("License"), Buttons: &Buttons{
+				LanguageName: "English", Agree: "Agree", Disagree: "Disagree", Print: "Print", Save: "Save...",
+				Message: strings.Repeat("a", 256),
+			}}},
+			expectedError: errors.New("error when encoding the buttons of license [en]: [" + strings.Repeat("a", 256) + "] is 256 bytes long, more than 255"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resources, err := LicenseResources(tc.licenses)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+				return
+			}
+			if tc.expectedError != nil {
+				t.Fatalf(`expected error "%v", got nil`, tc.expectedError)
+			}
+			require.Equal(t, tc.expected, resources)
+		})
+	}
+}
+
+func concat(parts ...[]byte) []byte {
+	var data []byte
+	for _, part := range parts {
+		data = append(data, part...)
+	}
+	return data
+}
@@ -0,0 +1,390 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package udif
+
+import (
+	"cmp"
+	"compress/bzip2"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/tiagomelo/macos-dmg-creator/plist"
+)
+
+// for ease of unit testing.
+var osOpen = os.Open
+
+const (
+	// SectorSize is the size of the sectors of UDIF disk images, in bytes.
+	SectorSize = 512
+
+	// blkxType is the type of the resources holding the block tables of the partitions.
+	blkxType = "blkx"
+
+	// blockTableSignature is the signature the mish block tables start with.
+	blockTableSignature = "mish"
+
+	// blockTableHeaderSize and chunkSize are the sizes of the header of a
+	// mish block table and of each of its chunk descriptors.
+	blockTableHeaderSize = 204
+	chunkSize            = 40
+
+	// maxChunkSectors is the largest number of sectors of the chunks whose data are
+	// stored in the file. hdiutil never writes larger ones, and a chunk is decompressed
+	// in one piece, so larger ones are rejected rather than allocated.
+	maxChunkSectors = 2048
+
+	// maxSectors is the largest number of sectors whose size, in bytes, fits an int64.
+	maxSectors = math.MaxInt64 / SectorSize
+)
+
+// chunk types of the mish block tables.
+const (
+	chunkZeroFill   uint32 = 0x00000000
+	chunkRaw        uint32 = 0x00000001
+	chunkIgnore     uint32 = 0x00000002
+	chunkADC        uint32 = 0x80000004
+	chunkZlib       uint32 = 0x80000005
+	chunkBzip2      uint32 = 0x80000006
+	chunkLZFSE      uint32 = 0x80000007
+	chunkLZMA       uint32 = 0x80000008
+	chunkComment    uint32 = 0x7ffffffe
+	chunkTerminator uint32 = 0xffffffff
+)
+
+// chunkTypeNames are the names of the chunk types, for error messages.
+var chunkTypeNames = map[uint32]string{
+	chunkADC:   "ADC",
+	chunkLZFSE: "LZFSE",
+	chunkLZMA:  "LZMA",
+}
+
+// Partition is a partition of a disk image, described by one of its blkx resources.
+type Partition struct {
+	// Name is the name of the partition, e.g. "disk image (Apple_HFS : 1)".
+	Name string
+
+	// Offset is the offset of the partition in the uncompressed image, in bytes.
+	Offset int64
+
+	// Size is the size of the partition, in bytes.
+	Size int64
+}
+
+// chunk is a run of sectors of the uncompressed image, stored in one piece of the file.
+type chunk struct {
+	// kind is the chunk type, e.g. chunkZlib.
+	kind uint32
+
+	// sector and sectorCount are the first sector of the chunk in
+	// the uncompressed image and its number of sectors.
+	sector, sectorCount int64
+
+	// offset and length locate the stored chunk in the file.
+	offset, length int64
+}
+
+// Image is a UDIF disk image opened for reading. It reads as the uncompressed
+// raw disk image, partition map included, through ReadAt, decompressing the chunks
+// it is stored in on demand. It is safe for concurrent use.
+type Image struct {
+	r      io.ReaderAt
+	closer io.Closer
+	size   int64
+	chunks []chunk
+
+	// Partitions are the partitions of the image, in the order of their sectors.
+	Partitions []Partition
+
+	// mu guards the last decompressed chunk, which is kept since reads are usually sequential.
+	mu          sync.Mutex
+	cachedChunk int
+	cachedData  []byte
+}
+
+// Open opens the UDIF disk image at the given path for reading.
+// The image must be closed once done with.
+func Open(path string) (*Image, error) {
+	f, err := osOpen(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when opening [%s]", path)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, errors.Wrapf(err, "error when reading file info of [%s]", path)
+	}
+	img, err := NewImage(f, info.Size())
+	if err != nil {
+		f.Close()
+		return nil, errors.Wrapf(err, "error when reading [%s]", path)
+	}
+	img.closer = f
+	return img, nil
+}
+
+// NewImage reads the koly trailer, the property list and the block tables of the UDIF
+// disk image stored in r, which is size bytes long, and returns the image they describe.
+func NewImage(r io.ReaderAt, size int64) (*Image, error) {
+	t, err := readTrailer(r, uint64(size))
+	if err != nil {
+		return nil, err
+	}
+	xml, err := readXML(r, t, uint64(size))
+	if err != nil {
+		return nil, err
+	}
+	blockTables, err := blkxResources(xml)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.sectorCount() > maxSectors {
+		return nil, errors.Errorf("the image of %d sectors is too large", t.sectorCount())
+	}
+
+	img := &Image{r: r, cachedChunk: -1}
+	for _, blockTable := range blockTables {
+		partition, chunks, err := parseBlockTable(blockTable.data, t.dataForkOffset(), size)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error when reading the block table of [%s]", blockTable.name)
+		}
+		partition.Name = blockTable.name
+		img.Partitions = append(img.Partitions, partition)
+		img.chunks = append(img.chunks, chunks...)
+	}
+	slices.SortFunc(img.Partitions, func(a, b Partition) int { return cmp.Compare(a.Offset, b.Offset) })
+	slices.SortFunc(img.chunks, func(a, b chunk) int { return cmp.Compare(a.sector, b.sector) })
+	for i := 1; i < len(img.chunks); i++ {
+		if previous := img.chunks[i-1]; previous.sector+previous.sectorCount > img.chunks[i].sector {
+			return nil, errors.Errorf("the chunks at sectors %d and %d overlap", previous.sector, img.chunks[i].sector)
+		}
+	}
+
+	img.size = int64(t.sectorCount()) * SectorSize
+	if len(img.chunks) > 0 {
+		last := img.chunks[len(img.chunks)-1]
+		img.size = max(img.size, (last.sector+last.sectorCount)*SectorSize)
+	}
+	return img, nil
+}
+
+// Size returns the size of the uncompressed image, in bytes.
+func (img *Image) Size() int64 {
+	return img.size
+}
+
+// ReadAt reads len(p) bytes of the uncompressed image starting at offset off.
+// Sectors that no chunk describes read as zeros.
+func (img *Image) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+	if off >= img.size {
+		return 0, io.EOF
+	}
+	n := 0
+	for n < len(p) && off < img.size {
+		// find the first chunk that ends after the offset.
+		i, _ := slices.BinarySearchFunc(img.chunks, off, func(c chunk, off int64) int {
+			if (c.sector+c.sectorCount)*SectorSize <= off {
+				return -1
+			}
+			return 1
+		})
+		end := off + min(int64(len(p)-n), img.size-off)
+		if i == len(img.chunks) || img.chunks[i].sector*SectorSize > off {
+			// a gap between chunks.
+			if i < len(img.chunks) {
+				end = min(end, img.chunks[i].sector*SectorSize)
+			}
+			clear(p[n : n+int(end-off)])
+		} else {
+			c := img.chunks[i]
+			chunkOffset := c.sector * SectorSize
+			end = min(end, chunkOffset+c.sectorCount*SectorSize)
+			if err := img.readChunk(i, p[n:n+int(end-off)], off-chunkOffset); err != nil {
+				return n, err
+			}
+		}
+		n += int(end - off)
+		off = end
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Close closes the file of the image opened by Open.
+func (img *Image) Close() error {
+	if img.closer == nil {
+		return nil
+	}
+	return img.closer.Close()
+}
+
+// readChunk reads len(p) bytes of the i-th chunk, starting at offset off within it.
+func (img *Image) readChunk(i int, p []byte, off int64) error {
+	c := img.chunks[i]
+	switch c.kind {
+	case chunkZeroFill, chunkIgnore:
+		clear(p)
+		return nil
+	case chunkRaw:
+		if off+int64(len(p)) > c.length {
+			return errors.Errorf("the raw chunk at sector %d is shorter than its sectors", c.sector)
+		}
+		if _, err := img.r.ReadAt(p, c.offset+off); err != nil {
+			return errors.Wrapf(err, "error when reading the chunk at sector %d", c.sector)
+		}
+		return nil
+	}
+
+	img.mu.Lock()
+	defer img.mu.Unlock()
+	if img.cachedChunk != i {
+		data, err := img.decompress(c)
+		if err != nil {
+			return err
+		}
+		img.cachedChunk, img.cachedData = i, data
+	}
+	copy(p, img.cachedData[off:])
+	return nil
+}
+
+// decompress returns the sectors of the compressed chunk.
+func (img *Image) decompress(c chunk) ([]byte, error) {
+	var r io.Reader = io.NewSectionReader(img.r, c.offset, c.length)
+	switch c.kind {
+	case chunkZlib:
+		zr, err := zlib.NewReader(r)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error when decompressing the zlib chunk at sector %d", c.sector)
+		}
+		defer zr.Close()
+		r = zr
+	case chunkBzip2:
+		r = bzip2.NewReader(r)
+	default:
+		name, ok := chunkTypeNames[c.kind]
+		if !ok {
+			name = fmt.Sprintf("0x%08x", c.kind)
+		}
+		return nil, errors.Errorf("the chunk at sector %d is of the unsupported type %s", c.sector, name)
+	}
+	data := make([]byte, c.sectorCount*SectorSize)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, errors.Wrapf(err, "error when decompressing the chunk at sector %d", c.sector)
+	}
+	return data, nil
+}
+
+// blockTable is the data of a blkx resource, named after its partition.
+type blockTable struct {
+	name string
+	data []byte
+}
+
+// blkxResources returns the blkx resources of the property list of a disk image.
+func blkxResources(xml []byte) ([]blockTable, error) {
+	value, err := plist.Decode(xml)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when decoding property list")
+	}
+	root, ok := value.(map[string]any)
+	if !ok {
+		return nil, errors.New("the property list is not a dictionary")
+	}
+	resourceFork, ok := root[resourceForkKey].(map[string]any)
+	if !ok {
+		return nil, errors.Errorf("%s is missing or not a dictionary", resourceForkKey)
+	}
+	list, ok := resourceFork[blkxType].([]any)
+	if !ok {
+		return nil, errors.Errorf("%s resources are missing", blkxType)
+	}
+	blockTables := make([]blockTable, 0, len(list))
+	for i, entry := range list {
+		resource, ok := entry.(map[string]any)
+		if !ok {
+			return nil, errors.Errorf("%s resource %d is not a dictionary", blkxType, i)
+		}
+		data, ok := resource["Data"].([]byte)
+		if !ok {
+			return nil, errors.Errorf("%s resource %d has no data", blkxType, i)
+		}
+		name, _ := resource["Name"].(string)
+		if name == "" {
+			name, _ = resource["CFName"].(string)
+		}
+		blockTables = append(blockTables, blockTable{name: name, data: data})
+	}
+	return blockTables, nil
+}
+
+// parseBlockTable parses the mish block table of a partition and returns the partition
+// and its chunks, whose data are located in the file, of the given size, from the data fork.
+// The values of the table are checked before any is used, since they come from the file.
+func parseBlockTable(data []byte, dataForkOffset uint64, size int64) (Partition, []chunk, error) {
+	if len(data) < blockTableHeaderSize || string(data[:4]) != blockTableSignature {
+		return Partition{}, nil, errors.New("not a mish block table")
+	}
+	firstSector := binary.BigEndian.Uint64(data[8:])
+	sectorCount := binary.BigEndian.Uint64(data[16:])
+	dataOffset := binary.BigEndian.Uint64(data[24:])
+	chunkCount := int(binary.BigEndian.Uint32(data[200:]))
+	if firstSector > maxSectors || sectorCount > maxSectors-firstSector {
+		return Partition{}, nil, errors.Errorf("the partition of %d sectors at sector %d is too large", sectorCount, firstSector)
+	}
+	if len(data) < blockTableHeaderSize+chunkCount*chunkSize {
+		return Partition{}, nil, errors.Errorf("the block table is too short for its %d chunks", chunkCount)
+	}
+	fileSize := uint64(size)
+	if dataForkOffset > fileSize || dataOffset > fileSize-dataForkOffset {
+		return Partition{}, nil, errors.New("the data of the partition lies outside of the file")
+	}
+	dataStart := dataForkOffset + dataOffset
+
+	var chunks []chunk
+	for i := range chunkCount {
+		entry := data[blockTableHeaderSize+i*chunkSize:]
+		kind := binary.BigEndian.Uint32(entry)
+		relativeSector := binary.BigEndian.Uint64(entry[8:])
+		count := binary.BigEndian.Uint64(entry[16:])
+		offset := binary.BigEndian.Uint64(entry[24:])
+		length := binary.BigEndian.Uint64(entry[32:])
+		if kind == chunkTerminator {
+			break
+		}
+		if kind == chunkComment || count == 0 {
+			continue
+		}
+		if relativeSector > sectorCount || count > sectorCount-relativeSector {
+			return Partition{}, nil, errors.Errorf("the chunk at sector %d lies outside of its partition", firstSector+min(relativeSector, maxSectors))
+		}
+		c := chunk{kind: kind, sector: int64(firstSector + relativeSector), sectorCount: int64(count)}
+		if kind != chunkZeroFill && kind != chunkIgnore {
+			// the sectors of stored chunks are read, or decompressed, in one piece.
+			if count > maxChunkSectors {
+				return Partition{}, nil, errors.Errorf("the chunk at sector %d has %d sectors, more than %d", c.sector, count, maxChunkSectors)
+			}
+			if offset > fileSize-dataStart || length > fileSize-dataStart-offset {
+				return Partition{}, nil, errors.Errorf("the chunk at sector %d lies outside of the file", c.sector)
+			}
+			c.offset, c.length = int64(dataStart+offset), int64(length)
+		}
+		chunks = append(chunks, c)
+	}
+	return Partition{Offset: int64(firstSector) * SectorSize, Size: int64(sectorCount) * SectorSize}, chunks, nil
+}
@@ -0,0 +1,297 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package udif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/macos-dmg-creator/plist"
+)
+
+// imageSectors is the number of sectors of testdata/image.dmg.
+const imageSectors = 24
+
+// expectedImage returns the uncompressed contents of testdata/image.dmg, written
+// by testdata/gen.go: each sector filled with its number, but the zero-fill
+// sectors 9 to 12 and the ignored sector 21.
+func expectedImage() []byte {
+	var data []byte
+	for s := range imageSectors {
+		if (s >= 9 && s <= 12) || s == 21 {
+			data = append(data, make([]byte, SectorSize)...)
+			continue
+		}
+		data = append(data, []byte(strings.Repeat(fmt.Sprintf("sector %04d ", s), SectorSize))[:SectorSize]...)
+	}
+	return data
+}
+
+// blockTableImage returns a disk image whose property list has a single blkx resource holding the data.
+func blockTableImage(t *testing.T, data []byte) []byte {
+	xml, err := plist.Encode(map[string]any{"resource-fork": map[string]any{"blkx": []any{
+		map[string]any{"Data": data, "Name": "disk image"},
+	}}})
+	require.NoError(t, err)
+	return image(string(xml), 0, len(xml))
+}
+
+// mishTable returns a mish block table of the partition starting at the given sector, made of the chunks.
+func mishTable(sector, sectorCount uint64, chunks ...[]uint64) []byte {
+	table := make([]byte, blockTableHeaderSize)
+	copy(table, blockTableSignature)
+	binary.BigEndian.PutUint64(table[8:], sector)
+	binary.BigEndian.PutUint64(table[16:], sectorCount)
+	binary.BigEndian.PutUint32(table[200:], uint32(len(chunks)))
+	for _, c := range chunks {
+		entry := make([]byte, chunkSize)
+		binary.BigEndian.PutUint32(entry, uint32(c[0]))
+		for i, value := range c[1:] {
+			binary.BigEndian.PutUint64(entry[8+8*i:], value)
+		}
+		table = append(table, entry...)
+	}
+	return table
+}
+
+func TestOpen(t *testing.T) {
+	img, err := Open(filepath.Join("testdata", "image.dmg"))
+	require.NoError(t, err)
+	defer img.Close()
+
+	require.Equal(t, int64(imageSectors*SectorSize), img.Size())
+	require.Equal(t, []Partition{
+		{Name: "Driver Descriptor Map (DDM : 0)", Offset: 0, Size: SectorSize},
+		{Name: "disk image (Apple_HFS : 1)", Offset: SectorSize, Size: 23 * SectorSize},
+	}, img.Partitions)
+
+	data, err := io.ReadAll(io.NewSectionReader(img, 0, img.Size()))
+	require.NoError(t, err)
+	require.Equal(t, expectedImage(), data)
+}
+
+func TestImageReadAt(t *testing.T) {
+	expected := expectedImage()
+	testCases := []struct {
+		name          string
+		off           int64
+		length        int
+		expectedN     int
+		expectedError error
+	}{
+		{
+			name:      "within a compressed chunk",
+			off:       2*SectorSize + 100,
+			length:    300,
+			expectedN: 300,
+		},
+		{
+			name:      "across chunks of every type",
+			off:       SectorSize - 10,
+			length:    22 * SectorSize,
+			expectedN: 22 * SectorSize,
+		},
+		{
+			name:      "from a zero-fill chunk into a bzip2 chunk",
+			off:       12*SectorSize + 1,
+			length:    SectorSize,
+			expectedN: SectorSize,
+		},
+		{
+			name:          "past the end of the image",
+			off:           23*SectorSize + 12,
+			length:        SectorSize,
+			expectedN:     SectorSize - 12,
+			expectedError: io.EOF,
+		},
+		{
+			name:          "at the end of the image",
+			off:           imageSectors * SectorSize,
+			length:        1,
+			expectedError: io.EOF,
+		},
+		{
+			name:          "negative offset",
+			off:           -1,
+			length:        1,
+			expectedError: errors.New("negative offset"),
+		},
+	}
+	img, err := Open(filepath.Join("testdata", "image.dmg"))
+	require.NoError(t, err)
+	defer img.Close()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := bytes.Repeat([]byte{0xff}, tc.length)
+			n, err := img.ReadAt(p, tc.off)
+			require.Equal(t, tc.expectedN, n)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+			} else if tc.expectedError != nil {
+				t.Fatalf(`expected error "%v", got nil`, tc.expectedError)
+			}
+			if n > 0 {
+				require.Equal(t, expected[tc.off:tc.off+int64(n)], p[:n])
+			}
+		})
+	}
+}
+
+func TestImageReadAtConcurrently(t *testing.T) {
+	expected := expectedImage()
+	img, err := Open(filepath.Join("testdata", "image.dmg"))
+	require.NoError(t, err)
+	defer img.Close()
+
+	var wg sync.WaitGroup
+	for s := range imageSectors {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p := make([]byte, SectorSize)
+			_, err := img.ReadAt(p, int64(s*SectorSize))
+			require.NoError(t, err)
+			require.Equal(t, expected[s*SectorSize:(s+1)*SectorSize], p)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestImageReadAtUnsupportedChunk(t *testing.T) {
+	img, err := Open(filepath.Join("testdata", "lzfse.dmg"))
+	require.NoError(t, err)
+	defer img.Close()
+
+	p := make([]byte, 2*SectorSize)
+	n, err := img.ReadAt(p, 0)
+	require.Equal(t, SectorSize, n)
+	require.EqualError(t, err, "the chunk at sector 1 is of the unsupported type LZFSE")
+}
+
+func TestNewImage(t *testing.T) {
+	testCases := []struct {
+		name          string
+		image         []byte
+		expectedError error
+	}{
+		{
+			name:          "no trailer",
+			image:         make([]byte, 1024),
+			expectedError: errors.New("not a UDIF disk image: koly trailer not found"),
+		},
+		{
+			name:          "no blkx resources",
+			image:         image("<plist><dict/></plist>", 0, len("<plist><dict/></plist>")),
+			expectedError: errors.New("resource-fork is missing or not a dictionary"),
+		},
+		{
+			name:          "blkx resource without data",
+			image:         image(imageXML, 0, len(imageXML)),
+			expectedError: errors.New("blkx resource 0 has no data"),
+		},
+		{
+			name:          "not a block table",
+			image:         blockTableImage(t, make([]byte, blockTableHeaderSize)),
+			expectedError: errors.New("error when reading the block table of [disk image]: not a mish block table"),
+		},
+		{
+			name:          "truncated block table",
+			image:         blockTableImage(t, mishTable(0, 1, []uint64{uint64(chunkZeroFill), 0, 1})[:blockTableHeaderSize]),
+			expectedError: errors.New("error when reading the block table of [disk image]: the block table is too short for its 1 chunks"),
+		},
+		{
+			name:          "chunk outside of its partition",
+			image:         blockTableImage(t, mishTable(0, 1, []uint64{uint64(chunkZeroFill), 0, 2})),
+			expectedError: errors.New("error when reading the block table of [disk image]: the chunk at sector 0 lies outside of its partition"),
+		},
+		{
+			name:          "chunk outside of the file",
+			image:         blockTableImage(t, mishTable(0, 1, []uint64{uint64(chunkZlib), 0, 1, 0, 1 << 20})),
+			expectedError: errors.New("error when reading the block table of [disk image]: the chunk at sector 0 lies outside of the file"),
+		},
+		{
+			name: "image too large",
+			image: func() []byte {
+				img := blockTableImage(t, mishTable(0, 1))
+				binary.BigEndian.PutUint64(img[len(img)-trailerSize+sectorCountOffset:], math.MaxUint64)
+				return img
+			}(),
+			expectedError: errors.New("the image of 18446744073709551615 sectors is too large"),
+		},
+		{
+			name:          "chunk too large",
+			image:         blockTableImage(t, mishTable(0, 1<<53, []uint64{uint64(chunkZlib), 0, 1 << 53, 0, 16})),
+			expectedError: errors.New("error when reading the block table of [disk image]: the chunk at sector 0 has 9007199254740992 sectors, more than 2048"),
+		},
+		{
+			name:          "partition too large",
+			image:         blockTableImage(t, mishTable(math.MaxUint64-1, 2)),
+			expectedError: errors.New("error when reading the block table of [disk image]: the partition of 2 sectors at sector 18446744073709551614 is too large"),
+		},
+		{
+			name:          "chunk sectors overflowing",
+			image:         blockTableImage(t, mishTable(0, 4, []uint64{uint64(chunkZlib), math.MaxUint64, 2, 0, 16})),
+			expectedError: fmt.Errorf("error when reading the block table of [disk image]: the chunk at sector %d lies outside of its partition", maxSectors),
+		},
+		{
+			name:          "chunk offset overflowing",
+			image:         blockTableImage(t, mishTable(0, 1, []uint64{uint64(chunkRaw), 0, 1, 16, math.MaxUint64 - 8})),
+			expectedError: errors.New("error when reading the block table of [disk image]: the chunk at sector 0 lies outside of the file"),
+		},
+		{
+			name: "overlapping chunks",
+			image: blockTableImage(t, mishTable(0, 2,
+				[]uint64{uint64(chunkZeroFill), 0, 2},
+				[]uint64{uint64(chunkIgnore), 1, 1},
+			)),
+			expectedError: errors.New("the chunks at sectors 0 and 1 overlap"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			img, err := NewImage(bytes.NewReader(tc.image), int64(len(tc.image)))
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+				return
+			}
+			if tc.expectedError != nil {
+				t.Fatalf(`expected error "%v", got nil`, tc.expectedError)
+			}
+			require.NotNil(t, img)
+		})
+	}
+}
+
+func TestOpenError(t *testing.T) {
+	defer func() { osOpen = os.Open }()
+	osOpen = func(name string) (*os.File, error) {
+		return nil, errors.New("some error")
+	}
+	_, err := Open("test.dmg")
+	require.EqualError(t, err, "error when opening [test.dmg]: some error")
+}
+
+func TestOpenNotAnImage(t *testing.T) {
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.WriteFile("test.dmg", []byte("koly"), 0o644))
+	_, err := Open("test.dmg")
+	require.EqualError(t, err, "error when reading [test.dmg]: not a UDIF disk image: the file is too small")
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+//go:build ignore
+
+// gen writes the small UDIF disk images used by the tests of the reader.
+// Since the standard library has no bzip2 compressor, bzip2(1) must be in the PATH.
+//
+//	cd udif && go run testdata/gen.go
+package main
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/tiagomelo/macos-dmg-creator/plist"
+)
+
+const sectorSize = 512
+
+// chunk types of the mish block tables.
+const (
+	zeroFill   uint32 = 0x00000000
+	raw        uint32 = 0x00000001
+	ignore     uint32 = 0x00000002
+	zlibType   uint32 = 0x80000005
+	bzip2Type  uint32 = 0x80000006
+	lzfse      uint32 = 0x80000007
+	comment    uint32 = 0x7ffffffe
+	terminator uint32 = 0xffffffff
+)
+
+// run is a run of sectors, relative to its partition, stored as one chunk.
+type run struct {
+	kind    uint32
+	sectors int
+}
+
+// partition is a partition of a fixture, made of runs of sectors.
+type partition struct {
+	name string
+	runs []run
+}
+
+// sectors returns the contents of count sectors starting at the given
+// sector of the uncompressed image, each filled with its number.
+func sectors(first, count int) []byte {
+	var data []byte
+	for s := first; s < first+count; s++ {
+		data = append(data, []byte(strings.Repeat(fmt.Sprintf("sector %04d ", s), sectorSize))[:sectorSize]...)
+	}
+	return data
+}
+
+func compress(kind uint32, data []byte) []byte {
+	switch kind {
+	case zlibType:
+		var buf bytes.Buffer
+		w := zlib.NewWriter(&buf)
+		w.Write(data)
+		w.Close()
+		return buf.Bytes()
+	case bzip2Type:
+		cmd := exec.Command("bzip2", "-c")
+		cmd.Stdin = bytes.NewReader(data)
+		out, err := cmd.Output()
+		if err != nil {
+			log.Fatal(err)
+		}
+		return out
+	}
+	return data
+}
+
+// write writes a UDIF disk image made of the partitions to the given path.
+func write(path string, partitions []partition) {
+	var dataFork []byte
+	var blkx []any
+	sector := 0
+	for id, p := range partitions {
+		count := 0
+		for _, r := range p.runs {
+			count += r.sectors
+		}
+		table := make([]byte, 204)
+		copy(table, "mish")
+		binary.BigEndian.PutUint32(table[4:], 1)
+		binary.BigEndian.PutUint64(table[8:], uint64(sector))
+		binary.BigEndian.PutUint64(table[16:], uint64(count))
+		binary.BigEndian.PutUint32(table[36:], uint32(id))
+		relative := 0
+		for _, r := range p.runs {
+			var stored []byte
+			switch r.kind {
+			case raw, zlibType, bzip2Type, lzfse:
+				stored = compress(r.kind, sectors(sector+relative, r.sectors))
+			}
+			entry := make([]byte, 40)
+			binary.BigEndian.PutUint32(entry, r.kind)
+			binary.BigEndian.PutUint64(entry[8:], uint64(relative))
+			binary.BigEndian.PutUint64(entry[16:], uint64(r.sectors))
+			binary.BigEndian.PutUint64(entry[24:], uint64(len(dataFork)))
+			binary.BigEndian.PutUint64(entry[32:], uint64(len(stored)))
+			table = append(table, entry...)
+			dataFork = append(dataFork, stored...)
+			relative += r.sectors
+		}
+		terminatorEntry := make([]byte, 40)
+		binary.BigEndian.PutUint32(terminatorEntry, terminator)
+		binary.BigEndian.PutUint64(terminatorEntry[8:], uint64(relative))
+		table = append(table, terminatorEntry...)
+		binary.BigEndian.PutUint32(table[200:], uint32(len(p.runs)+1))
+		blkx = append(blkx, map[string]any{
+			"Attributes": "0x0050",
+			"CFName":     p.name,
+			"Data":       table,
+			"ID":         fmt.Sprint(id - 1),
+			"Name":       p.name,
+		})
+		sector += count
+	}
+
+	xml, err := plist.Encode(map[string]any{"resource-fork": map[string]any{"blkx": blkx}})
+	if err != nil {
+		log.Fatal(err)
+	}
+	koly := make([]byte, 512)
+	copy(koly, "koly")
+	binary.BigEndian.PutUint32(koly[4:], 4)
+	binary.BigEndian.PutUint32(koly[8:], 512)
+	binary.BigEndian.PutUint32(koly[12:], 1)
+	binary.BigEndian.PutUint64(koly[32:], uint64(len(dataFork)))
+	binary.BigEndian.PutUint64(koly[216:], uint64(len(dataFork)))
+	binary.BigEndian.PutUint64(koly[224:], uint64(len(xml)))
+	binary.BigEndian.PutUint32(koly[488:], 1)
+	binary.BigEndian.PutUint64(koly[492:], uint64(sector))
+
+	image := append(append(dataFork, xml...), koly...)
+	if err := os.WriteFile(path, image, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func main() {
+	write(filepath.Join("testdata", "image.dmg"), []partition{
+		{name: "Driver Descriptor Map (DDM : 0)", runs: []run{{raw, 1}}},
+		{name: "disk image (Apple_HFS : 1)", runs: []run{
+			{zlibType, 8}, {zeroFill, 4}, {comment, 0}, {bzip2Type, 8}, {ignore, 1}, {raw, 2},
+		}},
+	})
+	write(filepath.Join("testdata", "lzfse.dmg"), []partition{
+		{name: "disk image (Apple_HFS : 1)", runs: []run{{raw, 1}, {lzfse, 1}}},
+	})
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package udif
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/tiagomelo/macos-dmg-creator/plist"
+)
+
+// for ease of unit testing.
+var osOpenFile = os.OpenFile
+
+const (
+	// trailerSize is the size of the koly trailer at the end of UDIF disk images.
+	trailerSize = 512
+
+	// trailerSignature is the signature the koly trailer starts with.
+	trailerSignature = "koly"
+
+	// offsets of the big-endian fields of the koly trailer locating the data fork,
+	// the property list, and holding the number of sectors of the uncompressed image.
+	dataForkOffsetOffset = 24
+	xmlOffsetOffset      = 216
+	xmlLengthOffset      = 224
+	sectorCountOffset    = 492
+
+	// resourceForkKey is the key of the property list holding the resources, by type.
+	resourceForkKey = "resource-fork"
+)
+
+// Resource is a classic Mac OS resource stored in the property list of a disk image.
+type Resource struct {
+	// ID is the identifier of the resource, unique within its type.
+	ID int16
+
+	// Name is the name of the resource.
+	Name string
+
+	// Attributes are the resource attributes, usually 0.
+	Attributes uint16
+
+	// Data is the content of the resource.
+	Data []byte
+}
+
+// Resources are resources, by four-character type, e.g. "LPic" or "TEXT".
+type Resources map[string][]Resource
+
+// trailer is the koly trailer of a UDIF disk image.
+type trailer []byte
+
+func (t trailer) dataForkOffset() uint64 { return binary.BigEndian.Uint64(t[dataForkOffsetOffset:]) }
+func (t trailer) xmlOffset() uint64      { return binary.BigEndian.Uint64(t[xmlOffsetOffset:]) }
+func (t trailer) xmlLength() uint64      { return binary.BigEndian.Uint64(t[xmlLengthOffset:]) }
+func (t trailer) sectorCount() uint64    { return binary.BigEndian.Uint64(t[sectorCountOffset:]) }
+
+func (t trailer) setXML(offset, length uint64) {
+	binary.BigEndian.PutUint64(t[xmlOffsetOffset:], offset)
+	binary.BigEndian.PutUint64(t[xmlLengthOffset:], length)
+}
+
+// SetResources writes the resources to the property list of the UDIF disk image at the
+// given path, replacing the resources of the same types. The rest of the image is kept.
+func SetResources(path string, resources Resources) error {
+	f, err := osOpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return errors.Wrapf(err, "error when opening [%s]", path)
+	}
+	defer f.Close()
+	if err := setResources(f, resources); err != nil {
+		return errors.Wrapf(err, "error when writing resources of [%s]", path)
+	}
+	return nil
+}
+
+// file is the part of *os.File used to rewrite disk images.
+type file interface {
+	io.ReaderAt
+	io.WriterAt
+	Stat() (os.FileInfo, error)
+	Truncate(size int64) error
+}
+
+// setResources rewrites the property list of the disk image with the resources, in place
+// of the old one if it is the last thing before the trailer, and rewrites the trailer after it.
+func setResources(f file, resources Resources) error {
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	size := uint64(info.Size())
+	t, err := readTrailer(f, size)
+	if err != nil {
+		return err
+	}
+	xml, err := readXML(f, t, size)
+	if err != nil {
+		return err
+	}
+	xmlOffset, xmlLength := t.xmlOffset(), t.xmlLength()
+	xml, err = withResources(xml, resources)
+	if err != nil {
+		return err
+	}
+
+	newOffset := size - trailerSize
+	if xmlOffset+xmlLength == newOffset {
+		newOffset = xmlOffset
+	}
+	t.setXML(newOffset, uint64(len(xml)))
+	if _, err := f.WriteAt(append(xml, t...), int64(newOffset)); err != nil {
+		return errors.Wrap(err, "error when writing property list")
+	}
+	return f.Truncate(int64(newOffset) + int64(len(xml)) + trailerSize)
+}
+
+// readTrailer reads the koly trailer at the end of the disk image of the given size.
+func readTrailer(r io.ReaderAt, size uint64) (trailer, error) {
+	if size < trailerSize {
+		return nil, errors.New("not a UDIF disk image: the file is too small")
+	}
+	t := make(trailer, trailerSize)
+	if _, err := r.ReadAt(t, int64(size-trailerSize)); err != nil {
+		return nil, errors.Wrap(err, "error when reading koly trailer")
+	}
+	if string(t[:4]) != trailerSignature {
+		return nil, errors.New("not a UDIF disk image: koly trailer not found")
+	}
+	return t, nil
+}
+
+// readXML reads the property list located by the trailer of the disk image of the given size.
+func readXML(r io.ReaderAt, t trailer, size uint64) ([]byte, error) {
+	xmlOffset, xmlLength := t.xmlOffset(), t.xmlLength()
+	if xmlLength == 0 || xmlOffset+xmlLength > size-trailerSize || xmlOffset+xmlLength < xmlOffset {
+		return nil, errors.Errorf("invalid property list location %d+%d", xmlOffset, xmlLength)
+	}
+	xml := make([]byte, xmlLength)
+	if _, err := r.ReadAt(xml, int64(xmlOffset)); err != nil {
+		return nil, errors.Wrap(err, "error when reading property list")
+	}
+	return xml, nil
+}
+
+// withResources returns the property list with the resources set.
+func withResources(xml []byte, resources Resources) ([]byte, error) {
+	value, err := plist.Decode(xml)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when decoding property list")
+	}
+	root, ok := value.(map[string]any)
+	if !ok {
+		return nil, errors.New("the property list is not a dictionary")
+	}
+	resourceFork, ok := root[resourceForkKey].(map[string]any)
+	if !ok {
+		if _, exists := root[resourceForkKey]; exists {
+			return nil, errors.Errorf("%s is not a dictionary", resourceForkKey)
+		}
+		resourceFork = map[string]any{}
+		root[resourceForkKey] = resourceFork
+	}
+	for resourceType, list := range resources {
+		entries := make([]any, 0, len(list))
+		for _, resource := range list {
+			entries = append(entries, map[string]any{
+				"Attributes": fmt.Sprintf("0x%04x", resource.Attributes),
+				"Data":       resource.Data,
+				"ID":         fmt.Sprint(resource.ID),
+				"Name":       resource.Name,
+			})
+		}
+		resourceFork[resourceType] = entries
+	}
+	encoded, err := plist.Encode(root)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when encoding property list")
+	}
+	return encoded, nil
+}
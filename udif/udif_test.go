@@ -0,0 +1,129 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package udif
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/macos-dmg-creator/plist"
+)
+
+// imageXML is the property list of the disk images built by the tests.
+const imageXML = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>resource-fork</key>
+	<dict>
+		<key>blkx</key>
+		<array>
+			<dict>
+				<key>Name</key>
+				<string>whole disk</string>
+			</dict>
+		</array>
+	</dict>
+</dict>
+</plist>
+`
+
+// image returns a disk image made of the contents followed by a koly
+// trailer locating the property list at xmlOffset, xmlLength bytes long.
+func image(contents string, xmlOffset, xmlLength int) []byte {
+	t := make(trailer, trailerSize)
+	copy(t, trailerSignature)
+	t.setXML(uint64(xmlOffset), uint64(xmlLength))
+	return append([]byte(contents), t...)
+}
+
+func TestSetResources(t *testing.T) {
+	const data = "disk image data"
+	testCases := []struct {
+		name               string
+		image              []byte
+		expectedXMLOffset  uint64
+		expectedDataLength int
+		expectedError      error
+	}{
+		{
+			name:               "property list before the trailer",
+			image:              image(data+imageXML, len(data), len(imageXML)),
+			expectedXMLOffset:  uint64(len(data)),
+			expectedDataLength: len(data),
+		},
+		{
+			name:               "property list elsewhere",
+			image:              image(imageXML+data, 0, len(imageXML)),
+			expectedXMLOffset:  uint64(len(imageXML) + len(data)),
+			expectedDataLength: len(imageXML) + len(data),
+		},
+		{
+			name:          "file too small",
+			image:         []byte("koly"),
+			expectedError: errors.New("error when writing resources of [test.dmg]: not a UDIF disk image: the file is too small"),
+		},
+		{
+			name:          "no trailer",
+			image:         make([]byte, 1024),
+			expectedError: errors.New("error when writing resources of [test.dmg]: not a UDIF disk image: koly trailer not found"),
+		},
+		{
+			name:          "invalid property list location",
+			image:         image(data+imageXML, 4096, len(imageXML)),
+			expectedError: fmt.Errorf("error when writing resources of [test.dmg]: invalid property list location 4096+%d", len(imageXML)),
+		},
+		{
+			name:          "invalid property list",
+			image:         image(data+"<plist>", len(data), len("<plist>")),
+			expectedError: errors.New("error when writing resources of [test.dmg]: error when decoding property list: XML syntax error on line 1: unexpected EOF"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			require.NoError(t, os.WriteFile("test.dmg", tc.image, 0o644))
+
+			err := SetResources("test.dmg", Resources{"TEXT": {{ID: 5000, Name: "English SLA", Data: []byte("License")}}})
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+				return
+			}
+			if tc.expectedError != nil {
+				t.Fatalf(`expected error "%v", got nil`, tc.expectedError)
+			}
+
+			img, err := os.ReadFile("test.dmg")
+			require.NoError(t, err)
+			require.Equal(t, tc.image[:tc.expectedDataLength], img[:tc.expectedDataLength])
+			tr := trailer(img[len(img)-trailerSize:])
+			require.Equal(t, trailerSignature, string(tr[:4]))
+			require.Equal(t, tc.expectedXMLOffset, tr.xmlOffset())
+			require.Equal(t, uint64(len(img)-trailerSize)-tc.expectedXMLOffset, tr.xmlLength())
+
+			value, err := plist.Decode(img[tr.xmlOffset() : tr.xmlOffset()+tr.xmlLength()])
+			require.NoError(t, err)
+			require.Equal(t, map[string]any{
+				"blkx": []any{map[string]any{"Name": "whole disk"}},
+				"TEXT": []any{map[string]any{"Attributes": "0x0000", "Data": []byte("License"), "ID": "5000", "Name": "English SLA"}},
+			}, value.(map[string]any)[resourceForkKey])
+		})
+	}
+}
+
+func TestSetResourcesOpenError(t *testing.T) {
+	defer func() { osOpenFile = os.OpenFile }()
+	osOpenFile = func(name string, flag int, perm os.FileMode) (*os.File, error) {
+		return nil, errors.New("some error")
+	}
+	err := SetResources("test.dmg", Resources{})
+	require.EqualError(t, err, "error when opening [test.dmg]: some error")
+}
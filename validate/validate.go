@@ -60,7 +60,7 @@ func Check(val any) error {
 		var fields FieldErrors
 		for _, verror := range verrors {
 			field := FieldError{
-				Field: verror.Field(),
+				Field: fieldPath(verror),
 				Error: verror.Translate(translator),
 			}
 			fields = append(fields, field)
@@ -69,3 +69,12 @@ func Check(val any) error {
 	}
 	return nil
 }
+
+// fieldPath returns the path to the field that failed validation
+// without the name of the validated struct, e.g. "Items[0].Name".
+func fieldPath(verror validator.FieldError) string {
+	if _, path, ok := strings.Cut(verror.Namespace(), "."); ok {
+		return path
+	}
+	return verror.Field()
+}
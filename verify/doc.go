@@ -0,0 +1,3 @@
+// Package verify provides functionality to check a created disk image: its
+// checksums, with hdiutil, and the contents of its volume against the expected ones.
+package verify
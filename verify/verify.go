@@ -0,0 +1,188 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package verify
+
+import (
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tiagomelo/macos-dmg-creator/bundle"
+	"github.com/tiagomelo/macos-dmg-creator/hdiutil"
+	"github.com/tiagomelo/macos-dmg-creator/plist"
+)
+
+// for ease of unit testing.
+var (
+	hdiutilVerifyDMG = hdiutil.VerifyDMG
+	hdiutilAttachDMG = hdiutil.AttachDMG
+	hdiutilDetachDMG = hdiutil.DetachDMG
+	osMkdirTemp      = os.MkdirTemp
+)
+
+const (
+	// applicationsSymlinkName is the name of the symlink to the Applications folder.
+	applicationsSymlinkName = "Applications"
+
+	// applicationsDir is the target of the Applications symlink.
+	applicationsDir = "/Applications"
+)
+
+// Expected describes the contents expected in the volume of a disk image.
+type Expected struct {
+	// AppBundleName is the name of the application bundle expected at the top level
+	// of the volume, next to the Applications symlink, e.g. MyApp.app.
+	// When empty, neither is checked.
+	AppBundleName string
+
+	// InfoPlist are the values expected in the Info.plist file of the application, by key.
+	InfoPlist map[string]string
+
+	// Files are the names of the other files and directories expected
+	// at the top level of the volume, e.g. Install.command.
+	Files []string
+}
+
+// Mismatch is a difference between the contents of the disk image and the expected ones.
+type Mismatch struct {
+	// Path is the path of the file the mismatch was found in, inside the disk image.
+	Path string
+
+	// Message describes the mismatch.
+	Message string
+}
+
+// String returns the mismatch as a line of text.
+func (m Mismatch) String() string {
+	return fmt.Sprintf("[%s] %s", m.Path, m.Message)
+}
+
+// VerificationError is returned when the disk image fails verification.
+// It lists every mismatch that was found.
+type VerificationError struct {
+	// Path is the path of the disk image.
+	Path string
+
+	// Mismatches are the mismatches found in the disk image.
+	Mismatches []Mismatch
+}
+
+// Error implements the error interface.
+func (e *VerificationError) Error() string {
+	mismatches := make([]string, len(e.Mismatches))
+	for i, mismatch := range e.Mismatches {
+		mismatches[i] = mismatch.String()
+	}
+	return fmt.Sprintf("DMG [%s] failed verification: %s", e.Path, strings.Join(mismatches, "; "))
+}
+
+// IsVerificationError checks if an error of type VerificationError exists.
+func IsVerificationError(err error) bool {
+	var verr *VerificationError
+	return errors.As(err, &verr)
+}
+
+// DMG verifies the checksums of the disk image at the given path, then attaches it
+// read-only at a private mount point and checks that its volume holds the expected
+// contents. It returns a *VerificationError listing the mismatches when it fails
+// verification, and other errors when it cannot be verified, e.g. attached.
+func DMG(path string, expected *Expected) (err error) {
+	// the contents of a corrupted disk image are not worth checking.
+	if err := hdiutilVerifyDMG(path); err != nil {
+		return &VerificationError{Path: path, Mismatches: []Mismatch{{Path: path, Message: err.Error()}}}
+	}
+
+	mountPoint, err := osMkdirTemp("", "createdmg-verify-")
+	if err != nil {
+		return errors.Wrap(err, "error when creating mount point")
+	}
+	defer os.Remove(mountPoint)
+	if err := hdiutilAttachDMG(path, mountPoint); err != nil {
+		return err
+	}
+	defer func() {
+		if detachErr := hdiutilDetachDMG(mountPoint); detachErr != nil && err == nil {
+			err = detachErr
+		}
+	}()
+
+	mismatches := checkContents(mountPoint, expected)
+	if len(mismatches) == 0 {
+		return nil
+	}
+	// report the paths inside the disk image rather than inside the temporary mount point.
+	for i, mismatch := range mismatches {
+		if rel, err := filepath.Rel(mountPoint, mismatch.Path); err == nil {
+			mismatches[i].Path = path + ":" + rel
+		}
+	}
+	return &VerificationError{Path: path, Mismatches: mismatches}
+}
+
+// checkContents checks the contents of the volume mounted at the given mount point.
+func checkContents(mountPoint string, expected *Expected) []Mismatch {
+	var mismatches []Mismatch
+	if expected.AppBundleName != "" {
+		mismatches = append(mismatches, checkApplicationsSymlink(mountPoint)...)
+		mismatches = append(mismatches, checkAppBundle(filepath.Join(mountPoint, expected.AppBundleName), expected.InfoPlist)...)
+	}
+	for _, name := range expected.Files {
+		filePath := filepath.Join(mountPoint, name)
+		if _, err := os.Lstat(filePath); err != nil {
+			mismatches = append(mismatches, Mismatch{Path: filePath, Message: "the file is missing"})
+		}
+	}
+	return mismatches
+}
+
+// checkApplicationsSymlink checks that the Applications symlink points to /Applications.
+func checkApplicationsSymlink(mountPoint string) []Mismatch {
+	symlinkPath := filepath.Join(mountPoint, applicationsSymlinkName)
+	target, err := os.Readlink(symlinkPath)
+	switch {
+	case err != nil:
+		return []Mismatch{{Path: symlinkPath, Message: "the Applications symlink is missing"}}
+	case target != applicationsDir:
+		return []Mismatch{{Path: symlinkPath, Message: fmt.Sprintf("the Applications symlink points to [%s] instead of [%s]", target, applicationsDir)}}
+	}
+	return nil
+}
+
+// checkAppBundle checks that the application bundle exists and that
+// its Info.plist file declares the expected values.
+func checkAppBundle(appBundlePath string, expectedInfo map[string]string) []Mismatch {
+	if stat, err := os.Stat(appBundlePath); err != nil || !stat.IsDir() {
+		return []Mismatch{{Path: appBundlePath, Message: "the application bundle is missing"}}
+	}
+	infoPlistPath := filepath.Join(appBundlePath, bundle.InfoPlistPath)
+	value, err := plist.DecodeFile(infoPlistPath)
+	if err != nil {
+		message := err.Error()
+		if errors.Is(err, fs.ErrNotExist) {
+			message = "Info.plist does not exist"
+		}
+		return []Mismatch{{Path: infoPlistPath, Message: message}}
+	}
+	info, ok := value.(map[string]any)
+	if !ok {
+		return []Mismatch{{Path: infoPlistPath, Message: "Info.plist is not a dictionary"}}
+	}
+	var mismatches []Mismatch
+	for _, key := range slices.Sorted(maps.Keys(expectedInfo)) {
+		actual, ok := info[key]
+		switch {
+		case !ok:
+			mismatches = append(mismatches, Mismatch{Path: infoPlistPath, Message: fmt.Sprintf("%s is not declared, expected [%s]", key, expectedInfo[key])})
+		case fmt.Sprint(actual) != expectedInfo[key]:
+			mismatches = append(mismatches, Mismatch{Path: infoPlistPath, Message: fmt.Sprintf("%s is [%v], expected [%s]", key, actual, expectedInfo[key])})
+		}
+	}
+	return mismatches
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/macos-dmg-creator/plist"
+)
+
+func TestDMG(t *testing.T) {
+	testCases := []struct {
+		name          string
+		expected      *Expected
+		populate      func(t *testing.T, mountPoint string)
+		verifyErr     error
+		attachErr     error
+		detachErr     error
+		wantAttached  bool
+		expectedError error
+	}{
+		{
+			name: "happy path",
+			expected: &Expected{
+				AppBundleName: "MyApp.app",
+				InfoPlist:     map[string]string{"CFBundleIdentifier": "com.example.myapp", "CFBundleVersion": "42"},
+				Files:         []string{".background"},
+			},
+			populate: func(t *testing.T, mountPoint string) {
+				createBundle(t, mountPoint, map[string]any{"CFBundleIdentifier": "com.example.myapp", "CFBundleVersion": "42"})
+				require.NoError(t, os.Symlink("/Applications", filepath.Join(mountPoint, "Applications")))
+				require.NoError(t, os.Mkdir(filepath.Join(mountPoint, ".background"), 0o755))
+			},
+			wantAttached: true,
+		},
+		{
+			name: "mismatches",
+			expected: &Expected{
+				AppBundleName: "MyApp.app",
+				InfoPlist:     map[string]string{"CFBundleIdentifier": "com.example.myapp", "CFBundleVersion": "42"},
+				Files:         []string{"Install.command"},
+			},
+			populate: func(t *testing.T, mountPoint string) {
+				createBundle(t, mountPoint, map[string]any{"CFBundleIdentifier": "com.example.other"})
+				require.NoError(t, os.Symlink("/tmp", filepath.Join(mountPoint, "Applications")))
+			},
+			wantAttached: true,
+			expectedError: &VerificationError{Path: "MyApp.dmg", Mismatches: []Mismatch{
+				{Path: "MyApp.dmg:Applications", Message: "the Applications symlink points to [/tmp] instead of [/Applications]"},
+				{Path: "MyApp.dmg:MyApp.app/Contents/Info.plist", Message: "CFBundleIdentifier is [com.example.other], expected [com.example.myapp]"},
+				{Path: "MyApp.dmg:MyApp.app/Contents/Info.plist", Message: "CFBundleVersion is not declared, expected [42]"},
+				{Path: "MyApp.dmg:Install.command", Message: "the file is missing"},
+			}},
+		},
+		{
+			name:     "missing application bundle and symlink",
+			expected: &Expected{AppBundleName: "MyApp.app"},
+			populate: func(t *testing.T, mountPoint string) {
+				require.NoError(t, os.WriteFile(filepath.Join(mountPoint, "MyApp.app"), nil, 0o644))
+			},
+			wantAttached: true,
+			expectedError: &VerificationError{Path: "MyApp.dmg", Mismatches: []Mismatch{
+				{Path: "MyApp.dmg:Applications", Message: "the Applications symlink is missing"},
+				{Path: "MyApp.dmg:MyApp.app", Message: "the application bundle is missing"},
+			}},
+		},
+		{
+			name:     "missing Info.plist",
+			expected: &Expected{AppBundleName: "MyApp.app"},
+			populate: func(t *testing.T, mountPoint string) {
+				require.NoError(t, os.MkdirAll(filepath.Join(mountPoint, "MyApp.app", "Contents"), 0o755))
+				require.NoError(t, os.Symlink("/Applications", filepath.Join(mountPoint, "Applications")))
+			},
+			wantAttached: true,
+			expectedError: &VerificationError{Path: "MyApp.dmg", Mismatches: []Mismatch{
+				{Path: "MyApp.dmg:MyApp.app/Contents/Info.plist", Message: "Info.plist does not exist"},
+			}},
+		},
+		{
+			name:      "checksum mismatch",
+			expected:  &Expected{AppBundleName: "MyApp.app"},
+			verifyErr: errors.New("error when verifying dmg MyApp.dmg: some error"),
+			expectedError: &VerificationError{Path: "MyApp.dmg", Mismatches: []Mismatch{
+				{Path: "MyApp.dmg", Message: "error when verifying dmg MyApp.dmg: some error"},
+			}},
+		},
+		{
+			name:          "error when attaching",
+			expected:      &Expected{AppBundleName: "MyApp.app"},
+			attachErr:     errors.New("error when attaching dmg MyApp.dmg: some error"),
+			expectedError: errors.New("error when attaching dmg MyApp.dmg: some error"),
+		},
+		{
+			name:          "error when detaching",
+			expected:      &Expected{},
+			populate:      func(t *testing.T, mountPoint string) {},
+			detachErr:     errors.New("error when detaching dmg: some error"),
+			wantAttached:  true,
+			expectedError: errors.New("error when detaching dmg: some error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			osMkdirTemp = func(dir, pattern string) (string, error) {
+				return os.MkdirTemp(tmpDir, pattern)
+			}
+			hdiutilVerifyDMG = func(dmgPath string) error {
+				require.Equal(t, "MyApp.dmg", dmgPath)
+				return tc.verifyErr
+			}
+			var attachedAt, detachedAt string
+			hdiutilAttachDMG = func(dmgPath, mountPoint string) error {
+				require.Equal(t, "MyApp.dmg", dmgPath)
+				if tc.attachErr != nil {
+					return tc.attachErr
+				}
+				attachedAt = mountPoint
+				tc.populate(t, mountPoint)
+				return nil
+			}
+			hdiutilDetachDMG = func(mountPoint string) error {
+				detachedAt = mountPoint
+				return tc.detachErr
+			}
+
+			err := DMG("MyApp.dmg", tc.expected)
+			require.Equal(t, attachedAt, detachedAt)
+			require.Equal(t, tc.wantAttached, attachedAt != "")
+			if tc.expectedError != nil {
+				require.EqualError(t, err, tc.expectedError.Error())
+				if verr, ok := tc.expectedError.(*VerificationError); ok {
+					require.Equal(t, verr, err)
+				}
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestVerificationError(t *testing.T) {
+	err := &VerificationError{Path: "MyApp.dmg", Mismatches: []Mismatch{
+		{Path: "MyApp.dmg:Applications", Message: "the Applications symlink is missing"},
+		{Path: "MyApp.dmg:MyApp.app", Message: "the application bundle is missing"},
+	}}
+	require.Equal(t, "DMG [MyApp.dmg] failed verification: [MyApp.dmg:Applications] the Applications symlink is missing; [MyApp.dmg:MyApp.app] the application bundle is missing", err.Error())
+	require.True(t, IsVerificationError(errors.Wrap(err, "error when verifying DMG")))
+	require.False(t, IsVerificationError(errors.New("some error")))
+}
+
+// createBundle creates the bundle MyApp.app in the given directory,
+// with an Info.plist file holding the given entries.
+func createBundle(t *testing.T, dir string, info map[string]any) {
+	t.Helper()
+	appPath := filepath.Join(dir, "MyApp.app")
+	require.NoError(t, os.MkdirAll(filepath.Join(appPath, "Contents/MacOS"), 0o755))
+	data, err := plist.Encode(info)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(appPath, "Contents/Info.plist"), data, 0o644))
+}
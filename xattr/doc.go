@@ -0,0 +1,3 @@
+// Package xattr reads and writes the Finder information stored in the extended
+// attributes of files and directories, like the SetFile command-line tool does.
+package xattr
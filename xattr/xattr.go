@@ -0,0 +1,95 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package xattr
+
+import (
+	"bytes"
+	"encoding/binary"
+	"slices"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// FinderInfoName is the name of the extended attribute holding the Finder information.
+	FinderInfoName = "com.apple.FinderInfo"
+
+	// finderInfoSize is the size of the Finder information.
+	finderInfoSize = 32
+
+	// finderFlagsOffset is the offset of the big-endian Finder flags
+	// in the Finder information of both files and directories.
+	finderFlagsOffset = 8
+
+	// hasCustomIconFlag is the Finder flag telling that the file, or the volume,
+	// has a custom icon: an icon resource for files and folders,
+	// or the .VolumeIcon.icns file at the root of volumes.
+	hasCustomIconFlag = 0x0400
+)
+
+// HasCustomIcon tells whether the custom icon Finder flag of the file or directory is set.
+func HasCustomIcon(path string) (bool, error) {
+	info, err := finderInfo(path)
+	if err != nil {
+		return false, err
+	}
+	return binary.BigEndian.Uint16(info[finderFlagsOffset:])&hasCustomIconFlag != 0, nil
+}
+
+// SetCustomIcon sets the custom icon Finder flag of the file or directory,
+// keeping the rest of its Finder information.
+func SetCustomIcon(path string) error {
+	info, err := finderInfo(path)
+	if err != nil {
+		return err
+	}
+	flags := binary.BigEndian.Uint16(info[finderFlagsOffset:])
+	binary.BigEndian.PutUint16(info[finderFlagsOffset:], flags|hasCustomIconFlag)
+	if err := unixSetxattr(path, FinderInfoName, info, 0); err != nil {
+		return errors.Wrapf(err, "error when writing Finder information of [%s]", path)
+	}
+	return nil
+}
+
+// finderInfo returns the Finder information of the file or directory,
+// all zeros when it has none.
+func finderInfo(path string) ([]byte, error) {
+	names, err := listxattr(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when listing extended attributes of [%s]", path)
+	}
+	info := make([]byte, finderInfoSize)
+	if !slices.Contains(names, FinderInfoName) {
+		return info, nil
+	}
+	n, err := unixGetxattr(path, FinderInfoName, info)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when reading Finder information of [%s]", path)
+	}
+	if n != finderInfoSize {
+		return nil, errors.Errorf("Finder information of [%s] is %d bytes long, expected %d", path, n, finderInfoSize)
+	}
+	return info, nil
+}
+
+// listxattr returns the names of the extended attributes of the file or directory.
+func listxattr(path string) ([]string, error) {
+	size, err := unixListxattr(path, nil)
+	if err != nil || size == 0 {
+		return nil, err
+	}
+	buf := make([]byte, size)
+	n, err := unixListxattr(path, buf)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for name := range bytes.SplitSeq(buf[:n], []byte{0}) {
+		if len(name) > 0 {
+			names = append(names, string(name))
+		}
+	}
+	return names, nil
+}
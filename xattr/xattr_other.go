@@ -0,0 +1,23 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+//go:build !darwin && !freebsd && !linux && !netbsd
+
+package xattr
+
+import (
+	"runtime"
+
+	"github.com/pkg/errors"
+)
+
+// errUnsupported is returned on the systems without extended attributes support.
+var errUnsupported = errors.Errorf("extended attributes are not supported on %s", runtime.GOOS)
+
+// for ease of unit testing.
+var (
+	unixListxattr = func(path string, dest []byte) (int, error) { return 0, errUnsupported }
+	unixGetxattr  = func(path, attr string, dest []byte) (int, error) { return 0, errUnsupported }
+	unixSetxattr  = func(path, attr string, data []byte, flags int) error { return errUnsupported }
+)
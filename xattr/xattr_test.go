@@ -0,0 +1,164 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package xattr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetCustomIcon(t *testing.T) {
+	testCases := []struct {
+		name          string
+		attrs         *mockAttrs
+		expectedInfo  []byte
+		expectedError error
+	}{
+		{
+			name:  "no Finder information",
+			attrs: &mockAttrs{values: map[string][]byte{"com.apple.quarantine": []byte("0081")}},
+			expectedInfo: []byte{
+				0, 0, 0, 0, 0, 0, 0, 0, 0x04, 0, 0, 0, 0, 0, 0, 0,
+				0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+			},
+		},
+		{
+			name: "existing Finder information",
+			attrs: &mockAttrs{values: map[string][]byte{FinderInfoName: {
+				'i', 'c', 'n', 's', 0, 0, 0, 0, 0x40, 0x01, 0, 0, 0, 0, 0, 0,
+				0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+			}}},
+			expectedInfo: []byte{
+				'i', 'c', 'n', 's', 0, 0, 0, 0, 0x44, 0x01, 0, 0, 0, 0, 0, 0,
+				0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+			},
+		},
+		{
+			name:          "invalid Finder information",
+			attrs:         &mockAttrs{values: map[string][]byte{FinderInfoName: {1, 2, 3}}},
+			expectedError: errors.New("Finder information of [/Volumes/MyApp] is 3 bytes long, expected 32"),
+		},
+		{
+			name:          "error listing attributes",
+			attrs:         &mockAttrs{listErr: errors.New("some error")},
+			expectedError: errors.New("error when listing extended attributes of [/Volumes/MyApp]: some error"),
+		},
+		{
+			name:          "error reading Finder information",
+			attrs:         &mockAttrs{values: map[string][]byte{FinderInfoName: make([]byte, 32)}, getErr: errors.New("some error")},
+			expectedError: errors.New("error when reading Finder information of [/Volumes/MyApp]: some error"),
+		},
+		{
+			name:          "error writing Finder information",
+			attrs:         &mockAttrs{setErr: errors.New("some error")},
+			expectedError: errors.New("error when writing Finder information of [/Volumes/MyApp]: some error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.attrs.install()
+
+			err := SetCustomIcon("/Volumes/MyApp")
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+				return
+			}
+			if tc.expectedError != nil {
+				t.Fatalf(`expected error "%v", got nil`, tc.expectedError)
+			}
+			require.Equal(t, tc.expectedInfo, tc.attrs.values[FinderInfoName])
+
+			hasCustomIcon, err := HasCustomIcon("/Volumes/MyApp")
+			require.NoError(t, err)
+			require.True(t, hasCustomIcon)
+		})
+	}
+}
+
+func TestHasCustomIcon(t *testing.T) {
+	testCases := []struct {
+		name          string
+		attrs         *mockAttrs
+		expected      bool
+		expectedError error
+	}{
+		{
+			name:  "no extended attributes",
+			attrs: &mockAttrs{},
+		},
+		{
+			name:  "flag not set",
+			attrs: &mockAttrs{values: map[string][]byte{FinderInfoName: make([]byte, 32)}},
+		},
+		{
+			name:          "error",
+			attrs:         &mockAttrs{listErr: errors.New("some error")},
+			expectedError: errors.New("error when listing extended attributes of [/Volumes/MyApp]: some error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.attrs.install()
+
+			hasCustomIcon, err := HasCustomIcon("/Volumes/MyApp")
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+				return
+			}
+			if tc.expectedError != nil {
+				t.Fatalf(`expected error "%v", got nil`, tc.expectedError)
+			}
+			require.Equal(t, tc.expected, hasCustomIcon)
+		})
+	}
+}
+
+// mockAttrs holds the extended attributes of a single file in memory.
+type mockAttrs struct {
+	values  map[string][]byte
+	listErr error
+	getErr  error
+	setErr  error
+}
+
+func (m *mockAttrs) install() {
+	unixListxattr = func(path string, dest []byte) (int, error) {
+		if m.listErr != nil {
+			return 0, m.listErr
+		}
+		var names []byte
+		for name := range m.values {
+			names = append(append(names, name...), 0)
+		}
+		if dest == nil {
+			return len(names), nil
+		}
+		return copy(dest, names), nil
+	}
+	unixGetxattr = func(path, attr string, dest []byte) (int, error) {
+		if m.getErr != nil {
+			return 0, m.getErr
+		}
+		return copy(dest, m.values[attr]), nil
+	}
+	unixSetxattr = func(path, attr string, data []byte, flags int) error {
+		if m.setErr != nil {
+			return m.setErr
+		}
+		if m.values == nil {
+			m.values = map[string][]byte{}
+		}
+		m.values[attr] = append([]byte(nil), data...)
+		return nil
+	}
+}
@@ -0,0 +1,16 @@
+// Copyright (c) 2025 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+//go:build darwin || freebsd || linux || netbsd
+
+package xattr
+
+import "golang.org/x/sys/unix"
+
+// for ease of unit testing.
+var (
+	unixListxattr = unix.Listxattr
+	unixGetxattr  = unix.Getxattr
+	unixSetxattr  = unix.Setxattr
+)